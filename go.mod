@@ -4,7 +4,7 @@ go 1.23.0
 
 require (
 	github.com/google/go-github/v66 v66.0.0
-	golang.org/x/oauth2 v0.30.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require github.com/google/go-querystring v1.1.0 // indirect
@@ -0,0 +1,420 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	githubapi "github.com/jotajotape/github-go-server-mcp/internal/github"
+)
+
+// Config es el archivo de configuración con perfiles con nombre:
+//
+//	default_profile: personal
+//	profiles:
+//	  personal:
+//	    token: {env: GITHUB_TOKEN_PERSONAL}
+//	    owner: jotajotape
+//	    workspace: C:\repos\mi-proyecto
+//	    remote: upstream
+//	  empresa:
+//	    token: {command: "gh auth token --hostname github.empresa.com"}
+//	    base_url: https://github.empresa.com/api/v3/
+//	    ca_bundle: ~/certs/empresa-ca.pem
+//	    proxy: http://proxy.empresa.com:3128
+//	    tools: ["git_*", "github_list_*"]
+//	  bot:
+//	    app: {id: 123456, private_key: ~/keys/bot.pem}
+type Config struct {
+	DefaultProfile string             `yaml:"default_profile"`
+	Profiles       map[string]Profile `yaml:"profiles"`
+
+	unknownFields []Problem // claves desconocidas (erratas), que Check informa con su línea
+}
+
+// Profile es la configuración de una cuenta de GitHub
+type Profile struct {
+	Token      TokenSource `yaml:"token"`
+	Owner      string      `yaml:"owner"`       // owner por defecto de las herramientas github_*
+	Repo       string      `yaml:"repo"`        // repositorio por defecto
+	BaseBranch string      `yaml:"base_branch"` // rama base por defecto (PRs, commits por API)
+	Workspace  string      `yaml:"workspace"`   // workspace Git inicial
+	Remote     string      `yaml:"remote"`      // remoto del que se deducen owner/repo y al que van push, pull y tags (default: origin)
+	Tools      []string    `yaml:"tools"`       // herramientas habilitadas (admite patrones: git_*); vacío = todas
+	BaseURL    string      `yaml:"base_url"`    // URL de la API (GitHub Enterprise)
+	UploadURL  string      `yaml:"upload_url"`  // URL de subidas (por defecto se deriva de base_url)
+	CABundle   string      `yaml:"ca_bundle"`   // archivo PEM con CAs adicionales
+	Proxy      string      `yaml:"proxy"`       // URL del proxy (por defecto HTTPS_PROXY/HTTP_PROXY)
+	App        *AppConfig  `yaml:"app"`         // autenticación como GitHub App (excluye token)
+}
+
+// AppConfig autentica el perfil como GitHub App con tokens de instalación
+type AppConfig struct {
+	ID             int64  `yaml:"id"`
+	PrivateKey     string `yaml:"private_key"`     // archivo PEM con la clave privada de la App
+	InstallationID int64  `yaml:"installation_id"` // instalación por defecto (opcional)
+}
+
+// TokenSource indica de dónde se obtiene el token. Solo debe indicarse una fuente;
+// sin ninguna se usa la variable de entorno GITHUB_TOKEN.
+type TokenSource struct {
+	Env     string `yaml:"env"`     // nombre de la variable de entorno
+	File    string `yaml:"file"`    // archivo con el token
+	Command string `yaml:"command"` // comando que imprime el token (p. ej. "gh auth token")
+}
+
+// DefaultTokenEnv es la variable de entorno usada cuando el perfil no indica fuente de token
+const DefaultTokenEnv = "GITHUB_TOKEN"
+
+// Problem es un error de configuración detectado por Check
+type Problem struct {
+	Profile string
+	Field   string
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Profile == "" {
+		return fmt.Sprintf("%s: %s", p.Field, p.Message)
+	}
+	return fmt.Sprintf("profile %s: %s: %s", p.Profile, p.Field, p.Message)
+}
+
+var (
+	ownerPattern = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+	repoPattern  = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+	// unknownFieldPattern reconoce el error de yaml.v3 para una clave que no existe en el struct
+	unknownFieldPattern = regexp.MustCompile(`^line (\d+): field (.+) not found in type `)
+)
+
+// DefaultPath devuelve la ruta por defecto del archivo (~/.config/github-mcp/config.yaml en Linux)
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "github-mcp", "config.yaml")
+}
+
+// Load lee el archivo de configuración
+func Load(file string) (*Config, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err = decoder.Decode(&cfg)
+
+	// Las claves desconocidas no impiden leer el resto: se guardan para que Check las informe
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		for _, message := range typeErr.Errors {
+			match := unknownFieldPattern.FindStringSubmatch(message)
+			if match == nil {
+				return nil, fmt.Errorf("invalid config %s: %v", file, err)
+			}
+			cfg.unknownFields = append(cfg.unknownFields, Problem{Field: "line " + match[1], Message: fmt.Sprintf("unknown key %q", match[2])})
+		}
+		err = nil
+	}
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid config %s: %v", file, err)
+	}
+	return &cfg, nil
+}
+
+// Profile devuelve el perfil indicado, o el perfil por defecto si name está vacío
+func (c *Config) Profile(name string) (Profile, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("profile %q not found in config (available: %s)", name, strings.Join(c.ProfileNames(), ", "))
+	}
+	return profile, nil
+}
+
+// ProfileNames devuelve los nombres de los perfiles ordenados
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolveToken obtiene el token desde la fuente configurada
+func (t TokenSource) ResolveToken() (string, error) {
+	switch {
+	case t.File != "":
+		data, err := os.ReadFile(expandHome(t.File))
+		if err != nil {
+			return "", fmt.Errorf("reading token file: %v", err)
+		}
+		return nonEmptyToken(string(data), "token file "+t.File)
+
+	case t.Command != "":
+		args := strings.Fields(t.Command)
+		if len(args) == 0 {
+			return "", fmt.Errorf("token command is empty")
+		}
+		output, err := exec.Command(args[0], args[1:]...).Output()
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				return "", fmt.Errorf("token command failed: %v: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+			}
+			return "", fmt.Errorf("token command failed: %v", err)
+		}
+		return nonEmptyToken(string(output), "token command")
+
+	default:
+		name := t.Env
+		if name == "" {
+			name = DefaultTokenEnv
+		}
+		return nonEmptyToken(os.Getenv(name), name)
+	}
+}
+
+// Describe indica la fuente del token para los logs (sin revelar el token)
+func (t TokenSource) Describe() string {
+	switch {
+	case t.File != "":
+		return "file " + t.File
+	case t.Command != "":
+		return "command " + commandName(t.Command)
+	case t.Env != "":
+		return "env " + t.Env
+	default:
+		return "env " + DefaultTokenEnv
+	}
+}
+
+func nonEmptyToken(token, source string) (string, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", fmt.Errorf("%s is empty", source)
+	}
+	return token, nil
+}
+
+// ToolEnabled indica si el perfil habilita una herramienta
+func (p Profile) ToolEnabled(name string) bool {
+	if len(p.Tools) == 0 {
+		return true
+	}
+	for _, pattern := range p.Tools {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Check valida todos los perfiles y devuelve los problemas encontrados.
+// knownTools son las herramientas registradas, para detectar patrones que no habilitan nada.
+// Los comandos de token no se ejecutan; solo se comprueba que existan.
+func (c *Config) Check(knownTools []string) []Problem {
+	problems := append([]Problem(nil), c.unknownFields...)
+
+	if len(c.Profiles) == 0 {
+		problems = append(problems, Problem{Field: "profiles", Message: "no profiles defined"})
+	}
+	if c.DefaultProfile != "" {
+		if _, ok := c.Profiles[c.DefaultProfile]; !ok {
+			problems = append(problems, Problem{Field: "default_profile", Message: fmt.Sprintf("profile %q is not defined", c.DefaultProfile)})
+		}
+	}
+
+	for _, name := range c.ProfileNames() {
+		for _, problem := range c.Profiles[name].Check(knownTools) {
+			problem.Profile = name
+			problems = append(problems, problem)
+		}
+	}
+	return problems
+}
+
+// Check valida un perfil
+func (p Profile) Check(knownTools []string) []Problem {
+	var problems []Problem
+	add := func(field, format string, args ...interface{}) {
+		problems = append(problems, Problem{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	sources := 0
+	for _, source := range []string{p.Token.Env, p.Token.File, p.Token.Command} {
+		if source != "" {
+			sources++
+		}
+	}
+	switch {
+	case p.App != nil:
+		if sources > 0 {
+			add("app", "token and app are mutually exclusive")
+		}
+		if p.App.ID <= 0 {
+			add("app.id", "GitHub App ID required")
+		}
+		if p.App.PrivateKey == "" {
+			add("app.private_key", "private key file required")
+		} else if data, err := os.ReadFile(expandHome(p.App.PrivateKey)); err != nil {
+			add("app.private_key", "%v", err)
+		} else if _, err := githubapi.ParsePrivateKey(data); err != nil {
+			add("app.private_key", "%v", err)
+		}
+	case sources > 1:
+		add("token", "only one of env, file or command may be set")
+	case p.Token.Command != "":
+		if _, err := exec.LookPath(commandName(p.Token.Command)); err != nil {
+			add("token.command", "%v", err)
+		}
+	default:
+		if _, err := p.Token.ResolveToken(); err != nil {
+			add("token", "%v", err)
+		}
+	}
+
+	if p.Owner != "" && !ownerPattern.MatchString(p.Owner) {
+		add("owner", "invalid owner %q", p.Owner)
+	}
+	if p.Repo != "" && !repoPattern.MatchString(p.Repo) {
+		add("repo", "invalid repository name %q", p.Repo)
+	}
+
+	if p.Workspace != "" {
+		if _, err := os.Stat(filepath.Join(expandHome(p.Workspace), ".git")); err != nil {
+			add("workspace", "%s is not a Git repository", p.Workspace)
+		} else if p.Remote != "" {
+			if err := exec.Command("git", "-C", expandHome(p.Workspace), "remote", "get-url", p.Remote).Run(); err != nil {
+				add("remote", "remote %q not found in %s", p.Remote, p.Workspace)
+			}
+		}
+	}
+
+	for _, field := range []struct{ name, value string }{
+		{"base_url", p.BaseURL},
+		{"upload_url", p.UploadURL},
+		{"proxy", p.Proxy},
+	} {
+		if field.value == "" {
+			continue
+		}
+		if u, err := url.Parse(field.value); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add(field.name, "invalid URL %q", field.value)
+		}
+	}
+	if p.UploadURL != "" && p.BaseURL == "" {
+		add("upload_url", "requires base_url")
+	}
+
+	if p.CABundle != "" {
+		if _, err := githubapi.LoadCABundle(expandHome(p.CABundle)); err != nil {
+			add("ca_bundle", "%v", err)
+		}
+	}
+
+	for _, pattern := range p.Tools {
+		if _, err := path.Match(pattern, ""); err != nil {
+			add("tools", "invalid pattern %q", pattern)
+			continue
+		}
+		matched := false
+		for _, tool := range knownTools {
+			if ok, _ := path.Match(pattern, tool); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			add("tools", "%q does not match any tool", pattern)
+		}
+	}
+	return problems
+}
+
+// commandName devuelve el ejecutable de un comando de token
+func commandName(command string) string {
+	if fields := strings.Fields(command); len(fields) > 0 {
+		return fields[0]
+	}
+	return command
+}
+
+// WorkspacePath devuelve el workspace con ~ expandido
+func (p Profile) WorkspacePath() string {
+	return expandHome(p.Workspace)
+}
+
+// TokenSource devuelve la fuente de tokens del perfil: GitHub App o token personal
+func (p Profile) TokenSource() (githubapi.TokenSource, error) {
+	if p.App == nil {
+		token, err := p.Token.ResolveToken()
+		if err != nil {
+			return nil, err
+		}
+		return githubapi.StaticToken(token), nil
+	}
+
+	key, err := os.ReadFile(expandHome(p.App.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("reading GitHub App private key: %v", err)
+	}
+	return githubapi.NewAppTokenSource(githubapi.AppOptions{
+		AppID:          p.App.ID,
+		PrivateKey:     key,
+		InstallationID: p.App.InstallationID,
+	}, p.ClientOptions())
+}
+
+// DescribeAuth indica el modo de autenticación para los logs (sin revelar secretos)
+func (p Profile) DescribeAuth() string {
+	if p.App != nil {
+		return fmt.Sprintf("GitHub App %d", p.App.ID)
+	}
+	return p.Token.Describe()
+}
+
+// ClientOptions devuelve la configuración de conexión con GitHub del perfil
+func (p Profile) ClientOptions() githubapi.ClientOptions {
+	opts := githubapi.ClientOptions{
+		BaseURL:   p.BaseURL,
+		UploadURL: p.UploadURL,
+		Proxy:     p.Proxy,
+	}
+	if p.CABundle != "" {
+		opts.CABundle = expandHome(p.CABundle)
+	}
+	return opts
+}
+
+// expandHome expande el prefijo ~ de una ruta
+func expandHome(p string) string {
+	if p != "~" && !strings.HasPrefix(p, "~/") && !strings.HasPrefix(p, `~\`) {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	return filepath.Join(home, p[1:])
+}
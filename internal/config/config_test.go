@@ -0,0 +1,98 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoadUnknownKeys(t *testing.T) {
+	file := writeConfig(t, `default_profile: work
+profiles:
+  work:
+    token: {tokn_env: WORK_TOKEN}
+    owner: acme
+    base_brnach: main
+`)
+	cfg, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// El resto del archivo se lee igualmente
+	if cfg.Profiles["work"].Owner != "acme" {
+		t.Errorf("owner = %q, want acme", cfg.Profiles["work"].Owner)
+	}
+
+	t.Setenv(DefaultTokenEnv, "ghp_test")
+	var unknown []string
+	for _, problem := range cfg.Check(nil) {
+		if problem.Profile == "" {
+			unknown = append(unknown, problem.String())
+		}
+	}
+	want := []string{`line 4: unknown key "tokn_env"`, `line 6: unknown key "base_brnach"`}
+	if !reflect.DeepEqual(unknown, want) {
+		t.Errorf("problems = %q, want %q", unknown, want)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	file := writeConfig(t, `default_profile: work
+profiles:
+  work:
+    token: {env: WORK_TOKEN}
+    owner: acme
+    repo: widgets
+    base_branch: main
+    tools: ["git_*"]
+`)
+	cfg, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile, err := cfg.Profile("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Token.Env != "WORK_TOKEN" || profile.BaseBranch != "main" || profile.Repo != "widgets" {
+		t.Errorf("profile = %+v", profile)
+	}
+	if !profile.ToolEnabled("git_status") || profile.ToolEnabled("github_list_repos") {
+		t.Errorf("tools filter not applied: %v", profile.Tools)
+	}
+
+	t.Setenv("WORK_TOKEN", "ghp_test")
+	if problems := cfg.Check([]string{"git_status"}); len(problems) != 0 {
+		t.Errorf("unexpected problems: %v", problems)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"syntax":     "profiles: [",
+		"wrong type": "profiles:\n  work:\n    tools: git_*\n",
+	} {
+		if _, err := Load(writeConfig(t, content)); err == nil {
+			t.Errorf("%s: Load succeeded, want error", name)
+		}
+	}
+
+	cfg, err := Load(writeConfig(t, ""))
+	if err != nil {
+		t.Fatalf("empty file: %v", err)
+	}
+	if len(cfg.Profiles) != 0 {
+		t.Errorf("empty file: profiles = %v", cfg.Profiles)
+	}
+}
@@ -1,9 +1,8 @@
 package git
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"os"
 	"os/exec"
 	"strings"
 
@@ -11,447 +10,550 @@ import (
 )
 
 // LogAnalysis muestra el historial de commits con análisis
-func LogAnalysis(config types.GitConfig, limit string) (string, error) {
+func LogAnalysis(ctx context.Context, config types.GitConfig, limit int) (*LogAnalysisResult, error) {
 	if !config.HasGit || !config.IsGitRepo {
-		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
 	}
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(config.RepoPath)
+	r := runnerFor(ctx, config)
 
-	if limit == "" {
-		limit = "20"
+	if limit <= 0 {
+		limit = 20
 	}
+	limitArg := fmt.Sprintf("-%d", limit)
 
-	result := map[string]interface{}{}
+	result := &LogAnalysisResult{}
 
 	// Log gráfico
-	cmd := exec.Command("git", "log", "--graph", "--oneline", "--decorate", "--all", "-"+limit)
+	cmd := r.Command("log", "--graph", "--oneline", "--decorate", "--all", limitArg)
 	if output, err := cmd.Output(); err == nil {
-		result["graphLog"] = strings.TrimSpace(string(output))
+		result.GraphLog = strings.TrimSpace(string(output))
 	}
 
 	// Estadísticas de commits por autor
-	cmd = exec.Command("git", "shortlog", "-sn", "--all")
+	cmd = r.Command("shortlog", "-sn", "--all")
 	if output, err := cmd.Output(); err == nil {
-		result["authorStats"] = strings.TrimSpace(string(output))
+		result.AuthorStats = strings.TrimSpace(string(output))
 	}
 
 	// Últimos commits con detalles
-	cmd = exec.Command("git", "log", "--pretty=format:%h|%an|%ad|%s", "--date=short", "-"+limit)
+	cmd = r.Command("log", "--pretty=format:%h|%an|%ad|%s", "--date=short", limitArg)
 	if output, err := cmd.Output(); err == nil {
-		result["recentCommits"] = strings.TrimSpace(string(output))
+		result.RecentCommits = strings.TrimSpace(string(output))
 	}
 
-	output, _ := json.MarshalIndent(result, "", "  ")
-	return string(output), nil
+	return result, nil
 }
 
 // DiffFiles muestra archivos modificados con detalles
-func DiffFiles(config types.GitConfig, staged bool) (string, error) {
+func DiffFiles(ctx context.Context, config types.GitConfig, staged bool) (*DiffFilesResult, error) {
 	if !config.HasGit || !config.IsGitRepo {
-		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
 	}
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(config.RepoPath)
+	r := runnerFor(ctx, config)
 
-	result := map[string]interface{}{}
+	result := &DiffFilesResult{}
 
 	var cmd *exec.Cmd
 	if staged {
 		// Archivos en staging
-		cmd = exec.Command("git", "diff", "--name-status", "--cached")
+		cmd = r.Command("diff", "--name-status", "--cached")
 	} else {
 		// Archivos modificados
-		cmd = exec.Command("git", "diff", "--name-status")
+		cmd = r.Command("diff", "--name-status")
 	}
 
 	if output, err := cmd.Output(); err == nil {
-		result["files"] = strings.TrimSpace(string(output))
+		result.Files = strings.TrimSpace(string(output))
 	}
 
 	// Estadísticas de cambios
 	if staged {
-		cmd = exec.Command("git", "diff", "--stat", "--cached")
+		cmd = r.Command("diff", "--stat", "--cached")
 	} else {
-		cmd = exec.Command("git", "diff", "--stat")
+		cmd = r.Command("diff", "--stat")
 	}
 
 	if output, err := cmd.Output(); err == nil {
-		result["stats"] = strings.TrimSpace(string(output))
+		result.Stats = strings.TrimSpace(string(output))
 	}
 
 	// Archivos sin seguimiento (solo si no es staged)
 	if !staged {
-		cmd = exec.Command("git", "ls-files", "--others", "--exclude-standard")
+		cmd = r.Command("ls-files", "--others", "--exclude-standard")
 		if output, err := cmd.Output(); err == nil {
 			untracked := strings.TrimSpace(string(output))
 			if untracked != "" {
-				result["untracked"] = untracked
+				result.Untracked = untracked
 			}
 		}
 	}
 
-	output, _ := json.MarshalIndent(result, "", "  ")
-	return string(output), nil
+	return result, nil
 }
 
 // BranchList lista todas las ramas con información detallada
-func BranchList(config types.GitConfig, remote bool) (string, error) {
+func BranchList(ctx context.Context, config types.GitConfig, remote bool) (*BranchListResult, error) {
 	if !config.HasGit || !config.IsGitRepo {
-		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
 	}
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(config.RepoPath)
+	r := runnerFor(ctx, config)
 
-	result := map[string]interface{}{}
+	result := &BranchListResult{}
 
 	// Ramas locales
-	cmd := exec.Command("git", "branch", "-v")
+	cmd := r.Command("branch", "-v")
 	if output, err := cmd.Output(); err == nil {
-		result["localBranches"] = strings.TrimSpace(string(output))
+		result.LocalBranches = strings.TrimSpace(string(output))
 	}
 
 	if remote {
 		// Ramas remotas
-		cmd = exec.Command("git", "branch", "-r", "-v")
+		cmd = r.Command("branch", "-r", "-v")
 		if output, err := cmd.Output(); err == nil {
-			result["remoteBranches"] = strings.TrimSpace(string(output))
+			result.RemoteBranches = strings.TrimSpace(string(output))
 		}
 
 		// Todas las ramas
-		cmd = exec.Command("git", "branch", "-a", "-v")
+		cmd = r.Command("branch", "-a", "-v")
 		if output, err := cmd.Output(); err == nil {
-			result["allBranches"] = strings.TrimSpace(string(output))
+			result.AllBranches = strings.TrimSpace(string(output))
 		}
 	}
 
 	// Rama actual
-	cmd = exec.Command("git", "branch", "--show-current")
+	cmd = r.Command("branch", "--show-current")
 	if output, err := cmd.Output(); err == nil {
-		result["currentBranch"] = strings.TrimSpace(string(output))
+		result.CurrentBranch = strings.TrimSpace(string(output))
 	}
 
 	// Último commit de cada rama
-	cmd = exec.Command("git", "for-each-ref", "--format=%(refname:short) %(committerdate:short) %(subject)", "refs/heads/")
+	cmd = r.Command("for-each-ref", "--format=%(refname:short) %(committerdate:short) %(subject)", "refs/heads/")
 	if output, err := cmd.Output(); err == nil {
-		result["branchCommits"] = strings.TrimSpace(string(output))
+		result.BranchCommits = strings.TrimSpace(string(output))
 	}
 
-	output, _ := json.MarshalIndent(result, "", "  ")
-	return string(output), nil
+	return result, nil
 }
 
 // StashOperations maneja operaciones de stash
-func StashOperations(config types.GitConfig, operation, name string) (string, error) {
+func StashOperations(ctx context.Context, config types.GitConfig, operation, name string) (*OperationResult, error) {
 	if !config.HasGit || !config.IsGitRepo {
-		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
 	}
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(config.RepoPath)
+	r := runnerFor(ctx, config)
 
 	var cmd *exec.Cmd
-	var result string
+	result := &OperationResult{Operation: operation, Target: name}
 
 	switch operation {
 	case "list":
-		cmd = exec.Command("git", "stash", "list")
+		cmd = r.Command("stash", "list")
 		if output, err := cmd.Output(); err == nil {
-			result = fmt.Sprintf("Stash list:\n%s", strings.TrimSpace(string(output)))
+			result.Summary, result.Output = "Stash list", strings.TrimSpace(string(output))
 		} else {
-			result = "No hay stashes guardados"
+			result.Summary = "No hay stashes guardados"
 		}
 
 	case "push":
 		if name != "" {
-			cmd = exec.Command("git", "stash", "push", "-m", name)
+			cmd = r.Command("stash", "push", "-m", name)
 		} else {
-			cmd = exec.Command("git", "stash", "push")
+			cmd = r.Command("stash", "push")
 		}
 		if output, err := cmd.CombinedOutput(); err == nil {
-			result = fmt.Sprintf("Stash creado:\n%s", strings.TrimSpace(string(output)))
+			result.Summary, result.Output = "Stash creado", strings.TrimSpace(string(output))
 		} else {
-			return "", fmt.Errorf("error creando stash: %v\nOutput: %s", err, output)
+			return nil, fmt.Errorf("error creando stash: %v\nOutput: %s", err, output)
 		}
 
 	case "pop":
 		if name != "" {
-			cmd = exec.Command("git", "stash", "pop", name)
+			cmd = r.Command("stash", "pop", name)
 		} else {
-			cmd = exec.Command("git", "stash", "pop")
+			cmd = r.Command("stash", "pop")
 		}
 		if output, err := cmd.CombinedOutput(); err == nil {
-			result = fmt.Sprintf("Stash aplicado y eliminado:\n%s", strings.TrimSpace(string(output)))
+			result.Summary, result.Output = "Stash aplicado y eliminado", strings.TrimSpace(string(output))
 		} else {
-			return "", fmt.Errorf("error aplicando stash: %v\nOutput: %s", err, output)
+			return nil, fmt.Errorf("error aplicando stash: %v\nOutput: %s", err, output)
 		}
 
 	case "apply":
 		if name != "" {
-			cmd = exec.Command("git", "stash", "apply", name)
+			cmd = r.Command("stash", "apply", name)
 		} else {
-			cmd = exec.Command("git", "stash", "apply")
+			cmd = r.Command("stash", "apply")
 		}
 		if output, err := cmd.CombinedOutput(); err == nil {
-			result = fmt.Sprintf("Stash aplicado (mantenido):\n%s", strings.TrimSpace(string(output)))
+			result.Summary, result.Output = "Stash aplicado (mantenido)", strings.TrimSpace(string(output))
 		} else {
-			return "", fmt.Errorf("error aplicando stash: %v\nOutput: %s", err, output)
+			return nil, fmt.Errorf("error aplicando stash: %v\nOutput: %s", err, output)
 		}
 
 	case "drop":
 		if name != "" {
-			cmd = exec.Command("git", "stash", "drop", name)
+			cmd = r.Command("stash", "drop", name)
 		} else {
-			cmd = exec.Command("git", "stash", "drop")
+			cmd = r.Command("stash", "drop")
 		}
 		if output, err := cmd.CombinedOutput(); err == nil {
-			result = fmt.Sprintf("Stash eliminado:\n%s", strings.TrimSpace(string(output)))
+			result.Summary, result.Output = "Stash eliminado", strings.TrimSpace(string(output))
 		} else {
-			return "", fmt.Errorf("error eliminando stash: %v\nOutput: %s", err, output)
+			return nil, fmt.Errorf("error eliminando stash: %v\nOutput: %s", err, output)
 		}
 
 	case "clear":
-		cmd = exec.Command("git", "stash", "clear")
+		cmd = r.Command("stash", "clear")
 		if output, err := cmd.CombinedOutput(); err == nil {
-			result = "Todos los stashes han sido eliminados"
+			result.Summary = "Todos los stashes han sido eliminados"
 		} else {
-			return "", fmt.Errorf("error limpiando stashes: %v\nOutput: %s", err, output)
+			return nil, fmt.Errorf("error limpiando stashes: %v\nOutput: %s", err, output)
 		}
 
 	default:
-		return "", fmt.Errorf("operación no válida: %s. Usa: list, push, pop, apply, drop, clear", operation)
+		return nil, fmt.Errorf("operación no válida: %s. Usa: list, push, pop, apply, drop, clear", operation)
 	}
 
 	return result, nil
 }
 
 // RemoteOperations maneja operaciones con remotos
-func RemoteOperations(config types.GitConfig, operation, name, url string) (string, error) {
+func RemoteOperations(ctx context.Context, config types.GitConfig, operation, name, url string) (*OperationResult, error) {
 	if !config.HasGit || !config.IsGitRepo {
-		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
 	}
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(config.RepoPath)
+	r := runnerFor(ctx, config)
 
 	var cmd *exec.Cmd
-	var result string
+	result := &OperationResult{Operation: operation, Target: name}
 
 	switch operation {
 	case "list":
-		cmd = exec.Command("git", "remote", "-v")
+		cmd = r.Command("remote", "-v")
 		if output, err := cmd.Output(); err == nil {
-			result = fmt.Sprintf("Remotos configurados:\n%s", strings.TrimSpace(string(output)))
+			result.Summary, result.Output = "Remotos configurados", strings.TrimSpace(string(output))
 		} else {
-			result = "No hay remotos configurados"
+			result.Summary = "No hay remotos configurados"
 		}
 
 	case "add":
 		if name == "" || url == "" {
-			return "", fmt.Errorf("nombre y URL requeridos para agregar remoto")
+			return nil, fmt.Errorf("nombre y URL requeridos para agregar remoto")
 		}
-		cmd = exec.Command("git", "remote", "add", name, url)
+		cmd = r.Command("remote", "add", name, url)
 		if output, err := cmd.CombinedOutput(); err == nil {
-			result = fmt.Sprintf("Remoto '%s' agregado: %s", name, url)
+			result.Summary = fmt.Sprintf("Remoto '%s' agregado: %s", name, url)
 		} else {
-			return "", fmt.Errorf("error agregando remoto: %v\nOutput: %s", err, output)
+			return nil, fmt.Errorf("error agregando remoto: %v\nOutput: %s", err, output)
 		}
 
 	case "remove":
 		if name == "" {
-			return "", fmt.Errorf("nombre del remoto requerido")
+			return nil, fmt.Errorf("nombre del remoto requerido")
 		}
-		cmd = exec.Command("git", "remote", "remove", name)
+		cmd = r.Command("remote", "remove", name)
 		if output, err := cmd.CombinedOutput(); err == nil {
-			result = fmt.Sprintf("Remoto '%s' eliminado", name)
+			result.Summary = fmt.Sprintf("Remoto '%s' eliminado", name)
 		} else {
-			return "", fmt.Errorf("error eliminando remoto: %v\nOutput: %s", err, output)
+			return nil, fmt.Errorf("error eliminando remoto: %v\nOutput: %s", err, output)
 		}
 
 	case "show":
 		if name == "" {
 			name = "origin"
 		}
-		cmd = exec.Command("git", "remote", "show", name)
+		result.Target = name
+		cmd = r.Command("remote", "show", name)
 		if output, err := cmd.Output(); err == nil {
-			result = fmt.Sprintf("Información del remoto '%s':\n%s", name, strings.TrimSpace(string(output)))
+			result.Summary, result.Output = fmt.Sprintf("Información del remoto '%s'", name), strings.TrimSpace(string(output))
 		} else {
-			return "", fmt.Errorf("error mostrando remoto: %v", err)
+			return nil, fmt.Errorf("error mostrando remoto: %v", err)
 		}
 
 	case "fetch":
 		if name == "" {
-			cmd = exec.Command("git", "fetch", "--all")
-			result = "Fetching desde todos los remotos"
+			cmd = r.Command("fetch", "--all")
+			result.Summary = "Fetching desde todos los remotos"
 		} else {
-			cmd = exec.Command("git", "fetch", name)
-			result = fmt.Sprintf("Fetching desde '%s'", name)
+			cmd = r.Command("fetch", name)
+			result.Summary = fmt.Sprintf("Fetching desde '%s'", name)
 		}
 		if output, err := cmd.CombinedOutput(); err == nil {
-			result += fmt.Sprintf(":\n%s", strings.TrimSpace(string(output)))
+			result.Output = strings.TrimSpace(string(output))
 		} else {
-			return "", fmt.Errorf("error en fetch: %v\nOutput: %s", err, output)
+			return nil, fmt.Errorf("error en fetch: %v\nOutput: %s", err, output)
 		}
 
 	default:
-		return "", fmt.Errorf("operación no válida: %s. Usa: list, add, remove, show, fetch", operation)
+		return nil, fmt.Errorf("operación no válida: %s. Usa: list, add, remove, show, fetch", operation)
 	}
 
 	return result, nil
 }
 
+// TagExists indica si existe el tag en el repositorio local
+func TagExists(ctx context.Context, config types.GitConfig, tagName string) bool {
+	_, err := runnerFor(ctx, config).Output("rev-parse", "-q", "--verify", "refs/tags/"+tagName)
+	return err == nil
+}
+
+// ValidateTagName comprueba que tag sea un nombre de tag válido antes de ejecutar ningún otro comando
+func ValidateTagName(ctx context.Context, config types.GitConfig, tag string) error {
+	return runnerFor(ctx, config).CheckTagName(tag)
+}
+
+// requireTagName exige un nombre de tag y comprueba que sea válido
+func requireTagName(r Runner, tagName string) error {
+	if tagName == "" {
+		return fmt.Errorf("nombre del tag requerido")
+	}
+	return r.CheckTagName(tagName)
+}
+
 // TagOperations maneja operaciones con tags
-func TagOperations(config types.GitConfig, operation, tagName, message string) (string, error) {
+func TagOperations(ctx context.Context, config types.GitConfig, operation, tagName, message string) (*OperationResult, error) {
 	if !config.HasGit || !config.IsGitRepo {
-		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
 	}
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(config.RepoPath)
+	r := runnerFor(ctx, config)
 
 	var cmd *exec.Cmd
-	var result string
+	result := &OperationResult{Operation: operation, Target: tagName}
 
 	switch operation {
 	case "list":
-		cmd = exec.Command("git", "tag", "-l", "--sort=-version:refname")
+		cmd = r.Command("tag", "-l", "--sort=-version:refname")
 		if output, err := cmd.Output(); err == nil {
-			result = fmt.Sprintf("Tags disponibles:\n%s", strings.TrimSpace(string(output)))
+			result.Summary, result.Output = "Tags disponibles", strings.TrimSpace(string(output))
 		} else {
-			result = "No hay tags creados"
+			result.Summary = "No hay tags creados"
 		}
 
 	case "create":
-		if tagName == "" {
-			return "", fmt.Errorf("nombre del tag requerido")
+		if err := requireTagName(r, tagName); err != nil {
+			return nil, err
 		}
 		if message != "" {
-			cmd = exec.Command("git", "tag", "-a", tagName, "-m", message)
+			cmd = r.Command("tag", "-a", tagName, "-m", message)
 		} else {
-			cmd = exec.Command("git", "tag", tagName)
+			cmd = r.Command("tag", tagName)
 		}
 		if output, err := cmd.CombinedOutput(); err == nil {
-			result = fmt.Sprintf("Tag '%s' creado", tagName)
+			result.Summary = fmt.Sprintf("Tag '%s' creado", tagName)
 		} else {
-			return "", fmt.Errorf("error creando tag: %v\nOutput: %s", err, output)
+			return nil, fmt.Errorf("error creando tag: %v\nOutput: %s", err, output)
 		}
 
 	case "delete":
-		if tagName == "" {
-			return "", fmt.Errorf("nombre del tag requerido")
+		if err := requireTagName(r, tagName); err != nil {
+			return nil, err
 		}
-		cmd = exec.Command("git", "tag", "-d", tagName)
+		cmd = r.Command("tag", "-d", tagName)
 		if output, err := cmd.CombinedOutput(); err == nil {
-			result = fmt.Sprintf("Tag '%s' eliminado localmente", tagName)
+			result.Summary = fmt.Sprintf("Tag '%s' eliminado localmente", tagName)
 		} else {
-			return "", fmt.Errorf("error eliminando tag: %v\nOutput: %s", err, output)
+			return nil, fmt.Errorf("error eliminando tag: %v\nOutput: %s", err, output)
 		}
 
 	case "push":
+		remote := remoteName(config)
 		if tagName == "" {
-			cmd = exec.Command("git", "push", "origin", "--tags")
-			result = "Todos los tags enviados al remoto"
+			cmd = r.Command("push", remote, "--tags")
+			result.Summary = "Todos los tags enviados al remoto"
 		} else {
-			cmd = exec.Command("git", "push", "origin", tagName)
-			result = fmt.Sprintf("Tag '%s' enviado al remoto", tagName)
+			if err := r.CheckTagName(tagName); err != nil {
+				return nil, err
+			}
+			cmd = r.Command("push", remote, "refs/tags/"+tagName)
+			result.Summary = fmt.Sprintf("Tag '%s' enviado a '%s'", tagName, remote)
 		}
 		if output, err := cmd.CombinedOutput(); err == nil {
-			result += fmt.Sprintf(":\n%s", strings.TrimSpace(string(output)))
+			result.Output = strings.TrimSpace(string(output))
 		} else {
-			return "", fmt.Errorf("error enviando tags: %v\nOutput: %s", err, output)
+			return nil, fmt.Errorf("error enviando tags: %v\nOutput: %s", err, output)
 		}
 
 	case "show":
-		if tagName == "" {
-			return "", fmt.Errorf("nombre del tag requerido")
+		if err := requireTagName(r, tagName); err != nil {
+			return nil, err
 		}
-		cmd = exec.Command("git", "show", tagName)
+		cmd = r.Command("show", tagName)
 		if output, err := cmd.Output(); err == nil {
-			result = fmt.Sprintf("Información del tag '%s':\n%s", tagName, strings.TrimSpace(string(output)))
+			result.Summary, result.Output = fmt.Sprintf("Información del tag '%s'", tagName), strings.TrimSpace(string(output))
 		} else {
-			return "", fmt.Errorf("error mostrando tag: %v", err)
+			return nil, fmt.Errorf("error mostrando tag: %v", err)
 		}
 
 	default:
-		return "", fmt.Errorf("operación no válida: %s. Usa: list, create, delete, push, show", operation)
+		return nil, fmt.Errorf("operación no válida: %s. Usa: list, create, delete, push, show", operation)
 	}
 
 	return result, nil
 }
 
 // CleanOperations operaciones de limpieza del repositorio
-func CleanOperations(config types.GitConfig, operation string, dryRun bool) (string, error) {
+func CleanOperations(ctx context.Context, config types.GitConfig, operation string, dryRun bool) (*CleanResult, error) {
 	if !config.HasGit || !config.IsGitRepo {
-		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
 	}
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(config.RepoPath)
+	r := runnerFor(ctx, config)
 
 	var cmd *exec.Cmd
-	var result string
+	result := &CleanResult{Operation: operation, DryRun: dryRun, Files: []string{}}
 
 	switch operation {
 	case "untracked":
 		if dryRun {
-			cmd = exec.Command("git", "clean", "-n")
-			result = "Vista previa - archivos que se eliminarían:"
+			cmd = r.Command("clean", "-n")
+			result.summary = "Vista previa - archivos que se eliminarían:"
 		} else {
-			cmd = exec.Command("git", "clean", "-f")
-			result = "Archivos sin seguimiento eliminados:"
+			cmd = r.Command("clean", "-f")
+			result.summary = "Archivos sin seguimiento eliminados:"
 		}
 
 	case "untracked_dirs":
 		if dryRun {
-			cmd = exec.Command("git", "clean", "-n", "-d")
-			result = "Vista previa - archivos y directorios que se eliminarían:"
+			cmd = r.Command("clean", "-n", "-d")
+			result.summary = "Vista previa - archivos y directorios que se eliminarían:"
 		} else {
-			cmd = exec.Command("git", "clean", "-f", "-d")
-			result = "Archivos y directorios sin seguimiento eliminados:"
+			cmd = r.Command("clean", "-f", "-d")
+			result.summary = "Archivos y directorios sin seguimiento eliminados:"
 		}
 
 	case "ignored":
 		if dryRun {
-			cmd = exec.Command("git", "clean", "-n", "-X")
-			result = "Vista previa - archivos ignorados que se eliminarían:"
+			cmd = r.Command("clean", "-n", "-X")
+			result.summary = "Vista previa - archivos ignorados que se eliminarían:"
 		} else {
-			cmd = exec.Command("git", "clean", "-f", "-X")
-			result = "Archivos ignorados eliminados:"
+			cmd = r.Command("clean", "-f", "-X")
+			result.summary = "Archivos ignorados eliminados:"
 		}
 
 	case "all":
 		if dryRun {
-			cmd = exec.Command("git", "clean", "-n", "-d", "-x")
-			result = "Vista previa - todos los archivos sin seguimiento que se eliminarían:"
+			cmd = r.Command("clean", "-n", "-d", "-x")
+			result.summary = "Vista previa - todos los archivos sin seguimiento que se eliminarían:"
 		} else {
-			cmd = exec.Command("git", "clean", "-f", "-d", "-x")
-			result = "Todos los archivos sin seguimiento eliminados:"
+			cmd = r.Command("clean", "-f", "-d", "-x")
+			result.summary = "Todos los archivos sin seguimiento eliminados:"
 		}
 
 	default:
-		return "", fmt.Errorf("operación no válida: %s. Usa: untracked, untracked_dirs, ignored, all", operation)
+		return nil, fmt.Errorf("operación no válida: %s. Usa: untracked, untracked_dirs, ignored, all", operation)
 	}
 
 	if output, err := cmd.Output(); err == nil {
-		cleanResult := strings.TrimSpace(string(output))
-		if cleanResult == "" {
-			result += "\nNo hay archivos para procesar"
-		} else {
-			result += fmt.Sprintf("\n%s", cleanResult)
+		for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+			// git clean informa "Would remove <ruta>" en vista previa y "Removing <ruta>" al ejecutar
+			line = strings.TrimPrefix(strings.TrimPrefix(line, "Would remove "), "Removing ")
+			if line != "" {
+				result.Files = append(result.Files, line)
+			}
 		}
 	} else {
-		return "", fmt.Errorf("error en limpieza: %v", err)
+		return nil, fmt.Errorf("error en limpieza: %v", err)
 	}
 
 	return result, nil
 }
+
+// StagedDiff devuelve el diff de los cambios en staging
+func StagedDiff(ctx context.Context, config types.GitConfig) (string, error) {
+	if !config.HasGit || !config.IsGitRepo {
+		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+	}
+
+	output, err := runnerFor(ctx, config).Output("diff", "--cached")
+	if err != nil {
+		return "", fmt.Errorf("error obteniendo diff en staging: %v", err)
+	}
+	return string(output), nil
+}
+
+// DiffAgainst devuelve el diff del working directory contra una referencia.
+// Sin referencia se usa el upstream de la rama actual (@{upstream}).
+func DiffAgainst(ctx context.Context, config types.GitConfig, ref string) (string, error) {
+	if !config.HasGit || !config.IsGitRepo {
+		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+	}
+
+	if ref == "" {
+		ref = "@{upstream}"
+	}
+
+	r := runnerFor(ctx, config)
+	sha, err := r.ResolveCommit(ref)
+	if err != nil {
+		return "", err
+	}
+	output, err := r.CombinedOutput("diff", sha, "--")
+	if err != nil {
+		return "", fmt.Errorf("error obteniendo diff contra %s: %v\nOutput: %s", ref, err, output)
+	}
+	return string(output), nil
+}
+
+// CommitsBetween lista los commits alcanzables desde head y no desde base (base..head)
+func CommitsBetween(ctx context.Context, config types.GitConfig, base, head string) (string, error) {
+	if !config.HasGit || !config.IsGitRepo {
+		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+	}
+
+	if head == "" {
+		head = "HEAD"
+	}
+
+	r := runnerFor(ctx, config)
+	baseSHA, headSHA, err := resolveRange(r, base, head)
+	if err != nil {
+		return "", err
+	}
+	output, err := r.CombinedOutput("log", "--pretty=format:%h|%an|%ad|%s%n%b", "--date=short", baseSHA+".."+headSHA, "--")
+	if err != nil {
+		return "", fmt.Errorf("error listando commits %s..%s: %v\nOutput: %s", base, head, err, output)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// DiffStat devuelve las estadísticas de cambios entre dos referencias (base...head)
+func DiffStat(ctx context.Context, config types.GitConfig, base, head string) (string, error) {
+	if !config.HasGit || !config.IsGitRepo {
+		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+	}
+
+	if head == "" {
+		head = "HEAD"
+	}
+
+	r := runnerFor(ctx, config)
+	baseSHA, headSHA, err := resolveRange(r, base, head)
+	if err != nil {
+		return "", err
+	}
+	output, err := r.CombinedOutput("diff", "--stat", baseSHA+"..."+headSHA, "--")
+	if err != nil {
+		return "", fmt.Errorf("error obteniendo estadísticas %s...%s: %v\nOutput: %s", base, head, err, output)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// resolveRange resuelve los extremos de un rango a SHAs para construirlo sin
+// pasar a git referencias que podrían interpretarse como opciones
+func resolveRange(r Runner, base, head string) (string, string, error) {
+	baseSHA, err := r.ResolveCommit(base)
+	if err != nil {
+		return "", "", err
+	}
+	headSHA, err := r.ResolveCommit(head)
+	if err != nil {
+		return "", "", err
+	}
+	return baseSHA, headSHA, nil
+}
@@ -1,7 +1,7 @@
 package git
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,9 +11,14 @@ import (
 	"github.com/jotajotape/github-go-server-mcp/internal/types"
 )
 
-// DetectGitEnvironment detecta y configura el entorno Git local
-func DetectGitEnvironment() types.GitConfig {
-	config := types.GitConfig{}
+// DetectGitEnvironment detecta y configura el entorno Git local.
+// githubHost es el host de GitHub del perfil (vacío = github.com) y remoteName el
+// remoto del que se deducen owner y repositorio (vacío = origin).
+func DetectGitEnvironment(githubHost, remoteName string) types.GitConfig {
+	config := types.GitConfig{GitHubHost: githubHost, RemoteName: remoteName}
+	if config.GitHubHost == "" {
+		config.GitHubHost = DefaultGitHubHost
+	}
 	
 	// Verificar si git está disponible
 	if _, err := exec.LookPath("git"); err == nil {
@@ -39,18 +44,13 @@ func DetectGitEnvironment() types.GitConfig {
 	config.IsGitRepo = true
 	config.RepoPath = repoPath
 
-	// Cambiar al directorio del repositorio para ejecutar comandos
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(repoPath)
+	r := NewRunner(context.Background(), repoPath)
 
-	// Obtener URL remota
-	if output, err := exec.Command("git", "remote", "get-url", "origin").Output(); err == nil {
-		config.RemoteURL = strings.TrimSpace(string(output))
-	}
+	// Obtener URL remota (si el remoto elegido no existe se sigue sin owner/repo)
+	_ = loadRemote(r, &config)
 
 	// Obtener rama actual
-	if output, err := exec.Command("git", "branch", "--show-current").Output(); err == nil {
+	if output, err := r.Command("branch", "--show-current").Output(); err == nil {
 		config.CurrentBranch = strings.TrimSpace(string(output))
 	}
 
@@ -75,92 +75,87 @@ func findGitRepo(startPath string) string {
 }
 
 // Status muestra el estado del repositorio Git local
-func Status(config types.GitConfig) (string, error) {
-	result := map[string]interface{}{
-		"gitConfig": config,
-	}
+func Status(ctx context.Context, config types.GitConfig) (*StatusResult, error) {
+	result := &StatusResult{GitConfig: config}
 
 	if !config.HasGit {
-		result["message"] = "Git no está disponible en el sistema"
-		output, _ := json.MarshalIndent(result, "", "  ")
-		return string(output), nil
+		result.Message = "Git no está disponible en el sistema"
+		return result, nil
 	}
 
 	if !config.IsGitRepo {
-		result["message"] = "No se detectó repositorio Git en el directorio actual"
-		output, _ := json.MarshalIndent(result, "", "  ")
-		return string(output), nil
+		result.Message = "No se detectó repositorio Git en el directorio actual"
+		return result, nil
 	}
 
 	// Cambiar al directorio del repositorio
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(config.RepoPath)
+	r := runnerFor(ctx, config)
 
 	// Obtener status
-	if output, err := exec.Command("git", "status", "--porcelain").Output(); err == nil {
-		result["status"] = strings.TrimSpace(string(output))
+	if output, err := r.Command("status", "--porcelain").Output(); err == nil {
+		result.Status = strings.TrimSpace(string(output))
 	}
 
 	// Obtener log reciente
-	if output, err := exec.Command("git", "log", "--oneline", "-5").Output(); err == nil {
-		result["recentCommits"] = strings.TrimSpace(string(output))
+	if output, err := r.Command("log", "--oneline", "-5").Output(); err == nil {
+		result.RecentCommits = strings.TrimSpace(string(output))
 	}
 
-	output, _ := json.MarshalIndent(result, "", "  ")
-	return string(output), nil
+	return result, nil
 }
 
 // Add agrega archivos al staging area
-func Add(config types.GitConfig, files string) (string, error) {
+func Add(ctx context.Context, config types.GitConfig, files []string) (*AddResult, error) {
 	if !config.HasGit || !config.IsGitRepo {
-		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
 	}
 
 	workingDir := GetEffectiveWorkingDir(config)
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(workingDir)
+	r := NewRunner(ctx, workingDir)
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no se indicaron archivos para agregar")
+	}
 
-	cmd := exec.Command("git", "add", files)
+	cmd := r.Command(append([]string{"add", "--"}, files...)...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return "", fmt.Errorf("error ejecutando git add: %v\nOutput: %s", err, output)
+		return nil, fmt.Errorf("error ejecutando git add: %v\nOutput: %s", err, output)
 	}
 
-	return fmt.Sprintf("✅ Archivos agregados al staging: %s\n📁 Directorio: %s", files, workingDir), nil
+	return &AddResult{Files: files, Directory: workingDir}, nil
 }
 
 // Commit hace commit de los cambios en staging
-func Commit(config types.GitConfig, message string) (string, error) {
+func Commit(ctx context.Context, config types.GitConfig, message string) (*CommitResult, error) {
 	if !config.HasGit || !config.IsGitRepo {
-		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
 	}
 
 	workingDir := GetEffectiveWorkingDir(config)
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(workingDir)
+	r := NewRunner(ctx, workingDir)
 
-	cmd := exec.Command("git", "commit", "-m", message)
+	cmd := r.Command("commit", "-m", message)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return "", fmt.Errorf("error ejecutando git commit: %v\nOutput: %s", err, output)
+		return nil, fmt.Errorf("error ejecutando git commit: %v\nOutput: %s", err, output)
 	}
 
-	return fmt.Sprintf("✅ Commit realizado: %s\n📁 Directorio: %s\n📝 Output: %s", message, workingDir, output), nil
+	result := &CommitResult{Message: message, Directory: workingDir, Output: string(output)}
+	if sha, err := r.Command("rev-parse", "HEAD").Output(); err == nil {
+		result.SHA = strings.TrimSpace(string(sha))
+	}
+	return result, nil
 }
 
 // Push sube cambios al repositorio remoto
-func Push(config types.GitConfig, branch string) (string, error) {
+func Push(ctx context.Context, config types.GitConfig, branch string) (*PushResult, error) {
 	if !config.HasGit || !config.IsGitRepo {
-		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
 	}
 
 	workingDir := GetEffectiveWorkingDir(config)
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(workingDir)
+	r := NewRunner(ctx, workingDir)
 
 	if branch == "" {
 		branch = config.CurrentBranch
@@ -168,28 +163,26 @@ func Push(config types.GitConfig, branch string) (string, error) {
 
 	var cmd *exec.Cmd
 	if branch != "" {
-		cmd = exec.Command("git", "push", "origin", branch)
+		cmd = r.Command("push", remoteName(config), branch)
 	} else {
-		cmd = exec.Command("git", "push")
+		cmd = r.Command("push")
 	}
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return "", fmt.Errorf("error ejecutando git push: %v\nOutput: %s", err, output)
+		return nil, fmt.Errorf("error ejecutando git push: %v\nOutput: %s", err, output)
 	}
 
-	return fmt.Sprintf("🚀 Push realizado a rama: %s\n📁 Directorio: %s\n📝 Output: %s", branch, workingDir, output), nil
+	return &PushResult{Branch: branch, Directory: workingDir, Output: string(output)}, nil
 }
 
 // Pull baja cambios del repositorio remoto
-func Pull(config types.GitConfig, branch string) (string, error) {
+func Pull(ctx context.Context, config types.GitConfig, branch string) (*PullResult, error) {
 	if !config.HasGit || !config.IsGitRepo {
-		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
 	}
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(config.RepoPath)
+	r := runnerFor(ctx, config)
 
 	if branch == "" {
 		branch = config.CurrentBranch
@@ -197,51 +190,45 @@ func Pull(config types.GitConfig, branch string) (string, error) {
 
 	var cmd *exec.Cmd
 	if branch != "" {
-		cmd = exec.Command("git", "pull", "origin", branch)
+		cmd = r.Command("pull", remoteName(config), branch)
 	} else {
-		cmd = exec.Command("git", "pull")
+		cmd = r.Command("pull")
 	}
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return "", fmt.Errorf("error ejecutando git pull: %v\nOutput: %s", err, output)
+		return nil, fmt.Errorf("error ejecutando git pull: %v\nOutput: %s", err, output)
 	}
 
-	return fmt.Sprintf("Pull realizado desde rama: %s", branch), nil
+	return &PullResult{Branch: branch, Output: string(output)}, nil
 }
 
 // Checkout cambia de rama o crea nueva rama
-func Checkout(config *types.GitConfig, branch string, create bool) (string, error) {
+func Checkout(ctx context.Context, config *types.GitConfig, branch string, create bool) (*CheckoutResult, error) {
 	if !config.HasGit || !config.IsGitRepo {
-		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
 	}
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(config.RepoPath)
+	r := runnerFor(ctx, *config)
 
 	var cmd *exec.Cmd
 	if create {
-		cmd = exec.Command("git", "checkout", "-b", branch)
+		cmd = r.Command("checkout", "-b", branch)
 	} else {
-		cmd = exec.Command("git", "checkout", branch)
+		cmd = r.Command("checkout", branch)
 	}
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return "", fmt.Errorf("error ejecutando git checkout: %v\nOutput: %s", err, output)
+		return nil, fmt.Errorf("error ejecutando git checkout: %v\nOutput: %s", err, output)
 	}
 
 	config.CurrentBranch = branch
-	return fmt.Sprintf("Checkout a rama: %s (crear: %v)", branch, create), nil
+	return &CheckoutResult{Branch: branch, Created: create}, nil
 }
 
 // CreateFile crea un archivo usando Git local
 func CreateFile(config types.GitConfig, path, content string) (string, error) {
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(config.RepoPath)
-
 	// Crear archivo
 	fullPath := filepath.Join(config.RepoPath, path)
 	err := os.MkdirAll(filepath.Dir(fullPath), 0755)
@@ -260,10 +247,6 @@ func CreateFile(config types.GitConfig, path, content string) (string, error) {
 // UpdateFile actualiza un archivo usando Git local
 func UpdateFile(config types.GitConfig, path, content string) (string, error) {
 	workingDir := GetEffectiveWorkingDir(config)
-	
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(workingDir)
 
 	// Actualizar archivo
 	fullPath := filepath.Join(workingDir, path)
@@ -276,46 +259,56 @@ func UpdateFile(config types.GitConfig, path, content string) (string, error) {
 }
 
 // SetWorkspace configura el directorio de trabajo para operaciones Git
-func SetWorkspace(config *types.GitConfig, workspacePath string) (string, error) {
+// remoteName cambia el remoto del que se deducen owner y repositorio (vacío = el actual).
+func SetWorkspace(ctx context.Context, config *types.GitConfig, workspacePath, remoteName string) (*WorkspaceResult, error) {
 	// Verificar que el directorio existe
 	if _, err := os.Stat(workspacePath); os.IsNotExist(err) {
-		return "", fmt.Errorf("directorio no existe: %s", workspacePath)
+		return nil, fmt.Errorf("directorio no existe: %s", workspacePath)
 	}
 
 	// Verificar que es un repositorio Git
 	gitPath := filepath.Join(workspacePath, ".git")
 	if _, err := os.Stat(gitPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("no es un repositorio Git: %s", workspacePath)
+		return nil, fmt.Errorf("no es un repositorio Git: %s", workspacePath)
 	}
 
 	// Verificar que git está disponible
 	if _, err := exec.LookPath("git"); err != nil {
-		return "", fmt.Errorf("Git no está disponible en el sistema")
+		return nil, fmt.Errorf("Git no está disponible en el sistema")
 	}
 
-	// Configurar workspace
-	config.WorkspacePath = workspacePath
-	config.IsGitRepo = true
-	config.RepoPath = workspacePath
-	config.HasGit = true
+	// Configurar workspace (sobre una copia: si el remoto no existe no cambia nada)
+	updated := *config
+	updated.WorkspacePath = workspacePath
+	updated.IsGitRepo = true
+	updated.RepoPath = workspacePath
+	updated.HasGit = true
+	if remoteName != "" {
+		updated.RemoteName = remoteName
+	}
 
-	// Cambiar temporalmente al directorio para obtener info
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(workspacePath)
+	r := NewRunner(ctx, workspacePath)
 
-	// Obtener URL remota
-	if output, err := exec.Command("git", "remote", "get-url", "origin").Output(); err == nil {
-		config.RemoteURL = strings.TrimSpace(string(output))
+	// Obtener URL remota y owner/repo
+	if err := loadRemote(r, &updated); err != nil {
+		return nil, err
 	}
 
 	// Obtener rama actual
-	if output, err := exec.Command("git", "branch", "--show-current").Output(); err == nil {
-		config.CurrentBranch = strings.TrimSpace(string(output))
-	}
-
-	return fmt.Sprintf("✅ Workspace configurado: %s\n🌿 Rama: %s\n🔗 Remote: %s", 
-		workspacePath, config.CurrentBranch, config.RemoteURL), nil
+	updated.CurrentBranch = ""
+	if output, err := r.Command("branch", "--show-current").Output(); err == nil {
+		updated.CurrentBranch = strings.TrimSpace(string(output))
+	}
+
+	*config = updated
+	return &WorkspaceResult{
+		Path:       workspacePath,
+		Branch:     config.CurrentBranch,
+		RemoteName: config.RemoteName,
+		Remote:     config.RemoteURL,
+		Owner:      config.Owner,
+		Repo:       config.Repo,
+	}, nil
 }
 
 // GetEffectiveWorkingDir retorna el directorio de trabajo efectivo
@@ -331,158 +324,253 @@ func GetEffectiveWorkingDir(config types.GitConfig) string {
 }
 
 // GetFileSHA obtiene el SHA de un archivo específico
-func GetFileSHA(config types.GitConfig, filePath string) (string, error) {
+func GetFileSHA(ctx context.Context, config types.GitConfig, filePath string) (*FileSHAResult, error) {
 	if !config.HasGit || !config.IsGitRepo {
-		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
 	}
 
 	workingDir := GetEffectiveWorkingDir(config)
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(workingDir)
+	r := NewRunner(ctx, workingDir)
 
 	// Obtener SHA del archivo
-	cmd := exec.Command("git", "rev-parse", fmt.Sprintf("HEAD:%s", filePath))
+	cmd := r.Command("rev-parse", fmt.Sprintf("HEAD:%s", filePath))
 	output, err := cmd.Output()
 	if err != nil {
-		return "", fmt.Errorf("error obteniendo SHA del archivo %s: %v", filePath, err)
+		return nil, fmt.Errorf("error obteniendo SHA del archivo %s: %v", filePath, err)
 	}
 
 	sha := strings.TrimSpace(string(output))
-	return fmt.Sprintf("📄 Archivo: %s\n🔑 SHA: %s\n📁 Directorio: %s", filePath, sha, workingDir), nil
+	return &FileSHAResult{Path: filePath, SHA: sha, Directory: workingDir}, nil
 }
 
 // GetLastCommitSHA obtiene el SHA del último commit
-func GetLastCommitSHA(config types.GitConfig) (string, error) {
+func GetLastCommitSHA(ctx context.Context, config types.GitConfig) (*CommitSHAResult, error) {
 	if !config.HasGit || !config.IsGitRepo {
-		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
 	}
 
 	workingDir := GetEffectiveWorkingDir(config)
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(workingDir)
+	r := NewRunner(ctx, workingDir)
 
-	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd := r.Command("rev-parse", "HEAD")
 	output, err := cmd.Output()
 	if err != nil {
-		return "", fmt.Errorf("error obteniendo SHA del commit: %v", err)
+		return nil, fmt.Errorf("error obteniendo SHA del commit: %v", err)
 	}
 
 	sha := strings.TrimSpace(string(output))
-	return fmt.Sprintf("🔑 Último commit SHA: %s\n📁 Directorio: %s", sha, workingDir), nil
+	return &CommitSHAResult{SHA: sha, Directory: workingDir}, nil
 }
 
 // GetFileContent obtiene el contenido de un archivo desde Git
-func GetFileContent(config types.GitConfig, filePath, ref string) (string, error) {
+func GetFileContent(ctx context.Context, config types.GitConfig, filePath, ref string) (*FileContentResult, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	output, err := ReadFile(ctx, config, filePath, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileContentResult{Path: filePath, Ref: ref, Content: string(output)}, nil
+}
+
+// ReadFile devuelve el contenido en bruto de un archivo en una referencia Git
+func ReadFile(ctx context.Context, config types.GitConfig, filePath, ref string) ([]byte, error) {
 	if !config.HasGit || !config.IsGitRepo {
-		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
+	}
+
+	if ref == "" {
+		ref = "HEAD"
 	}
 
+	r := runnerFor(ctx, config)
+	sha, err := r.ResolveCommit(ref)
+	if err != nil {
+		return nil, err
+	}
+	output, err := r.Output("show", fmt.Sprintf("%s:%s", sha, filePath))
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo contenido del archivo %s en %s: %v", filePath, ref, err)
+	}
+	return output, nil
+}
+
+// ReadWorkingFile devuelve el contenido de un archivo tal y como está en el working directory
+func ReadWorkingFile(config types.GitConfig, filePath string) ([]byte, error) {
+	fullPath, err := workingFilePath(config, filePath)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(fullPath)
+}
+
+// StatWorkingFile devuelve la información de un archivo del working directory
+func StatWorkingFile(config types.GitConfig, filePath string) (os.FileInfo, error) {
+	fullPath, err := workingFilePath(config, filePath)
+	if err != nil {
+		return nil, err
+	}
+	return os.Stat(fullPath)
+}
+
+// OpenWorkingFile abre para lectura un archivo del working directory
+func OpenWorkingFile(config types.GitConfig, filePath string) (*os.File, error) {
+	fullPath, err := workingFilePath(config, filePath)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(fullPath)
+}
+
+// CreateWorkingFile crea (o, con overwrite, trunca) un archivo del working directory,
+// creando los directorios intermedios
+func CreateWorkingFile(config types.GitConfig, filePath string, overwrite bool) (*os.File, error) {
+	fullPath, err := workingFilePath(config, filePath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return nil, fmt.Errorf("error creando directorio: %v", err)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if overwrite {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	file, err := os.OpenFile(fullPath, flags, 0644)
+	if os.IsExist(err) {
+		return nil, fmt.Errorf("el archivo %s ya existe (usa overwrite para reemplazarlo)", filePath)
+	}
+	return file, err
+}
+
+// workingFilePath resuelve una ruta relativa al workspace sin permitir salir de él
+func workingFilePath(config types.GitConfig, filePath string) (string, error) {
 	workingDir := GetEffectiveWorkingDir(config)
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(workingDir)
+	fullPath := filepath.Join(workingDir, filepath.FromSlash(filePath))
 
+	rel, err := filepath.Rel(workingDir, fullPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("ruta fuera del workspace: %s", filePath)
+	}
+	return fullPath, nil
+}
+
+// BlobSHA devuelve el SHA del blob de un archivo en una referencia Git
+func BlobSHA(ctx context.Context, config types.GitConfig, filePath, ref string) (string, error) {
 	if ref == "" {
 		ref = "HEAD"
 	}
 
-	cmd := exec.Command("git", "show", fmt.Sprintf("%s:%s", ref, filePath))
-	output, err := cmd.Output()
+	r := runnerFor(ctx, config)
+	sha, err := r.ResolveCommit(ref)
 	if err != nil {
-		return "", fmt.Errorf("error obteniendo contenido del archivo %s en %s: %v", filePath, ref, err)
+		return "", err
 	}
-
-	return fmt.Sprintf("📄 Archivo: %s\n🌿 Ref: %s\n📝 Contenido:\n%s", filePath, ref, string(output)), nil
+	output, err := r.Output("rev-parse", "--verify", "--end-of-options", fmt.Sprintf("%s:%s", sha, filePath))
+	if err != nil {
+		return "", fmt.Errorf("error obteniendo SHA del archivo %s en %s: %v", filePath, ref, err)
+	}
+	return strings.TrimSpace(string(output)), nil
 }
 
 // GetChangedFiles obtiene lista de archivos modificados
-func GetChangedFiles(config types.GitConfig, staged bool) (string, error) {
+func GetChangedFiles(ctx context.Context, config types.GitConfig, staged bool) (*ChangedFilesResult, error) {
 	if !config.HasGit || !config.IsGitRepo {
-		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
 	}
 
 	workingDir := GetEffectiveWorkingDir(config)
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(workingDir)
+	r := NewRunner(ctx, workingDir)
 
 	var cmd *exec.Cmd
 	if staged {
-		cmd = exec.Command("git", "diff", "--cached", "--name-only")
+		cmd = r.Command("diff", "--cached", "--name-only")
 	} else {
-		cmd = exec.Command("git", "diff", "--name-only")
+		cmd = r.Command("diff", "--name-only")
 	}
 
 	output, err := cmd.Output()
 	if err != nil {
-		return "", fmt.Errorf("error obteniendo archivos modificados: %v", err)
+		return nil, fmt.Errorf("error obteniendo archivos modificados: %v", err)
 	}
 
-	status := "working directory"
-	if staged {
-		status = "staging area"
+	files := []string{}
+	if trimmed := strings.TrimSpace(string(output)); trimmed != "" {
+		files = strings.Split(trimmed, "\n")
 	}
 
-	return fmt.Sprintf("📁 Directorio: %s\n📋 Archivos modificados (%s):\n%s", workingDir, status, string(output)), nil
+	return &ChangedFilesResult{Directory: workingDir, Staged: staged, Files: files}, nil
 }
 
 // ValidateRepository verifica si el directorio es un repositorio Git válido
-func ValidateRepository(path string) (string, error) {
+func ValidateRepository(ctx context.Context, path string) (*RepositoryInfo, error) {
 	gitPath := filepath.Join(path, ".git")
 	if _, err := os.Stat(gitPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("no es un repositorio Git: %s", path)
+		return nil, fmt.Errorf("no es un repositorio Git: %s", path)
 	}
 
 	// Verificar si Git está disponible
 	if _, err := exec.LookPath("git"); err != nil {
-		return "", fmt.Errorf("Git no está disponible en el sistema")
+		return nil, fmt.Errorf("Git no está disponible en el sistema")
 	}
 
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(path)
+	r := NewRunner(ctx, path)
 
 	// Obtener información del repositorio
-	cmd := exec.Command("git", "remote", "get-url", "origin")
+	cmd := r.Command("remote", "get-url", "origin")
 	remoteOutput, _ := cmd.Output()
 	
-	cmd = exec.Command("git", "branch", "--show-current")
+	cmd = r.Command("branch", "--show-current")
 	branchOutput, _ := cmd.Output()
 
-	return fmt.Sprintf("✅ Repositorio Git válido: %s\n🌿 Rama: %s\n🔗 Remote: %s", 
-		path, 
-		strings.TrimSpace(string(branchOutput)), 
-		strings.TrimSpace(string(remoteOutput))), nil
+	return &RepositoryInfo{
+		Path:   path,
+		Branch: strings.TrimSpace(string(branchOutput)),
+		Remote: strings.TrimSpace(string(remoteOutput)),
+	}, nil
 }
 
 // ListFiles lista todos los archivos en el repositorio
-func ListFiles(config types.GitConfig, ref string) (string, error) {
-	if !config.HasGit || !config.IsGitRepo {
-		return "", fmt.Errorf("Git no disponible o no es un repositorio Git")
+func ListFiles(ctx context.Context, config types.GitConfig, ref string) (*FileListResult, error) {
+	if ref == "" {
+		ref = "HEAD"
 	}
 
-	workingDir := GetEffectiveWorkingDir(config)
-	originalDir, _ := os.Getwd()
-	defer os.Chdir(originalDir)
-	os.Chdir(workingDir)
+	paths, err := ListFilePaths(ctx, config, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileListResult{Directory: GetEffectiveWorkingDir(config), Ref: ref, Total: len(paths), Files: paths}, nil
+}
+
+// ListFilePaths devuelve las rutas de todos los archivos en una referencia Git
+func ListFilePaths(ctx context.Context, config types.GitConfig, ref string) ([]string, error) {
+	if !config.HasGit || !config.IsGitRepo {
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
+	}
 
 	if ref == "" {
 		ref = "HEAD"
 	}
 
-	cmd := exec.Command("git", "ls-tree", "--name-only", "-r", ref)
-	output, err := cmd.Output()
+	r := runnerFor(ctx, config)
+	sha, err := r.ResolveCommit(ref)
+	if err != nil {
+		return nil, err
+	}
+	output, err := r.Output("ls-tree", "--name-only", "-r", sha)
 	if err != nil {
-		return "", fmt.Errorf("error listando archivos en %s: %v", ref, err)
+		return nil, fmt.Errorf("error listando archivos en %s: %v", ref, err)
 	}
 
 	files := strings.TrimSpace(string(output))
-	fileCount := strings.Count(files, "\n") + 1
 	if files == "" {
-		fileCount = 0
+		return []string{}, nil
 	}
-
-	return fmt.Sprintf("📁 Directorio: %s\n🌿 Ref: %s\n📊 Total archivos: %d\n\n📄 Archivos:\n%s", workingDir, ref, fileCount, files), nil
+	return strings.Split(files, "\n"), nil
 }
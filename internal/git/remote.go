@@ -0,0 +1,88 @@
+package git
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// DefaultGitHubHost es el host de los remotos de github.com
+const DefaultGitHubHost = "github.com"
+
+// DefaultRemote es el remoto del que se deducen owner y repositorio si no se indica otro
+const DefaultRemote = "origin"
+
+// ParseRemoteURL extrae host, owner y repositorio de una URL de remoto Git.
+// Admite los formatos https://host/owner/repo(.git), [usuario@]host:owner/repo(.git) y
+// ssh://git@host[:puerto]/owner/repo(.git).
+func ParseRemoteURL(remote string) (host, owner, repo string, ok bool) {
+	remote = strings.TrimSpace(remote)
+	var remoteHost, repoPath string
+
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" && u.Host != "" {
+		// https://host/owner/repo, ssh://git@host:22/owner/repo
+		remoteHost, repoPath = u.Hostname(), u.Path
+	} else if hostPart, pathPart, found := strings.Cut(remote, ":"); found && !strings.Contains(remote, "://") {
+		// git@host:owner/repo o host:owner/repo (sintaxis scp). Como en git, si hay una barra
+		// antes de ":" es una ruta local, y una sola letra es una unidad de Windows (C:\repo)
+		if strings.ContainsAny(hostPart, `/\`) || len(hostPart) == 1 {
+			return "", "", "", false
+		}
+		if at := strings.LastIndex(hostPart, "@"); at >= 0 {
+			hostPart = hostPart[at+1:]
+		}
+		remoteHost, repoPath = hostPart, pathPart
+	} else {
+		return "", "", "", false
+	}
+
+	parts := strings.Split(strings.Trim(repoPath, "/"), "/")
+	if remoteHost == "" || len(parts) != 2 || parts[0] == "" || strings.TrimSuffix(parts[1], ".git") == "" {
+		return "", "", "", false
+	}
+	return strings.ToLower(remoteHost), parts[0], strings.TrimSuffix(parts[1], ".git"), true
+}
+
+// remoteName devuelve el remoto configurado (origin por defecto) al que van push, pull y tags
+func remoteName(config types.GitConfig) string {
+	if config.RemoteName == "" {
+		return DefaultRemote
+	}
+	return config.RemoteName
+}
+
+// loadRemote lee la URL del remoto configurado (origin por defecto) y deduce host, owner y repositorio
+func loadRemote(r Runner, config *types.GitConfig) error {
+	if config.RemoteName == "" {
+		config.RemoteName = DefaultRemote
+	}
+
+	// Sin remoto no deben quedar los datos del workspace anterior
+	config.RemoteURL, config.RemoteHost, config.Owner, config.Repo = "", "", "", ""
+
+	output, err := r.Command("remote", "get-url", config.RemoteName).Output()
+	if err != nil {
+		if config.RemoteName != DefaultRemote {
+			return fmt.Errorf("el remoto '%s' no existe en %s", config.RemoteName, r.Dir)
+		}
+		return nil
+	}
+
+	config.RemoteURL = strings.TrimSpace(string(output))
+	config.RemoteHost, config.Owner, config.Repo, _ = ParseRemoteURL(config.RemoteURL)
+	return nil
+}
+
+// RemoteRepository devuelve owner y repositorio del remoto si apunta al host de GitHub del perfil
+func RemoteRepository(config types.GitConfig) (owner, repo string, ok bool) {
+	host := config.GitHubHost
+	if host == "" {
+		host = DefaultGitHubHost
+	}
+	if config.Owner == "" || !strings.EqualFold(config.RemoteHost, host) {
+		return "", "", false
+	}
+	return config.Owner, config.Repo, true
+}
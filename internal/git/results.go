@@ -0,0 +1,214 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// Resultados de las operaciones Git. Los campos exportados se devuelven como
+// structuredContent de las herramientas MCP; String() genera el texto legible.
+// Los resultados sin String() se muestran como JSON indentado.
+
+// StatusResult es el estado del repositorio local
+type StatusResult struct {
+	GitConfig     types.GitConfig `json:"gitConfig"`
+	Message       string          `json:"message,omitempty"`
+	Status        string          `json:"status,omitempty"`
+	RecentCommits string          `json:"recentCommits,omitempty"`
+}
+
+// AddResult son los archivos agregados al staging
+type AddResult struct {
+	Files     []string `json:"files"`
+	Directory string   `json:"directory"`
+}
+
+func (r *AddResult) String() string {
+	return fmt.Sprintf("✅ Archivos agregados al staging: %s\n📁 Directorio: %s", strings.Join(r.Files, ", "), r.Directory)
+}
+
+// CommitResult es el commit creado
+type CommitResult struct {
+	SHA       string `json:"sha"`
+	Message   string `json:"message"`
+	Directory string `json:"directory"`
+	Output    string `json:"output"`
+}
+
+func (r *CommitResult) String() string {
+	return fmt.Sprintf("✅ Commit realizado: %s\n🔑 SHA: %s\n📁 Directorio: %s\n📝 Output: %s", r.Message, r.SHA, r.Directory, r.Output)
+}
+
+// PushResult es el resultado de un push
+type PushResult struct {
+	Branch    string `json:"branch"`
+	Directory string `json:"directory"`
+	Output    string `json:"output"`
+}
+
+func (r *PushResult) String() string {
+	return fmt.Sprintf("🚀 Push realizado a rama: %s\n📁 Directorio: %s\n📝 Output: %s", r.Branch, r.Directory, r.Output)
+}
+
+// PullResult es el resultado de un pull
+type PullResult struct {
+	Branch string `json:"branch"`
+	Output string `json:"output"`
+}
+
+func (r *PullResult) String() string {
+	return fmt.Sprintf("Pull realizado desde rama: %s", r.Branch)
+}
+
+// CheckoutResult es la rama activa tras un checkout
+type CheckoutResult struct {
+	Branch  string `json:"branch"`
+	Created bool   `json:"created"`
+}
+
+func (r *CheckoutResult) String() string {
+	return fmt.Sprintf("Checkout a rama: %s (crear: %v)", r.Branch, r.Created)
+}
+
+// RepositoryInfo describe un repositorio local validado
+type RepositoryInfo struct {
+	Path   string `json:"path"`
+	Branch string `json:"branch"`
+	Remote string `json:"remote"`
+}
+
+func (r *RepositoryInfo) String() string {
+	return fmt.Sprintf("✅ Repositorio Git válido: %s\n🌿 Rama: %s\n🔗 Remote: %s", r.Path, r.Branch, r.Remote)
+}
+
+// WorkspaceResult es el workspace configurado con git_set_workspace
+type WorkspaceResult struct {
+	Path       string `json:"path"`
+	Branch     string `json:"branch"`
+	RemoteName string `json:"remoteName"`
+	Remote     string `json:"remote"`
+	Owner      string `json:"owner,omitempty"` // deducidos del remoto
+	Repo       string `json:"repo,omitempty"`
+}
+
+func (r *WorkspaceResult) String() string {
+	text := fmt.Sprintf("✅ Workspace configurado: %s\n🌿 Rama: %s\n🔗 Remote (%s): %s", r.Path, r.Branch, r.RemoteName, r.Remote)
+	if r.Owner != "" {
+		text += fmt.Sprintf("\n📦 Repositorio: %s/%s", r.Owner, r.Repo)
+	}
+	return text
+}
+
+// FileSHAResult es el SHA del blob de un archivo en HEAD
+type FileSHAResult struct {
+	Path      string `json:"path"`
+	SHA       string `json:"sha"`
+	Directory string `json:"directory"`
+}
+
+func (r *FileSHAResult) String() string {
+	return fmt.Sprintf("📄 Archivo: %s\n🔑 SHA: %s\n📁 Directorio: %s", r.Path, r.SHA, r.Directory)
+}
+
+// CommitSHAResult es el SHA del último commit
+type CommitSHAResult struct {
+	SHA       string `json:"sha"`
+	Directory string `json:"directory"`
+}
+
+func (r *CommitSHAResult) String() string {
+	return fmt.Sprintf("🔑 Último commit SHA: %s\n📁 Directorio: %s", r.SHA, r.Directory)
+}
+
+// FileContentResult es el contenido de un archivo en una referencia
+type FileContentResult struct {
+	Path    string `json:"path"`
+	Ref     string `json:"ref"`
+	Content string `json:"content"`
+}
+
+func (r *FileContentResult) String() string {
+	return fmt.Sprintf("📄 Archivo: %s\n🌿 Ref: %s\n📝 Contenido:\n%s", r.Path, r.Ref, r.Content)
+}
+
+// ChangedFilesResult son los archivos modificados en working directory o staging
+type ChangedFilesResult struct {
+	Directory string   `json:"directory"`
+	Staged    bool     `json:"staged"`
+	Files     []string `json:"files"`
+}
+
+func (r *ChangedFilesResult) String() string {
+	status := "working directory"
+	if r.Staged {
+		status = "staging area"
+	}
+	return fmt.Sprintf("📁 Directorio: %s\n📋 Archivos modificados (%s):\n%s", r.Directory, status, strings.Join(r.Files, "\n"))
+}
+
+// FileListResult son los archivos versionados en una referencia
+type FileListResult struct {
+	Directory string   `json:"directory"`
+	Ref       string   `json:"ref"`
+	Total     int      `json:"total"`
+	Files     []string `json:"files"`
+}
+
+func (r *FileListResult) String() string {
+	return fmt.Sprintf("📁 Directorio: %s\n🌿 Ref: %s\n📊 Total archivos: %d\n\n📄 Archivos:\n%s", r.Directory, r.Ref, r.Total, strings.Join(r.Files, "\n"))
+}
+
+// LogAnalysisResult es el análisis del historial
+type LogAnalysisResult struct {
+	GraphLog      string `json:"graphLog,omitempty"`
+	AuthorStats   string `json:"authorStats,omitempty"`
+	RecentCommits string `json:"recentCommits,omitempty"`
+}
+
+// DiffFilesResult son los archivos modificados con estadísticas
+type DiffFilesResult struct {
+	Files     string `json:"files,omitempty"`
+	Stats     string `json:"stats,omitempty"`
+	Untracked string `json:"untracked,omitempty"`
+}
+
+// BranchListResult son las ramas del repositorio
+type BranchListResult struct {
+	LocalBranches  string `json:"localBranches,omitempty"`
+	RemoteBranches string `json:"remoteBranches,omitempty"`
+	AllBranches    string `json:"allBranches,omitempty"`
+	CurrentBranch  string `json:"currentBranch,omitempty"`
+	BranchCommits  string `json:"branchCommits,omitempty"`
+}
+
+// OperationResult es el resultado de las operaciones de stash, remotos y tags
+type OperationResult struct {
+	Operation string `json:"operation"`
+	Target    string `json:"target,omitempty"`
+	Summary   string `json:"summary"`
+	Output    string `json:"output,omitempty"`
+}
+
+func (r *OperationResult) String() string {
+	if r.Output == "" {
+		return r.Summary
+	}
+	return fmt.Sprintf("%s:\n%s", r.Summary, r.Output)
+}
+
+// CleanResult son los archivos eliminados (o que se eliminarían) por git clean
+type CleanResult struct {
+	Operation string   `json:"operation"`
+	DryRun    bool     `json:"dryRun"`
+	Files     []string `json:"files"`
+	summary   string
+}
+
+func (r *CleanResult) String() string {
+	if len(r.Files) == 0 {
+		return r.summary + "\nNo hay archivos para procesar"
+	}
+	return fmt.Sprintf("%s\n%s", r.summary, strings.Join(r.Files, "\n"))
+}
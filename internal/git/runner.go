@@ -0,0 +1,89 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// waitDelay es el margen que se da a git para cerrar sus pipes tras matarlo
+const waitDelay = 5 * time.Second
+
+// Runner ejecuta comandos git en un directorio concreto.
+// Cada comando recibe su propio directorio de trabajo (exec.Cmd.Dir), de modo
+// que varias peticiones pueden ejecutarse en paralelo sin tocar el cwd del proceso.
+// Si el contexto se cancela, el proceso git (y sus hijos) se terminan.
+type Runner struct {
+	Dir string
+	ctx context.Context
+}
+
+// NewRunner crea un runner para el directorio indicado
+func NewRunner(ctx context.Context, dir string) Runner {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return Runner{Dir: dir, ctx: ctx}
+}
+
+// runnerFor crea un runner sobre el directorio de trabajo efectivo de la configuración
+func runnerFor(ctx context.Context, config types.GitConfig) Runner {
+	return NewRunner(ctx, GetEffectiveWorkingDir(config))
+}
+
+// Command prepara un comando git con el directorio y el contexto del runner
+func (r Runner) Command(args ...string) *exec.Cmd {
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = r.Dir
+	// Nunca pedir credenciales por terminal: no hay nadie para responder
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.WaitDelay = waitDelay
+	configureProcessGroup(cmd)
+	return cmd
+}
+
+// Output ejecuta git y devuelve stdout
+func (r Runner) Output(args ...string) ([]byte, error) {
+	return r.Command(args...).Output()
+}
+
+// CombinedOutput ejecuta git y devuelve stdout y stderr combinados
+func (r Runner) CombinedOutput(args ...string) ([]byte, error) {
+	return r.Command(args...).CombinedOutput()
+}
+
+// ResolveCommit resuelve una referencia (rama, tag, SHA, HEAD~1...) al SHA del commit.
+// Las referencias que empiezan por '-' se rechazan para que no se interpreten como
+// opciones de git; los comandos deben usar el SHA devuelto, nunca la referencia original.
+func (r Runner) ResolveCommit(ref string) (string, error) {
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return "", fmt.Errorf("referencia Git no válida: %q", ref)
+	}
+	output, err := r.Output("rev-parse", "--verify", "--quiet", "--end-of-options", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("referencia Git desconocida: %s", ref)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// CheckTagName comprueba que tag sea un nombre de tag válido (git check-ref-format).
+// Los nombres que empiezan por '-' se rechazan para que no se interpreten como opciones de git.
+func (r Runner) CheckTagName(tag string) error {
+	if tag == "" || strings.HasPrefix(tag, "-") {
+		return fmt.Errorf("nombre de tag no válido: %q", tag)
+	}
+	if _, err := r.Output("check-ref-format", "refs/tags/"+tag); err != nil {
+		return fmt.Errorf("nombre de tag no válido: %q", tag)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// newTestRepo crea un repositorio con un commit en un directorio temporal
+func newTestRepo(t *testing.T) Runner {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git no está instalado")
+	}
+
+	dir := t.TempDir()
+	r := NewRunner(context.Background(), dir)
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+	} {
+		if output, err := r.CombinedOutput(args...); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"add", "a.txt"}, {"commit", "-q", "-m", "first"}, {"tag", "v1"}} {
+		if output, err := r.CombinedOutput(args...); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	return r
+}
+
+func TestResolveCommit(t *testing.T) {
+	r := newTestRepo(t)
+	head, err := r.Output("rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.TrimSpace(string(head))
+
+	for _, ref := range []string{"HEAD", "main", "v1", want, want[:7]} {
+		got, err := r.ResolveCommit(ref)
+		if err != nil {
+			t.Errorf("ResolveCommit(%q): %v", ref, err)
+		} else if got != want {
+			t.Errorf("ResolveCommit(%q) = %s, want %s", ref, got, want)
+		}
+	}
+
+	outside := filepath.Join(t.TempDir(), "out")
+	for _, ref := range []string{"", "--output=" + outside, "-p", "missing", "HEAD:a.txt"} {
+		if got, err := r.ResolveCommit(ref); err == nil {
+			t.Errorf("ResolveCommit(%q) = %s, want error", ref, got)
+		}
+	}
+	if _, err := os.Stat(outside); !os.IsNotExist(err) {
+		t.Errorf("a ref wrote %s", outside)
+	}
+}
+
+// Las operaciones que aceptan una ref del cliente la resuelven antes de pasarla a git
+func TestRefArguments(t *testing.T) {
+	r := newTestRepo(t)
+	config := types.GitConfig{HasGit: true, IsGitRepo: true, WorkspacePath: r.Dir}
+	ctx := context.Background()
+
+	operations := []struct {
+		name string
+		run  func(ref string) (string, error)
+	}{
+		{name: "ReadFile", run: func(ref string) (string, error) {
+			content, err := ReadFile(ctx, config, "a.txt", ref)
+			return string(content), err
+		}},
+		{name: "BlobSHA", run: func(ref string) (string, error) {
+			return BlobSHA(ctx, config, "a.txt", ref)
+		}},
+		{name: "ListFilePaths", run: func(ref string) (string, error) {
+			files, err := ListFilePaths(ctx, config, ref)
+			return strings.Join(files, ","), err
+		}},
+	}
+
+	for _, op := range operations {
+		t.Run(op.name, func(t *testing.T) {
+			for _, ref := range []string{"", "main", "v1"} {
+				if got, err := op.run(ref); err != nil || got == "" {
+					t.Errorf("ref %q: %q, %v", ref, got, err)
+				}
+			}
+
+			outside := filepath.Join(t.TempDir(), "out")
+			for _, ref := range []string{"--output=" + outside, "-p", "missing"} {
+				if got, err := op.run(ref); err == nil {
+					t.Errorf("ref %q = %q, want error", ref, got)
+				}
+			}
+			if _, err := os.Stat(outside); !os.IsNotExist(err) {
+				t.Errorf("a ref wrote %s", outside)
+			}
+		})
+	}
+}
+
+func TestCheckTagName(t *testing.T) {
+	r := newTestRepo(t)
+
+	for _, tag := range []string{"v1.2.0", "release/1.0", "v2.0.0-rc.1"} {
+		if err := r.CheckTagName(tag); err != nil {
+			t.Errorf("CheckTagName(%q): %v", tag, err)
+		}
+	}
+	for _, tag := range []string{"", "-f", "--delete", "a..b", "a b", "v1~1", "v1^", "x.lock", "a:b", "/v1", "v1/"} {
+		if err := r.CheckTagName(tag); err == nil {
+			t.Errorf("CheckTagName(%q): no error", tag)
+		}
+	}
+}
+
+// Las operaciones con tags validan el nombre y van al remoto configurado, no siempre a origin
+func TestTagOperations(t *testing.T) {
+	r := newTestRepo(t)
+	remote := t.TempDir()
+	if output, err := NewRunner(context.Background(), remote).CombinedOutput("init", "-q", "--bare"); err != nil {
+		t.Fatalf("git init: %v\n%s", err, output)
+	}
+	if output, err := r.CombinedOutput("remote", "add", "upstream", remote); err != nil {
+		t.Fatalf("git remote add: %v\n%s", err, output)
+	}
+	config := types.GitConfig{HasGit: true, IsGitRepo: true, WorkspacePath: r.Dir, RemoteName: "upstream", CurrentBranch: "main"}
+	ctx := context.Background()
+
+	for _, operation := range []string{"create", "delete", "push", "show"} {
+		for _, tag := range []string{"-d", "--all", "a..b"} {
+			if _, err := TagOperations(ctx, config, operation, tag, "msg"); err == nil {
+				t.Errorf("%s %q: no error", operation, tag)
+			}
+		}
+	}
+	if TagExists(ctx, config, "-d") || TagExists(ctx, config, "a..b") {
+		t.Error("invalid tags exist")
+	}
+
+	if _, err := TagOperations(ctx, config, "create", "v2", "Release v2"); err != nil {
+		t.Fatal(err)
+	}
+	if !TagExists(ctx, config, "v2") {
+		t.Fatal("v2 not created")
+	}
+	result, err := TagOperations(ctx, config, "push", "v2", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result.Summary, "upstream") {
+		t.Errorf("summary = %q, want remote upstream", result.Summary)
+	}
+	if _, err := Push(ctx, config, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err := NewRunner(ctx, remote).Output("for-each-ref", "--format=%(refname)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Fields(string(refs)); strings.Join(got, " ") != "refs/heads/main refs/tags/v2" {
+		t.Errorf("remote refs = %q, want main and v2", got)
+	}
+}
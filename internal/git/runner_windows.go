@@ -0,0 +1,8 @@
+//go:build windows
+
+package git
+
+import "os/exec"
+
+// configureProcessGroup no hace nada en Windows: exec.CommandContext ya mata el proceso git
+func configureProcessGroup(cmd *exec.Cmd) {}
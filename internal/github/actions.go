@@ -0,0 +1,236 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// RunFilter son los filtros de ListWorkflowRuns. Los campos vacíos no filtran.
+type RunFilter struct {
+	Workflow string // ID o nombre de archivo (ci.yml) del workflow; vacío = todos
+	Branch   string
+	Event    string // push, pull_request, workflow_dispatch...
+	Status   string // queued, in_progress, completed, success, failure...
+	HeadSHA  string
+	PageOptions
+}
+
+// ListWorkflows lista los workflows de GitHub Actions de un repositorio
+func ListWorkflows(client *github.Client, ctx context.Context, owner, repoName string, opts PageOptions) (*WorkflowList, error) {
+	result := &WorkflowList{}
+	workflows, next, err := paginate(opts, func(page github.ListOptions) ([]WorkflowSummary, *github.Response, error) {
+		workflows, resp, err := client.Actions.ListWorkflows(ctx, owner, repoName, &page)
+		if err != nil {
+			return nil, nil, err
+		}
+		result.TotalCount = workflows.GetTotalCount()
+		summaries := make([]WorkflowSummary, 0, len(workflows.Workflows))
+		for _, workflow := range workflows.Workflows {
+			summaries = append(summaries, WorkflowSummary{
+				ID:    workflow.GetID(),
+				Name:  workflow.GetName(),
+				Path:  workflow.GetPath(),
+				State: workflow.GetState(),
+				URL:   workflow.GetHTMLURL(),
+			})
+		}
+		return summaries, resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Workflows, result.NextCursor = workflows, next
+	return result, nil
+}
+
+// ListWorkflowRuns lista las ejecuciones de workflows, de todo el repositorio o de un workflow
+func ListWorkflowRuns(client *github.Client, ctx context.Context, owner, repoName string, filter RunFilter) (*RunList, error) {
+	opt := &github.ListWorkflowRunsOptions{
+		Branch:  filter.Branch,
+		Event:   filter.Event,
+		Status:  filter.Status,
+		HeadSHA: filter.HeadSHA,
+	}
+
+	result := &RunList{}
+	runs, next, err := paginate(filter.PageOptions, func(page github.ListOptions) ([]RunSummary, *github.Response, error) {
+		opt.ListOptions = page
+		var runs *github.WorkflowRuns
+		var resp *github.Response
+		var err error
+		if filter.Workflow == "" {
+			runs, resp, err = client.Actions.ListRepositoryWorkflowRuns(ctx, owner, repoName, opt)
+		} else if id, convErr := strconv.ParseInt(filter.Workflow, 10, 64); convErr == nil {
+			runs, resp, err = client.Actions.ListWorkflowRunsByID(ctx, owner, repoName, id, opt)
+		} else {
+			runs, resp, err = client.Actions.ListWorkflowRunsByFileName(ctx, owner, repoName, filter.Workflow, opt)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		result.TotalCount = runs.GetTotalCount()
+		summaries := make([]RunSummary, 0, len(runs.WorkflowRuns))
+		for _, run := range runs.WorkflowRuns {
+			summaries = append(summaries, runSummary(run))
+		}
+		return summaries, resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Runs, result.NextCursor = runs, next
+	return result, nil
+}
+
+// GetWorkflowRun obtiene una ejecución con sus jobs y steps (del último intento)
+func GetWorkflowRun(client *github.Client, ctx context.Context, owner, repoName string, runID int64) (*RunDetail, error) {
+	run, _, err := client.Actions.GetWorkflowRunByID(ctx, owner, repoName, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := listJobs(client, ctx, owner, repoName, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RunDetail{RunSummary: runSummary(run), Jobs: []JobSummary{}}
+	for _, job := range jobs {
+		result.Jobs = append(result.Jobs, jobSummary(job))
+	}
+	return result, nil
+}
+
+// RerunWorkflowRun vuelve a ejecutar los jobs fallidos de una ejecución (o todos con all)
+func RerunWorkflowRun(client *github.Client, ctx context.Context, owner, repoName string, runID int64, all bool) (*RunAction, error) {
+	action, rerun := "rerun_failed_jobs", client.Actions.RerunFailedJobsByID
+	if all {
+		action, rerun = "rerun", client.Actions.RerunWorkflowByID
+	}
+	if _, err := rerun(ctx, owner, repoName, runID); err != nil && !isAccepted(err) {
+		return nil, err
+	}
+	return &RunAction{RunID: runID, Action: action}, nil
+}
+
+// CancelWorkflowRun cancela una ejecución en curso
+func CancelWorkflowRun(client *github.Client, ctx context.Context, owner, repoName string, runID int64) (*RunAction, error) {
+	if _, err := client.Actions.CancelWorkflowRunByID(ctx, owner, repoName, runID); err != nil && !isAccepted(err) {
+		return nil, err
+	}
+	return &RunAction{RunID: runID, Action: "cancel"}, nil
+}
+
+// DispatchWorkflow lanza un workflow con el evento workflow_dispatch. Sin ref se usa
+// la rama por defecto del repositorio.
+func DispatchWorkflow(client *github.Client, ctx context.Context, owner, repoName, workflow, ref string, inputs map[string]interface{}) (*WorkflowDispatch, error) {
+	if ref == "" {
+		repo, _, err := client.Repositories.Get(ctx, owner, repoName)
+		if err != nil {
+			return nil, err
+		}
+		ref = repo.GetDefaultBranch()
+	}
+
+	event := github.CreateWorkflowDispatchEventRequest{Ref: ref, Inputs: inputs}
+	var err error
+	if id, convErr := strconv.ParseInt(workflow, 10, 64); convErr == nil {
+		_, err = client.Actions.CreateWorkflowDispatchEventByID(ctx, owner, repoName, id, event)
+	} else {
+		_, err = client.Actions.CreateWorkflowDispatchEventByFileName(ctx, owner, repoName, workflow, event)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	inputNames := []string{}
+	for name := range inputs {
+		inputNames = append(inputNames, name)
+	}
+	sort.Strings(inputNames)
+	return &WorkflowDispatch{Workflow: workflow, Ref: ref, Inputs: inputNames}, nil
+}
+
+// listJobs devuelve todos los jobs del último intento de una ejecución
+func listJobs(client *github.Client, ctx context.Context, owner, repoName string, runID int64) ([]*github.WorkflowJob, error) {
+	var jobs []*github.WorkflowJob
+	opt := &github.ListWorkflowJobsOptions{Filter: "latest", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		page, resp, err := client.Actions.ListWorkflowJobs(ctx, owner, repoName, runID, opt)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, page.Jobs...)
+		if resp.NextPage == 0 {
+			return jobs, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+// isAccepted indica una respuesta 202: la API acepta la operación y la hace en segundo plano
+func isAccepted(err error) bool {
+	var accepted *github.AcceptedError
+	return errors.As(err, &accepted)
+}
+
+// isFailedConclusion indica si la conclusión de un job o step es un fallo
+func isFailedConclusion(conclusion string) bool {
+	switch conclusion {
+	case "failure", "cancelled", "timed_out", "startup_failure", "action_required":
+		return true
+	}
+	return false
+}
+
+func runSummary(run *github.WorkflowRun) RunSummary {
+	return RunSummary{
+		ID:         run.GetID(),
+		Name:       run.GetName(),
+		WorkflowID: run.GetWorkflowID(),
+		RunNumber:  run.GetRunNumber(),
+		Attempt:    run.GetRunAttempt(),
+		Event:      run.GetEvent(),
+		Branch:     run.GetHeadBranch(),
+		HeadSHA:    run.GetHeadSHA(),
+		Status:     run.GetStatus(),
+		Conclusion: run.GetConclusion(),
+		Actor:      run.GetActor().GetLogin(),
+		URL:        run.GetHTMLURL(),
+		CreatedAt:  run.GetCreatedAt().Format(time.RFC3339),
+		UpdatedAt:  run.GetUpdatedAt().Format(time.RFC3339),
+	}
+}
+
+func jobSummary(job *github.WorkflowJob) JobSummary {
+	summary := JobSummary{
+		ID:         job.GetID(),
+		Name:       job.GetName(),
+		Status:     job.GetStatus(),
+		Conclusion: job.GetConclusion(),
+		URL:        job.GetHTMLURL(),
+		Steps:      []StepSummary{},
+	}
+	if job.StartedAt != nil {
+		summary.StartedAt = job.GetStartedAt().Format(time.RFC3339)
+	}
+	if job.CompletedAt != nil {
+		summary.CompletedAt = job.GetCompletedAt().Format(time.RFC3339)
+	}
+	for _, step := range job.Steps {
+		summary.Steps = append(summary.Steps, StepSummary{
+			Number:     step.GetNumber(),
+			Name:       step.GetName(),
+			Status:     step.GetStatus(),
+			Conclusion: step.GetConclusion(),
+		})
+	}
+	return summary
+}
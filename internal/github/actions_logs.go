@@ -0,0 +1,351 @@
+package github
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// Límites de los logs: tamaño del zip que se descarga en memoria y bytes que se
+// descomprimen de él, sumando todas las entradas leídas
+const (
+	maxLogArchiveSize  = 64 << 20
+	maxLogExpandedSize = 256 << 20
+)
+
+// LogOptions seleccionan qué parte de los logs de una ejecución se devuelve.
+// Por defecto: los steps fallidos de los jobs fallidos, últimas 200 líneas de cada uno.
+type LogOptions struct {
+	JobID int64  // solo este job; 0 = los jobs fallidos (o todos si ninguno ha fallado)
+	Step  int    // solo este step (número); 0 = los steps fallidos (o el job completo)
+	Grep  string // expresión regular: solo las líneas que coinciden
+	Tail  int    // últimas N líneas de cada fragmento; 0 = todas
+}
+
+// GetRunLogs descarga el zip de logs de una ejecución y extrae los fragmentos de los
+// jobs y steps seleccionados. Sin runID se usa la ejecución del job indicado.
+func GetRunLogs(client *github.Client, ctx context.Context, owner, repoName string, runID int64, opts LogOptions) (*RunLogs, error) {
+	var grep *regexp.Regexp
+	if opts.Grep != "" {
+		var err error
+		if grep, err = regexp.Compile(opts.Grep); err != nil {
+			return nil, fmt.Errorf("invalid grep pattern: %v", err)
+		}
+	}
+
+	if runID == 0 {
+		if opts.JobID == 0 {
+			return nil, fmt.Errorf("run_id or job_id required")
+		}
+		job, _, err := client.Actions.GetWorkflowJobByID(ctx, owner, repoName, opts.JobID)
+		if err != nil {
+			return nil, err
+		}
+		runID = job.GetRunID()
+	}
+
+	jobs, err := listJobs(client, ctx, owner, repoName, runID)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err = selectJobs(jobs, opts.JobID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Las ejecuciones en curso no tienen zip: se usan los logs de cada job
+	archive, err := downloadRunLogs(client, ctx, owner, repoName, runID)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	result := &RunLogs{RunID: runID, Excerpts: []LogExcerpt{}}
+	for _, job := range jobs {
+		steps, err := selectSteps(job, opts.Step)
+		if err != nil {
+			return nil, err
+		}
+
+		jobLog, stepFiles, err := findJobLogs(archive, job.GetName())
+		if err != nil {
+			return nil, err
+		}
+		if jobLog == "" && len(stepFiles) == 0 {
+			if jobLog, err = downloadJobLog(client, ctx, owner, repoName, job.GetID()); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(steps) == 0 {
+			result.Excerpts = append(result.Excerpts, newExcerpt(job, nil, jobLog, grep, opts.Tail))
+			continue
+		}
+		for _, step := range steps {
+			text, ok := stepFiles[step.GetNumber()]
+			if !ok {
+				text = sliceStepLog(jobLog, step)
+			}
+			result.Excerpts = append(result.Excerpts, newExcerpt(job, step, text, grep, opts.Tail))
+		}
+	}
+	return result, nil
+}
+
+// selectJobs elige el job pedido o, sin él, los jobs fallidos (todos si ninguno ha fallado)
+func selectJobs(jobs []*github.WorkflowJob, jobID int64) ([]*github.WorkflowJob, error) {
+	if jobID != 0 {
+		for _, job := range jobs {
+			if job.GetID() == jobID {
+				return []*github.WorkflowJob{job}, nil
+			}
+		}
+		return nil, fmt.Errorf("job %d not found in the latest attempt of the run", jobID)
+	}
+
+	var failed []*github.WorkflowJob
+	for _, job := range jobs {
+		if isFailedConclusion(job.GetConclusion()) {
+			failed = append(failed, job)
+		}
+	}
+	if len(failed) > 0 {
+		return failed, nil
+	}
+	return jobs, nil
+}
+
+// selectSteps elige el step pedido o los steps fallidos; nil significa el job completo
+func selectSteps(job *github.WorkflowJob, number int) ([]*github.TaskStep, error) {
+	if number != 0 {
+		for _, step := range job.Steps {
+			if step.GetNumber() == int64(number) {
+				return []*github.TaskStep{step}, nil
+			}
+		}
+		return nil, fmt.Errorf("step %d not found in job %q", number, job.GetName())
+	}
+
+	var failed []*github.TaskStep
+	for _, step := range job.Steps {
+		if isFailedConclusion(step.GetConclusion()) {
+			failed = append(failed, step)
+		}
+	}
+	return failed, nil
+}
+
+// downloadRunLogs descarga el zip de logs de una ejecución
+func downloadRunLogs(client *github.Client, ctx context.Context, owner, repoName string, runID int64) (*logArchive, error) {
+	logURL, _, err := client.Actions.GetWorkflowRunLogs(ctx, owner, repoName, runID, 1)
+	if err != nil {
+		return nil, err
+	}
+	data, err := download(client, ctx, logURL.String())
+	if err != nil {
+		return nil, err
+	}
+	return newLogArchive(data)
+}
+
+// logArchive es el zip de logs de una ejecución. Las entradas se descomprimen al
+// leerlas, con un único límite de bytes descomprimidos para todo el archivo.
+type logArchive struct {
+	files  []*zip.File
+	budget int64 // bytes que aún se pueden descomprimir
+}
+
+func newLogArchive(data []byte) (*logArchive, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("reading logs archive: %v", err)
+	}
+	return &logArchive{files: reader.File, budget: maxLogExpandedSize}, nil
+}
+
+// read descomprime una entrada y la descuenta del límite del archivo
+func (a *logArchive) read(file *zip.File) (string, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return "", fmt.Errorf("reading %s from logs archive: %v", file.Name, err)
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, a.budget+1))
+	if err != nil {
+		return "", fmt.Errorf("reading %s from logs archive: %v", file.Name, err)
+	}
+	if int64(len(content)) > a.budget {
+		return "", fmt.Errorf("logs archive expands to more than %d MB; use job_id and step to read less", maxLogExpandedSize>>20)
+	}
+	a.budget -= int64(len(content))
+	return string(content), nil
+}
+
+// downloadJobLog descarga el log en texto plano de un job
+func downloadJobLog(client *github.Client, ctx context.Context, owner, repoName string, jobID int64) (string, error) {
+	logURL, _, err := client.Actions.GetWorkflowJobLogs(ctx, owner, repoName, jobID, 1)
+	if err != nil {
+		return "", err
+	}
+	data, err := download(client, ctx, logURL.String())
+	return string(data), err
+}
+
+// download obtiene una URL firmada de descarga (el token no se envía a hosts externos)
+func download(client *github.Client, ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading logs: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxLogArchiveSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxLogArchiveSize {
+		return nil, fmt.Errorf("logs larger than %d MB; use job_id to download a single job", maxLogArchiveSize>>20)
+	}
+	return data, nil
+}
+
+// findJobLogs busca en el zip el log completo del job ("N_job.txt") y los de sus
+// steps ("job/N_step.txt"). Los nombres del zip omiten caracteres no válidos en rutas.
+// Solo se descomprimen las entradas del job.
+func findJobLogs(archive *logArchive, jobName string) (string, map[int64]string, error) {
+	want := logFileName(jobName)
+	var jobLog string
+	steps := map[int64]string{}
+	if archive == nil {
+		return jobLog, steps, nil
+	}
+
+	for _, file := range archive.files {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		dir, name, nested := strings.Cut(file.Name, "/")
+		var number int64
+		if nested {
+			var ok bool
+			if number, _, ok = splitNumbered(name); !ok || logFileName(dir) != want {
+				continue
+			}
+		} else if n, base, ok := splitNumbered(dir); !ok || n < 0 || logFileName(base) != want {
+			continue
+		}
+
+		content, err := archive.read(file)
+		if err != nil {
+			return "", nil, err
+		}
+		if nested {
+			steps[number] = content
+		} else {
+			jobLog = content
+		}
+	}
+	return jobLog, steps, nil
+}
+
+// splitNumbered separa "3_Run tests.txt" en 3 y "Run tests"
+func splitNumbered(name string) (int64, string, bool) {
+	prefix, rest, ok := strings.Cut(strings.TrimSuffix(name, ".txt"), "_")
+	if !ok {
+		return 0, "", false
+	}
+	var number int64
+	if _, err := fmt.Sscanf(prefix, "%d", &number); err != nil {
+		return 0, "", false
+	}
+	return number, rest, true
+}
+
+// logFileName normaliza un nombre de job como aparece en el zip de logs
+func logFileName(name string) string {
+	return strings.ToLower(strings.TrimSpace(strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return -1
+		}
+		return r
+	}, name)))
+}
+
+// sliceStepLog extrae de un log de job las líneas escritas durante un step, usando
+// la marca de tiempo con la que empieza cada línea
+func sliceStepLog(jobLog string, step *github.TaskStep) string {
+	if step.StartedAt == nil {
+		return ""
+	}
+	start := step.GetStartedAt().Time.Truncate(time.Second)
+	end := time.Now()
+	if step.CompletedAt != nil {
+		end = step.GetCompletedAt().Time.Truncate(time.Second).Add(time.Second)
+	}
+
+	var lines []string
+	include := false
+	for _, line := range strings.Split(jobLog, "\n") {
+		if stamp, _, ok := strings.Cut(line, " "); ok {
+			if t, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+				include = !t.Before(start) && t.Before(end)
+			}
+		}
+		if include {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// newExcerpt prepara el fragmento de log: quita las marcas de tiempo y aplica grep y tail
+func newExcerpt(job *github.WorkflowJob, step *github.TaskStep, text string, grep *regexp.Regexp, tail int) LogExcerpt {
+	excerpt := LogExcerpt{Job: job.GetName(), JobID: job.GetID(), Conclusion: job.GetConclusion()}
+	if step != nil {
+		excerpt.Step = step.GetName()
+		excerpt.StepNumber = step.GetNumber()
+		excerpt.Conclusion = step.GetConclusion()
+	}
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if stamp, rest, ok := strings.Cut(line, " "); ok {
+			if _, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+				line = rest
+			}
+		}
+		if grep == nil || grep.MatchString(line) {
+			lines = append(lines, line)
+		}
+	}
+
+	excerpt.TotalLines = len(lines)
+	if tail > 0 && len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+	excerpt.Lines = len(lines)
+	excerpt.Content = strings.Join(lines, "\n")
+	return excerpt
+}
+
+// isNotFound indica una respuesta 404 de la API
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*github.ErrorResponse)
+	return ok && statusCode(apiErr) == http.StatusNotFound
+}
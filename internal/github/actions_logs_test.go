@@ -0,0 +1,149 @@
+package github
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// newTestArchive crea un zip de logs con las entradas indicadas
+func newTestArchive(t *testing.T, files map[string]string) *logArchive {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := newLogArchive(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return archive
+}
+
+func TestFindJobLogs(t *testing.T) {
+	archive := newTestArchive(t, map[string]string{
+		"0_build.txt":                  "build log",
+		"build/1_Set up job.txt":       "setup",
+		"build/3_Run tests.txt":        "tests",
+		"1_test (ubuntu, 1.23).txt":    "matrix log",
+		"test (ubuntu, 1.23)/2_Go.txt": "go",
+		"2_lint  deploy.txt":           "lint log",
+		"lint  deploy/notes.txt":       "unnumbered",
+		"build-extra/1_Other.txt":      "other job",
+	})
+
+	tests := []struct {
+		name      string
+		job       string
+		wantLog   string
+		wantSteps map[int64]string
+	}{
+		{name: "job and steps", job: "build", wantLog: "build log", wantSteps: map[int64]string{1: "setup", 3: "tests"}},
+		{name: "case insensitive", job: "Build", wantLog: "build log", wantSteps: map[int64]string{1: "setup", 3: "tests"}},
+		{name: "matrix job", job: "test (ubuntu, 1.23)", wantLog: "matrix log", wantSteps: map[int64]string{2: "go"}},
+		{name: "invalid path characters", job: "lint / deploy", wantLog: "lint log", wantSteps: map[int64]string{}},
+		{name: "missing job", job: "release", wantLog: "", wantSteps: map[int64]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobLog, steps, err := findJobLogs(archive, tt.job)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if jobLog != tt.wantLog {
+				t.Errorf("job log = %q, want %q", jobLog, tt.wantLog)
+			}
+			if !reflect.DeepEqual(steps, tt.wantSteps) {
+				t.Errorf("steps = %v, want %v", steps, tt.wantSteps)
+			}
+		})
+	}
+
+	// Sin zip (ejecución en curso) no hay logs
+	if jobLog, steps, err := findJobLogs(nil, "build"); err != nil || jobLog != "" || len(steps) != 0 {
+		t.Errorf("nil archive = %q, %v, %v", jobLog, steps, err)
+	}
+}
+
+func TestFindJobLogsBudget(t *testing.T) {
+	archive := newTestArchive(t, map[string]string{
+		"0_build.txt":      strings.Repeat("a", 60),
+		"build/1_Step.txt": strings.Repeat("b", 30),
+		"1_huge.txt":       strings.Repeat("c", 1000),
+	})
+	archive.budget = 100
+
+	// Las entradas de otros jobs no se descomprimen ni cuentan
+	if _, _, err := findJobLogs(archive, "build"); err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if archive.budget != 10 {
+		t.Errorf("budget left = %d, want 10", archive.budget)
+	}
+
+	// El límite es común a todas las entradas: se supera con error, sin truncar
+	if _, _, err := findJobLogs(archive, "build"); err == nil || !strings.Contains(err.Error(), "expands to more than") {
+		t.Errorf("second read: err = %v, want budget error", err)
+	}
+	if _, _, err := findJobLogs(archive, "huge"); err == nil {
+		t.Error("huge: want budget error")
+	}
+}
+
+func TestSliceStepLog(t *testing.T) {
+	jobLog := strings.Join([]string{
+		"2024-05-01T10:00:00.1000000Z setup",
+		"2024-05-01T10:00:01.5000000Z checkout",
+		"continuation of checkout",
+		"2024-05-01T10:00:02.0000000Z test 1",
+		"2024-05-01T10:00:03.9000000Z test 2",
+		"2024-05-01T10:00:04.0000000Z cleanup",
+	}, "\n")
+	at := func(second int) *github.Timestamp {
+		return &github.Timestamp{Time: time.Date(2024, 5, 1, 10, 0, second, 0, time.UTC)}
+	}
+
+	tests := []struct {
+		name string
+		step *github.TaskStep
+		want []string
+	}{
+		{name: "single second", step: &github.TaskStep{StartedAt: at(0), CompletedAt: at(0)}, want: []string{"setup"}},
+		{name: "continuation lines", step: &github.TaskStep{StartedAt: at(1), CompletedAt: at(1)}, want: []string{"checkout", "continuation of checkout"}},
+		{name: "completion rounded up", step: &github.TaskStep{StartedAt: at(2), CompletedAt: at(3)}, want: []string{"test 1", "test 2"}},
+		{name: "still running", step: &github.TaskStep{StartedAt: at(4)}, want: []string{"cleanup"}},
+		{name: "not started", step: &github.TaskStep{}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, line := range strings.Split(sliceStepLog(jobLog, tt.step), "\n") {
+				if line == "" {
+					continue
+				}
+				if _, rest, ok := strings.Cut(line, "Z "); ok {
+					line = rest
+				}
+				got = append(got, line)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sliceStepLog = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
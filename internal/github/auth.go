@@ -0,0 +1,392 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// TokenSource proporciona el token de cada petición a la API. owner es el propietario
+// al que va dirigida la petición ("" si no se puede deducir de la URL), lo que permite
+// usar un token distinto por organización (instalaciones de una GitHub App).
+type TokenSource interface {
+	Token(ctx context.Context, owner string) (string, error)
+}
+
+// StaticToken es un token fijo (personal access token)
+type StaticToken string
+
+func (t StaticToken) Token(context.Context, string) (string, error) {
+	return string(t), nil
+}
+
+// tokenInvalidator lo implementan las fuentes que pueden descartar un token rechazado (401)
+type tokenInvalidator interface {
+	Invalidate(owner string)
+}
+
+// authTransport añade la cabecera Authorization con el token de la fuente configurada.
+// Con hosts solo se autentican las peticiones a esos hosts: las descargas redirigidas
+// (logs, artefactos) van a almacenamiento externo con URLs firmadas y no deben recibir el token.
+type authTransport struct {
+	base   http.RoundTripper
+	source TokenSource
+	hosts  map[string]bool
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.hosts != nil && !t.hosts[strings.ToLower(req.URL.Host)] {
+		return t.base.RoundTrip(req)
+	}
+
+	owner := ownerFromPath(req.URL.Path)
+	if owner == "" {
+		owner, _ = req.Context().Value(ownerKey{}).(string)
+	}
+
+	resp, err := t.send(req, owner)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// Un token revocado o caducado antes de tiempo se renueva y se reintenta una vez
+	invalidator, ok := t.source.(tokenInvalidator)
+	if !ok || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+	invalidator.Invalidate(owner)
+	resp.Body.Close()
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return t.send(retry, owner)
+}
+
+func (t *authTransport) send(req *http.Request, owner string) (*http.Response, error) {
+	token, err := t.source.Token(req.Context(), owner)
+	if err != nil {
+		return nil, fmt.Errorf("GitHub authentication failed: %v", err)
+	}
+
+	authorized := req.Clone(req.Context())
+	authorized.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(authorized)
+}
+
+type ownerKey struct{}
+
+// WithOwner indica el propietario de las peticiones cuya URL no lo incluye (p. ej. GraphQL)
+func WithOwner(ctx context.Context, owner string) context.Context {
+	return context.WithValue(ctx, ownerKey{}, strings.ToLower(owner))
+}
+
+// ownerFromPath deduce el propietario de rutas como /repos/{owner}/..., /orgs/{org}/... o /users/{user}/...
+func ownerFromPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		switch segments[i] {
+		case "repos", "orgs", "users":
+			return strings.ToLower(segments[i+1])
+		}
+	}
+	return ""
+}
+
+// AppOptions identifica una GitHub App
+type AppOptions struct {
+	AppID          int64
+	PrivateKey     []byte // PEM (PKCS#1 o PKCS#8)
+	InstallationID int64  // instalación por defecto; 0 = deducirla
+}
+
+const (
+	tokenRefreshMargin = 5 * time.Minute  // antelación con la que se renueva un token de instalación
+	notInstalledTTL    = 10 * time.Minute // tras este tiempo se vuelve a buscar la App en un owner sin instalación
+	appCallTimeout     = 30 * time.Second // límite de una llamada compartida a la API de la App
+)
+
+// AppTokenSource autentica como GitHub App: firma JWTs con la clave privada y los
+// cambia por tokens de instalación, uno por owner, que se renuevan antes de caducar.
+// El mutex solo protege los mapas: las llamadas a la API se hacen fuera de él y las
+// peticiones simultáneas de una misma instalación comparten una única llamada, que no
+// depende de la petición que la inició: si esta se cancela, las demás siguen esperándola.
+type AppTokenSource struct {
+	appID int64
+	key   *rsa.PrivateKey
+	apps  *github.Client // cliente autenticado con el JWT de la App
+	now   func() time.Time
+
+	mu             sync.Mutex
+	installationID int64                               // instalación por defecto (configurada o deducida)
+	installations  map[string]int64                    // owner (minúsculas) -> instalación
+	notInstalled   map[string]time.Time                // owners donde la App no estaba instalada -> cuándo se comprobó
+	tokens         map[int64]*github.InstallationToken // instalación -> token vigente
+	inflight       map[string]*appCall                 // llamadas a la API en curso
+}
+
+// appCall es una llamada a la API en curso que esperan las peticiones simultáneas
+type appCall struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+// NewAppTokenSource crea la fuente de tokens de una GitHub App. Las peticiones de
+// tokens usan la misma URL base, CA y proxy que el cliente de la API.
+func NewAppTokenSource(app AppOptions, opts ClientOptions) (*AppTokenSource, error) {
+	if app.AppID <= 0 {
+		return nil, fmt.Errorf("GitHub App ID required")
+	}
+	key, err := ParsePrivateKey(app.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	source := &AppTokenSource{
+		appID:          app.AppID,
+		key:            key,
+		installationID: app.InstallationID,
+		now:            time.Now,
+		installations:  map[string]int64{},
+		notInstalled:   map[string]time.Time{},
+		tokens:         map[int64]*github.InstallationToken{},
+		inflight:       map[string]*appCall{},
+	}
+
+	transport, err := newTransport(opts)
+	if err != nil {
+		return nil, err
+	}
+	jwt := tokenSourceFunc(func(context.Context, string) (string, error) { return source.JWT() })
+	auth := &authTransport{base: transport, source: jwt}
+	source.apps, err = newAPIClient(auth, opts)
+	if err != nil {
+		return nil, err
+	}
+	auth.hosts = apiHosts(source.apps)
+	return source, nil
+}
+
+// Token devuelve el token de instalación para owner, pidiendo uno nuevo si no hay o está por caducar
+func (a *AppTokenSource) Token(ctx context.Context, owner string) (string, error) {
+	id, err := a.installationFor(ctx, owner)
+	if err != nil {
+		return "", err
+	}
+
+	a.mu.Lock()
+	token, ok := a.tokens[id]
+	a.mu.Unlock()
+	if ok && token.GetExpiresAt().Time.After(a.now().Add(tokenRefreshMargin)) {
+		return token.GetToken(), nil
+	}
+
+	return a.single(ctx, fmt.Sprintf("token/%d", id), func(ctx context.Context) (string, error) {
+		token, _, err := a.apps.Apps.CreateInstallationToken(ctx, id, nil)
+		if err != nil {
+			return "", fmt.Errorf("creating installation token for installation %d: %v", id, err)
+		}
+		a.mu.Lock()
+		a.tokens[id] = token
+		a.mu.Unlock()
+		return token.GetToken(), nil
+	})
+}
+
+// single ejecuta fn sin el mutex tomado; las llamadas con la misma clave que llegan
+// mientras está en curso esperan su resultado en vez de repetirla. fn recibe un contexto
+// con los valores de ctx pero sin su cancelación, limitado por appCallTimeout, y cada
+// llamador deja de esperar cuando se cancela el suyo.
+func (a *AppTokenSource) single(ctx context.Context, key string, fn func(ctx context.Context) (string, error)) (string, error) {
+	a.mu.Lock()
+	call, ok := a.inflight[key]
+	if !ok {
+		call = &appCall{done: make(chan struct{})}
+		a.inflight[key] = call
+		go a.run(ctx, key, call, fn)
+	}
+	a.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// run hace la llamada compartida y publica su resultado
+func (a *AppTokenSource) run(ctx context.Context, key string, call *appCall, fn func(ctx context.Context) (string, error)) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), appCallTimeout)
+	defer cancel()
+	call.token, call.err = fn(ctx)
+
+	a.mu.Lock()
+	delete(a.inflight, key)
+	a.mu.Unlock()
+	close(call.done)
+}
+
+// Invalidate descarta el token de instalación de owner para que se pida uno nuevo
+func (a *AppTokenSource) Invalidate(owner string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	id, ok := a.installations[owner]
+	if !ok {
+		id = a.installationID
+	}
+	delete(a.tokens, id)
+}
+
+// installationFor busca la instalación de la App para owner. Sin owner (o si la App
+// no está instalada en él) se usa la instalación por defecto.
+func (a *AppTokenSource) installationFor(ctx context.Context, owner string) (int64, error) {
+	if owner != "" {
+		a.mu.Lock()
+		id, ok := a.installations[owner]
+		checked, skip := a.notInstalled[owner]
+		if skip && a.now().Sub(checked) >= notInstalledTTL {
+			// La App puede haberse instalado desde la última comprobación
+			delete(a.notInstalled, owner)
+			skip = false
+		}
+		a.mu.Unlock()
+
+		if !ok && !skip {
+			if err := a.loadInstallations(ctx); err != nil {
+				return 0, err
+			}
+			a.mu.Lock()
+			if id, ok = a.installations[owner]; !ok {
+				// Repos de terceros: no volver a listar instalaciones en cada petición
+				a.notInstalled[owner] = a.now()
+			}
+			a.mu.Unlock()
+		}
+		if ok {
+			return id, nil
+		}
+	}
+
+	a.mu.Lock()
+	id, loaded := a.installationID, len(a.installations) > 0
+	a.mu.Unlock()
+	if id != 0 {
+		return id, nil
+	}
+
+	if !loaded {
+		if err := a.loadInstallations(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	switch len(a.installations) {
+	case 0:
+		return 0, fmt.Errorf("GitHub App %d has no installations", a.appID)
+	case 1:
+		for _, id := range a.installations {
+			a.installationID = id
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("GitHub App %d is installed in several accounts; set installation_id in the profile", a.appID)
+}
+
+// loadInstallations carga las instalaciones de la App indexadas por cuenta
+func (a *AppTokenSource) loadInstallations(ctx context.Context) error {
+	_, err := a.single(ctx, "installations", func(ctx context.Context) (string, error) {
+		found := map[string]int64{}
+		opts := &github.ListOptions{PerPage: 100}
+		for {
+			installations, resp, err := a.apps.Apps.ListInstallations(ctx, opts)
+			if err != nil {
+				return "", fmt.Errorf("listing GitHub App installations: %v", err)
+			}
+			for _, installation := range installations {
+				found[strings.ToLower(installation.GetAccount().GetLogin())] = installation.GetID()
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opts.Page = resp.NextPage
+		}
+
+		a.mu.Lock()
+		for owner, id := range found {
+			a.installations[owner] = id
+		}
+		a.mu.Unlock()
+		return "", nil
+	})
+	return err
+}
+
+// JWT genera el token firmado (RS256) con el que la App se autentica ante la API.
+// Se emite con un minuto de margen por desfase de reloj y caduca a los 9 minutos.
+func (a *AppTokenSource) JWT() (string, error) {
+	now := a.now()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]int64{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": a.appID,
+	})
+
+	encoding := base64.RawURLEncoding
+	unsigned := encoding.EncodeToString(header) + "." + encoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("signing GitHub App JWT: %v", err)
+	}
+	return unsigned + "." + encoding.EncodeToString(signature), nil
+}
+
+// ParsePrivateKey lee la clave privada PEM de una GitHub App
+func ParsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("GitHub App private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub App private key: %v", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("GitHub App private key must be RSA")
+	}
+	return key, nil
+}
+
+// tokenSourceFunc adapta una función a TokenSource
+type tokenSourceFunc func(ctx context.Context, owner string) (string, error)
+
+func (f tokenSourceFunc) Token(ctx context.Context, owner string) (string, error) {
+	return f(ctx, owner)
+}
@@ -0,0 +1,385 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeApps simula los endpoints de GitHub App: lista de instalaciones y creación de tokens
+type fakeApps struct {
+	t   *testing.T
+	key *rsa.PublicKey
+
+	mu      sync.Mutex
+	expires time.Time     // caducidad de los tokens creados
+	block   chan struct{} // si no es nil, la creación de tokens de la instalación 1 espera a que se cierre
+	lists   int
+	created map[string]int // instalación -> tokens creados
+	claims  map[string]int64
+}
+
+func (f *fakeApps) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.checkJWT(r)
+	path := strings.TrimPrefix(r.URL.Path, "/api/v3")
+	switch {
+	case r.Method == http.MethodGet && path == "/app/installations":
+		f.mu.Lock()
+		f.lists++
+		f.mu.Unlock()
+		w.Write([]byte(`[{"id":1,"account":{"login":"Acme"}},{"id":2,"account":{"login":"other"}}]`))
+	case r.Method == http.MethodPost && strings.HasPrefix(path, "/app/installations/") && strings.HasSuffix(path, "/access_tokens"):
+		id := strings.TrimSuffix(strings.TrimPrefix(path, "/app/installations/"), "/access_tokens")
+		f.mu.Lock()
+		block := f.block
+		f.mu.Unlock()
+		if block != nil && id == "1" {
+			<-block
+		}
+		f.mu.Lock()
+		f.created[id]++
+		n, expires := f.created[id], f.expires
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token":"tok-%s-%d","expires_at":%q}`, id, n, expires.Format(time.RFC3339))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// checkJWT verifica la firma del JWT de la App y guarda sus claims
+func (f *fakeApps) checkJWT(r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), ".")
+	if len(parts) != 3 {
+		f.t.Errorf("%s %s: Authorization is not a JWT", r.Method, r.URL.Path)
+		return
+	}
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(f.key, crypto.SHA256, digest[:], signature); err != nil {
+		f.t.Errorf("JWT signature: %v", err)
+	}
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	var claims map[string]int64
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		f.t.Errorf("JWT claims: %v", err)
+	}
+	f.mu.Lock()
+	f.claims = claims
+	f.mu.Unlock()
+}
+
+func (f *fakeApps) set(fn func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fn()
+}
+
+func (f *fakeApps) count(id string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.created[id]
+}
+
+func newTestAppSource(t *testing.T, installationID int64) (*AppTokenSource, *fakeApps) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeApps{t: t, key: &key.PublicKey, expires: time.Now().Add(time.Hour), created: map[string]int{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	source, err := NewAppTokenSource(AppOptions{AppID: 42, PrivateKey: keyPEM, InstallationID: installationID}, ClientOptions{BaseURL: server.URL + "/api/v3/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return source, fake
+}
+
+func TestAppJWTClaims(t *testing.T) {
+	source, fake := newTestAppSource(t, 0)
+	now := time.Unix(1700000000, 0)
+	source.now = func() time.Time { return now }
+
+	if _, err := source.Token(context.Background(), "acme"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int64{"iss": 42, "iat": now.Unix() - 60, "exp": now.Unix() + 540}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	for claim, value := range want {
+		if got := fake.claims[claim]; got != value {
+			t.Errorf("claim %s = %d, want %d", claim, got, value)
+		}
+	}
+}
+
+func TestAppTokenPerOwner(t *testing.T) {
+	source, fake := newTestAppSource(t, 0)
+	ctx := context.Background()
+
+	tests := []struct {
+		owner string
+		want  string
+	}{
+		{owner: "acme", want: "tok-1-1"},
+		{owner: "acme", want: "tok-1-1"},
+		{owner: "other", want: "tok-2-1"},
+		{owner: "other", want: "tok-2-1"},
+	}
+	for _, tt := range tests {
+		got, err := source.Token(ctx, tt.owner)
+		if err != nil {
+			t.Fatalf("Token(%s): %v", tt.owner, err)
+		}
+		if got != tt.want {
+			t.Errorf("Token(%s) = %s, want %s", tt.owner, got, tt.want)
+		}
+	}
+	if fake.lists != 1 {
+		t.Errorf("installations listed %d times, want 1", fake.lists)
+	}
+
+	// Sin instalación propia ni por defecto, con varias instalaciones no se puede elegir
+	if _, err := source.Token(ctx, "stranger"); err == nil || !strings.Contains(err.Error(), "several accounts") {
+		t.Errorf("Token(stranger) error = %v", err)
+	}
+	if _, err := source.Token(ctx, "stranger"); err == nil {
+		t.Error("Token(stranger) succeeded")
+	}
+	// Un owner desconocido se busca una sola vez
+	if fake.lists != 2 {
+		t.Errorf("installations listed %d times, want 2", fake.lists)
+	}
+}
+
+func TestAppTokenDefaultInstallation(t *testing.T) {
+	source, fake := newTestAppSource(t, 2)
+
+	for _, owner := range []string{"", "stranger"} {
+		got, err := source.Token(context.Background(), owner)
+		if err != nil {
+			t.Fatalf("Token(%q): %v", owner, err)
+		}
+		if got != "tok-2-1" {
+			t.Errorf("Token(%q) = %s, want tok-2-1", owner, got)
+		}
+	}
+	if n := fake.count("2"); n != 1 {
+		t.Errorf("tokens created for the default installation = %d, want 1", n)
+	}
+}
+
+func TestAppTokenRefresh(t *testing.T) {
+	source, fake := newTestAppSource(t, 0)
+	ctx := context.Background()
+	expires := fake.expires
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{name: "fresh", now: expires.Add(-time.Hour), want: "tok-1-1"},
+		{name: "before margin", now: expires.Add(-tokenRefreshMargin - time.Minute), want: "tok-1-1"},
+		{name: "within margin", now: expires.Add(-tokenRefreshMargin + time.Minute), want: "tok-1-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source.now = func() time.Time { return tt.now }
+			// El token renovado caduca una hora después del reloj simulado
+			fake.set(func() { fake.expires = tt.now.Add(time.Hour) })
+			got, err := source.Token(ctx, "acme")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Token = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppTokenInvalidate(t *testing.T) {
+	source, _ := newTestAppSource(t, 0)
+	ctx := context.Background()
+
+	for _, want := range []string{"tok-1-1", "tok-1-1"} {
+		if got, _ := source.Token(ctx, "acme"); got != want {
+			t.Errorf("Token = %s, want %s", got, want)
+		}
+	}
+	source.Invalidate("acme")
+	if got, _ := source.Token(ctx, "acme"); got != "tok-1-2" {
+		t.Errorf("Token after Invalidate = %s, want tok-1-2", got)
+	}
+	// Invalidar un owner no afecta a los demás
+	if got, _ := source.Token(ctx, "other"); got != "tok-2-1" {
+		t.Errorf("Token(other) = %s, want tok-2-1", got)
+	}
+}
+
+func TestAppTokenConcurrent(t *testing.T) {
+	source, fake := newTestAppSource(t, 0)
+	ctx := context.Background()
+	if _, err := source.Token(ctx, "other"); err != nil {
+		t.Fatal(err)
+	}
+
+	block := make(chan struct{})
+	fake.set(func() { fake.block = block })
+	var wg sync.WaitGroup
+	tokens := make([]string, 5)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], _ = source.Token(ctx, "acme")
+		}(i)
+	}
+
+	// Mientras se pide el token de acme, otro owner no espera
+	done := make(chan struct{})
+	go func() {
+		source.Token(ctx, "other")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Token(other) blocked by the acme token request")
+	}
+
+	close(block)
+	wg.Wait()
+	for i, token := range tokens {
+		if token != "tok-1-1" {
+			t.Errorf("token %d = %q, want tok-1-1", i, token)
+		}
+	}
+	if n := fake.count("1"); n != 1 {
+		t.Errorf("tokens created for acme = %d, want 1", n)
+	}
+}
+
+func TestAppJWTOnlySentToAPIHosts(t *testing.T) {
+	var seen headerRecorder
+	external := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen.record(r)
+	}))
+	defer external.Close()
+
+	source, _ := newTestAppSource(t, 0)
+	req, err := source.apps.NewRequest(http.MethodGet, external.URL+"/signed", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := source.apps.BareDo(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if got, ok := seen.get("/signed"); !ok || got != "" {
+		t.Errorf("external host: Authorization = %q (requested: %v), want none", got, ok)
+	}
+}
+
+func TestAppTokenFirstCallerCancelled(t *testing.T) {
+	source, fake := newTestAppSource(t, 0)
+	if _, err := source.Token(context.Background(), "other"); err != nil {
+		t.Fatal(err)
+	}
+
+	block := make(chan struct{})
+	fake.set(func() { fake.block = block })
+
+	// La petición que inicia la llamada se cancela mientras la API responde
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := source.Token(ctx, "acme")
+		first <- err
+	}()
+	waiter := make(chan string)
+	go func() {
+		token, err := source.Token(context.Background(), "acme")
+		if err != nil {
+			t.Error(err)
+		}
+		waiter <- token
+	}()
+
+	cancel()
+	select {
+	case err := <-first:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("cancelled caller: err = %v, want canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("cancelled caller kept waiting")
+	}
+
+	// La llamada compartida sigue y el otro llamador recibe el token
+	close(block)
+	select {
+	case token := <-waiter:
+		if token != "tok-1-1" {
+			t.Errorf("waiter token = %q, want tok-1-1", token)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("waiter did not get the token")
+	}
+	if n := fake.count("1"); n != 1 {
+		t.Errorf("tokens created for acme = %d, want 1", n)
+	}
+}
+
+func TestAppTokenNotInstalledTTL(t *testing.T) {
+	source, fake := newTestAppSource(t, 2)
+	now := time.Now()
+	source.now = func() time.Time { return now }
+	lists := func() int {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		return fake.lists
+	}
+
+	tests := []struct {
+		after     time.Duration
+		wantLists int
+	}{
+		{after: 0, wantLists: 1},
+		{after: notInstalledTTL - time.Second, wantLists: 1},
+		{after: notInstalledTTL, wantLists: 2},
+		{after: notInstalledTTL + time.Second, wantLists: 2},
+	}
+	start := now
+	for _, tt := range tests {
+		now = start.Add(tt.after)
+		if _, err := source.Token(context.Background(), "stranger"); err != nil {
+			t.Fatal(err)
+		}
+		if got := lists(); got != tt.wantLists {
+			t.Errorf("after %v: installations listed %d times, want %d", tt.after, got, tt.wantLists)
+		}
+	}
+}
@@ -0,0 +1,448 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// commitSHAPattern reconoce un SHA completo de commit
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// BranchProtectionChanges son los cambios de la protección de una rama. Los punteros
+// nil conservan el valor actual; la protección clásica se reescribe entera (PUT), así
+// que se parte de la vigente. RulesetID y RulesetEnforcement cambian el modo de un ruleset.
+type BranchProtectionChanges struct {
+	RequireReviews          *bool // false quita la revisión obligatoria
+	RequiredApprovals       *int
+	DismissStaleReviews     *bool
+	RequireCodeOwnerReviews *bool
+	RequireLastPushApproval *bool
+
+	RequireStatusChecks *bool // false quita los checks obligatorios
+	StatusChecks        *[]string
+	StrictStatusChecks  *bool // la rama debe estar al día con la base
+
+	EnforceAdmins                 *bool
+	RequireLinearHistory          *bool
+	AllowForcePushes              *bool
+	AllowDeletions                *bool
+	RequireConversationResolution *bool
+	LockBranch                    *bool
+
+	RulesetID          int64
+	RulesetEnforcement string // active, evaluate o disabled
+}
+
+// ListBranches lista las ramas de un repositorio
+func ListBranches(client *github.Client, ctx context.Context, owner, repoName string, protectedOnly bool, opts PageOptions) (*BranchList, error) {
+	branches, next, err := paginate(opts, func(page github.ListOptions) ([]BranchSummary, *github.Response, error) {
+		opt := &github.BranchListOptions{ListOptions: page}
+		if protectedOnly {
+			opt.Protected = github.Bool(true)
+		}
+		branches, resp, err := client.Repositories.ListBranches(ctx, owner, repoName, opt)
+		if err != nil {
+			return nil, nil, err
+		}
+		summaries := make([]BranchSummary, 0, len(branches))
+		for _, branch := range branches {
+			summaries = append(summaries, BranchSummary{
+				Name:      branch.GetName(),
+				SHA:       branch.GetCommit().GetSHA(),
+				Protected: branch.GetProtected(),
+			})
+		}
+		return summaries, resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &BranchList{Branches: branches, NextCursor: next}, nil
+}
+
+// CreateBranch crea una rama desde un SHA o desde otra rama (default: la rama por defecto)
+func CreateBranch(client *github.Client, ctx context.Context, owner, repoName, name, from string) (*BranchRef, error) {
+	if from == "" {
+		repo, _, err := client.Repositories.Get(ctx, owner, repoName)
+		if err != nil {
+			return nil, err
+		}
+		from = repo.GetDefaultBranch()
+	}
+
+	sha := from
+	if !commitSHAPattern.MatchString(from) {
+		ref, _, err := client.Git.GetRef(ctx, owner, repoName, "heads/"+from)
+		if err != nil {
+			if isNotFound(err) {
+				return nil, fmt.Errorf("branch %s not found in %s/%s", from, owner, repoName)
+			}
+			return nil, err
+		}
+		sha = ref.GetObject().GetSHA()
+	}
+
+	_, _, err := client.Git.CreateRef(ctx, owner, repoName, &github.Reference{
+		Ref:    github.String("refs/heads/" + name),
+		Object: &github.GitObject{SHA: github.String(sha)},
+	})
+	if err != nil {
+		var apiErr *github.ErrorResponse
+		if errors.As(err, &apiErr) && statusCode(apiErr) == http.StatusUnprocessableEntity {
+			return nil, fmt.Errorf("cannot create branch %s: %s", name, apiErr.Message)
+		}
+		return nil, err
+	}
+	return &BranchRef{Name: name, SHA: sha, From: from, Action: "created"}, nil
+}
+
+// DeleteBranch borra una rama. La rama por defecto no se puede borrar.
+func DeleteBranch(client *github.Client, ctx context.Context, owner, repoName, name string) (*BranchRef, error) {
+	repo, _, err := client.Repositories.Get(ctx, owner, repoName)
+	if err != nil {
+		return nil, err
+	}
+	if repo.GetDefaultBranch() == name {
+		return nil, fmt.Errorf("%s is the default branch of %s/%s and cannot be deleted", name, owner, repoName)
+	}
+
+	ref, _, err := client.Git.GetRef(ctx, owner, repoName, "heads/"+name)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("branch %s not found in %s/%s", name, owner, repoName)
+		}
+		return nil, err
+	}
+	if _, err := client.Git.DeleteRef(ctx, owner, repoName, "heads/"+name); err != nil {
+		return nil, err
+	}
+	return &BranchRef{Name: name, SHA: ref.GetObject().GetSHA(), Action: "deleted"}, nil
+}
+
+// Compare compara dos refs: commits por delante y por detrás, y archivos cambiados desde
+// la base de fusión. GitHub devuelve como mucho 250 commits y 300 archivos.
+func Compare(client *github.Client, ctx context.Context, owner, repoName, base, head string, patches bool) (*Comparison, error) {
+	comparison, _, err := client.Repositories.CompareCommits(ctx, owner, repoName, base, head, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Comparison{
+		Base:         base,
+		Head:         head,
+		Status:       comparison.GetStatus(),
+		AheadBy:      comparison.GetAheadBy(),
+		BehindBy:     comparison.GetBehindBy(),
+		TotalCommits: comparison.GetTotalCommits(),
+		MergeBaseSHA: comparison.GetMergeBaseCommit().GetSHA(),
+		URL:          comparison.GetHTMLURL(),
+		Commits:      []CompareCommit{},
+		Files:        []PullRequestFile{},
+	}
+	for _, commit := range comparison.Commits {
+		message, _, _ := strings.Cut(commit.GetCommit().GetMessage(), "\n")
+		result.Commits = append(result.Commits, CompareCommit{
+			SHA:     commit.GetSHA(),
+			Message: message,
+			Author:  commit.GetCommit().GetAuthor().GetName(),
+			Date:    commit.GetCommit().GetAuthor().GetDate().Format(time.RFC3339),
+		})
+	}
+	for _, file := range comparison.Files {
+		changed := PullRequestFile{
+			Path:         file.GetFilename(),
+			PreviousPath: file.GetPreviousFilename(),
+			Status:       file.GetStatus(),
+			Additions:    file.GetAdditions(),
+			Deletions:    file.GetDeletions(),
+		}
+		if patches {
+			changed.Patch = file.GetPatch()
+		}
+		result.Files = append(result.Files, changed)
+	}
+	return result, nil
+}
+
+// GetBranchProtection obtiene la protección clásica de una rama y las reglas de los
+// rulesets que se le aplican
+func GetBranchProtection(client *github.Client, ctx context.Context, owner, repoName, branch string) (*BranchProtection, error) {
+	result := &BranchProtection{Branch: branch, Rules: []BranchRule{}}
+
+	protection, _, err := client.Repositories.GetBranchProtection(ctx, owner, repoName, branch)
+	switch {
+	case err == nil:
+		result.Protected = true
+		result.fill(protection)
+	case errors.Is(err, github.ErrBranchNotProtected) || isNotFound(err):
+		// sin protección clásica (o sin permiso de administración para verla)
+	default:
+		return nil, err
+	}
+
+	rules, _, err := client.Repositories.GetRulesForBranch(ctx, owner, repoName, branch)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	for _, rule := range rules {
+		branchRule := BranchRule{Type: rule.Type, RulesetID: rule.RulesetID, RulesetSource: rule.RulesetSource}
+		if rule.Parameters != nil {
+			branchRule.Parameters = *rule.Parameters
+		}
+		result.Rules = append(result.Rules, branchRule)
+	}
+	return result, nil
+}
+
+// UpdateBranchProtection aplica los cambios a la protección de una rama y devuelve la
+// protección resultante
+func UpdateBranchProtection(client *github.Client, ctx context.Context, owner, repoName, branch string, changes BranchProtectionChanges) (*BranchProtection, error) {
+	if changes.RulesetID == 0 && !changes.protectionChanged() {
+		return nil, fmt.Errorf("no branch protection changes")
+	}
+	if changes.RulesetID != 0 {
+		if err := setRulesetEnforcement(client, ctx, owner, repoName, changes.RulesetID, changes.RulesetEnforcement); err != nil {
+			return nil, err
+		}
+	}
+
+	if changes.protectionChanged() {
+		current, _, err := client.Repositories.GetBranchProtection(ctx, owner, repoName, branch)
+		if err != nil && !errors.Is(err, github.ErrBranchNotProtected) && !isNotFound(err) {
+			return nil, err
+		}
+		req := protectionRequest(current)
+		changes.apply(req)
+		if _, _, err := client.Repositories.UpdateBranchProtection(ctx, owner, repoName, branch, req); err != nil {
+			return nil, err
+		}
+	}
+
+	return GetBranchProtection(client, ctx, owner, repoName, branch)
+}
+
+// setRulesetEnforcement cambia el modo de un ruleset conservando el resto de su definición
+func setRulesetEnforcement(client *github.Client, ctx context.Context, owner, repoName string, id int64, enforcement string) error {
+	switch enforcement {
+	case "active", "evaluate", "disabled":
+	default:
+		return fmt.Errorf("invalid ruleset enforcement %q (active, evaluate or disabled)", enforcement)
+	}
+
+	ruleset, _, err := client.Repositories.GetRuleset(ctx, owner, repoName, id, false)
+	if err != nil {
+		return err
+	}
+	_, _, err = client.Repositories.UpdateRuleset(ctx, owner, repoName, id, &github.Ruleset{
+		Name:         ruleset.Name,
+		Target:       ruleset.Target,
+		Enforcement:  enforcement,
+		BypassActors: ruleset.BypassActors,
+		Conditions:   ruleset.Conditions,
+		Rules:        ruleset.Rules,
+	})
+	return err
+}
+
+func (c BranchProtectionChanges) protectionChanged() bool {
+	for _, flag := range []*bool{c.RequireReviews, c.DismissStaleReviews, c.RequireCodeOwnerReviews, c.RequireLastPushApproval,
+		c.RequireStatusChecks, c.StrictStatusChecks, c.EnforceAdmins, c.RequireLinearHistory, c.AllowForcePushes,
+		c.AllowDeletions, c.RequireConversationResolution, c.LockBranch} {
+		if flag != nil {
+			return true
+		}
+	}
+	return c.RequiredApprovals != nil || c.StatusChecks != nil
+}
+
+// apply aplica los cambios sobre la petición construida a partir de la protección vigente
+func (c BranchProtectionChanges) apply(req *github.ProtectionRequest) {
+	reviews := c.RequiredApprovals != nil || c.DismissStaleReviews != nil || c.RequireCodeOwnerReviews != nil || c.RequireLastPushApproval != nil
+	switch {
+	case c.RequireReviews != nil && !*c.RequireReviews:
+		req.RequiredPullRequestReviews = nil
+	case (c.RequireReviews != nil || reviews) && req.RequiredPullRequestReviews == nil:
+		req.RequiredPullRequestReviews = &github.PullRequestReviewsEnforcementRequest{RequiredApprovingReviewCount: 1}
+	}
+	if r := req.RequiredPullRequestReviews; r != nil {
+		if c.RequiredApprovals != nil {
+			r.RequiredApprovingReviewCount = *c.RequiredApprovals
+		}
+		if c.DismissStaleReviews != nil {
+			r.DismissStaleReviews = *c.DismissStaleReviews
+		}
+		if c.RequireCodeOwnerReviews != nil {
+			r.RequireCodeOwnerReviews = *c.RequireCodeOwnerReviews
+		}
+		if c.RequireLastPushApproval != nil {
+			r.RequireLastPushApproval = c.RequireLastPushApproval
+		}
+	}
+
+	checks := c.StatusChecks != nil || c.StrictStatusChecks != nil
+	switch {
+	case c.RequireStatusChecks != nil && !*c.RequireStatusChecks:
+		req.RequiredStatusChecks = nil
+	case (c.RequireStatusChecks != nil || checks) && req.RequiredStatusChecks == nil:
+		req.RequiredStatusChecks = &github.RequiredStatusChecks{Checks: &[]*github.RequiredStatusCheck{}}
+	}
+	if s := req.RequiredStatusChecks; s != nil {
+		if c.StatusChecks != nil {
+			// Los checks que ya eran obligatorios conservan la app que los debe reportar
+			existing := map[string]*github.RequiredStatusCheck{}
+			if s.Checks != nil {
+				for _, check := range *s.Checks {
+					existing[check.Context] = check
+				}
+			}
+			required := []*github.RequiredStatusCheck{}
+			for _, name := range *c.StatusChecks {
+				check, ok := existing[name]
+				if !ok {
+					check = &github.RequiredStatusCheck{Context: name}
+				}
+				required = append(required, check)
+			}
+			s.Checks = &required
+		}
+		if c.StrictStatusChecks != nil {
+			s.Strict = *c.StrictStatusChecks
+		}
+	}
+
+	if c.EnforceAdmins != nil {
+		req.EnforceAdmins = *c.EnforceAdmins
+	}
+	if c.RequireLinearHistory != nil {
+		req.RequireLinearHistory = c.RequireLinearHistory
+	}
+	if c.AllowForcePushes != nil {
+		req.AllowForcePushes = c.AllowForcePushes
+	}
+	if c.AllowDeletions != nil {
+		req.AllowDeletions = c.AllowDeletions
+	}
+	if c.RequireConversationResolution != nil {
+		req.RequiredConversationResolution = c.RequireConversationResolution
+	}
+	if c.LockBranch != nil {
+		req.LockBranch = c.LockBranch
+	}
+}
+
+// protectionRequest convierte la protección vigente (nil si no hay) en la petición que
+// la reescribe sin cambios
+func protectionRequest(p *github.Protection) *github.ProtectionRequest {
+	req := &github.ProtectionRequest{}
+	if p == nil {
+		return req
+	}
+
+	if checks := p.RequiredStatusChecks; checks != nil {
+		req.RequiredStatusChecks = &github.RequiredStatusChecks{Strict: checks.Strict, Checks: checks.Checks}
+		if checks.Checks == nil {
+			required := []*github.RequiredStatusCheck{}
+			for _, name := range checks.GetContexts() {
+				required = append(required, &github.RequiredStatusCheck{Context: name})
+			}
+			req.RequiredStatusChecks.Checks = &required
+		}
+	}
+	if reviews := p.RequiredPullRequestReviews; reviews != nil {
+		req.RequiredPullRequestReviews = &github.PullRequestReviewsEnforcementRequest{
+			DismissStaleReviews:          reviews.DismissStaleReviews,
+			RequireCodeOwnerReviews:      reviews.RequireCodeOwnerReviews,
+			RequiredApprovingReviewCount: reviews.RequiredApprovingReviewCount,
+			RequireLastPushApproval:      github.Bool(reviews.RequireLastPushApproval),
+		}
+		if d := reviews.DismissalRestrictions; d != nil {
+			users, teams, apps := actorNames(d.Users, d.Teams, d.Apps)
+			req.RequiredPullRequestReviews.DismissalRestrictionsRequest = &github.DismissalRestrictionsRequest{Users: &users, Teams: &teams, Apps: &apps}
+		}
+		if b := reviews.BypassPullRequestAllowances; b != nil {
+			users, teams, apps := actorNames(b.Users, b.Teams, b.Apps)
+			req.RequiredPullRequestReviews.BypassPullRequestAllowancesRequest = &github.BypassPullRequestAllowancesRequest{Users: users, Teams: teams, Apps: apps}
+		}
+	}
+	if r := p.Restrictions; r != nil {
+		users, teams, apps := actorNames(r.Users, r.Teams, r.Apps)
+		req.Restrictions = &github.BranchRestrictionsRequest{Users: users, Teams: teams, Apps: apps}
+	}
+
+	var flags BranchProtection
+	flags.fill(p)
+	req.EnforceAdmins = flags.EnforceAdmins
+	req.RequireLinearHistory = github.Bool(flags.RequireLinearHistory)
+	req.AllowForcePushes = github.Bool(flags.AllowForcePushes)
+	req.AllowDeletions = github.Bool(flags.AllowDeletions)
+	req.RequiredConversationResolution = github.Bool(flags.RequireConversationResolution)
+	req.BlockCreations = github.Bool(p.GetBlockCreations().GetEnabled())
+	req.LockBranch = github.Bool(p.GetLockBranch().GetEnabled())
+	req.AllowForkSyncing = github.Bool(p.GetAllowForkSyncing().GetEnabled())
+	return req
+}
+
+// actorNames devuelve los logins y slugs de usuarios, equipos y apps
+func actorNames(users []*github.User, teams []*github.Team, apps []*github.App) ([]string, []string, []string) {
+	userNames, teamNames, appNames := []string{}, []string{}, []string{}
+	for _, user := range users {
+		userNames = append(userNames, user.GetLogin())
+	}
+	for _, team := range teams {
+		teamNames = append(teamNames, team.GetSlug())
+	}
+	for _, app := range apps {
+		appNames = append(appNames, app.GetSlug())
+	}
+	return userNames, teamNames, appNames
+}
+
+// fill copia la protección clásica de la API en el resultado
+func (r *BranchProtection) fill(p *github.Protection) {
+	if reviews := p.RequiredPullRequestReviews; reviews != nil {
+		r.RequiredReviews = &RequiredReviews{
+			Approvals:        reviews.RequiredApprovingReviewCount,
+			DismissStale:     reviews.DismissStaleReviews,
+			CodeOwners:       reviews.RequireCodeOwnerReviews,
+			LastPushApproval: reviews.RequireLastPushApproval,
+		}
+	}
+	if checks := p.RequiredStatusChecks; checks != nil {
+		r.RequiredStatusChecks = &RequiredStatusChecks{Strict: checks.Strict, Checks: []string{}}
+		if checks.Checks != nil {
+			for _, check := range *checks.Checks {
+				r.RequiredStatusChecks.Checks = append(r.RequiredStatusChecks.Checks, check.Context)
+			}
+		} else {
+			r.RequiredStatusChecks.Checks = append(r.RequiredStatusChecks.Checks, checks.GetContexts()...)
+		}
+	}
+	if restrictions := p.Restrictions; restrictions != nil {
+		users, teams, apps := actorNames(restrictions.Users, restrictions.Teams, restrictions.Apps)
+		r.PushRestrictions = &Actors{Users: users, Teams: teams, Apps: apps}
+	}
+	// Los ajustes ausentes en la respuesta están desactivados
+	if v := p.EnforceAdmins; v != nil {
+		r.EnforceAdmins = v.Enabled
+	}
+	if v := p.RequireLinearHistory; v != nil {
+		r.RequireLinearHistory = v.Enabled
+	}
+	if v := p.AllowForcePushes; v != nil {
+		r.AllowForcePushes = v.Enabled
+	}
+	if v := p.AllowDeletions; v != nil {
+		r.AllowDeletions = v.Enabled
+	}
+	if v := p.RequiredConversationResolution; v != nil {
+		r.RequireConversationResolution = v.Enabled
+	}
+	r.LockBranch = p.GetLockBranch().GetEnabled()
+}
@@ -0,0 +1,220 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// currentProtection es la protección clásica vigente de main en protectionServer
+const currentProtection = `{
+	"required_status_checks": {"strict": true, "checks": [{"context": "ci", "app_id": 15}]},
+	"required_pull_request_reviews": {"dismiss_stale_reviews": true, "require_code_owner_reviews": false, "required_approving_review_count": 2, "require_last_push_approval": false},
+	"enforce_admins": {"enabled": true},
+	"required_linear_history": {"enabled": true},
+	"allow_force_pushes": {"enabled": false},
+	"allow_deletions": {"enabled": false},
+	"restrictions": {"users": [{"login": "ana"}], "teams": [], "apps": []}
+}`
+
+// protectionServer simula la protección de la rama main (sin proteger si unprotected)
+// y guarda el cuerpo de cada PUT
+type protectionServer struct {
+	unprotected bool
+
+	mu   sync.Mutex
+	puts []map[string]json.RawMessage
+}
+
+func (s *protectionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/o/r/branches/main/protection":
+		if s.unprotected {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Branch not protected"}`))
+			return
+		}
+		w.Write([]byte(currentProtection))
+	case r.Method == http.MethodPut && r.URL.Path == "/api/v3/repos/o/r/branches/main/protection":
+		raw, _ := io.ReadAll(r.Body)
+		var body map[string]json.RawMessage
+		json.Unmarshal(raw, &body)
+		s.mu.Lock()
+		s.puts = append(s.puts, body)
+		s.mu.Unlock()
+		w.Write([]byte(currentProtection))
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/o/r/rules/branches/main":
+		w.Write([]byte(`[{"type":"required_signatures","ruleset_id":4,"ruleset_source":"o/r","ruleset_source_type":"Repository"}]`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// sent devuelve los campos indicados del último PUT como JSON compacto
+func (s *protectionServer) sent(t *testing.T, fields ...string) map[string]string {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.puts) != 1 {
+		t.Fatalf("PUT requests = %d, want 1", len(s.puts))
+	}
+	result := map[string]string{}
+	for _, field := range fields {
+		var value interface{}
+		json.Unmarshal(s.puts[0][field], &value)
+		compact, _ := json.Marshal(value)
+		result[field] = string(compact)
+	}
+	return result
+}
+
+func TestGetBranchProtection(t *testing.T) {
+	protection, err := GetBranchProtection(newTestClient(t, &protectionServer{}), context.Background(), "o", "r", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &BranchProtection{
+		Branch:               "main",
+		Protected:            true,
+		RequiredReviews:      &RequiredReviews{Approvals: 2, DismissStale: true},
+		RequiredStatusChecks: &RequiredStatusChecks{Strict: true, Checks: []string{"ci"}},
+		PushRestrictions:     &Actors{Users: []string{"ana"}, Teams: []string{}, Apps: []string{}},
+		EnforceAdmins:        true,
+		RequireLinearHistory: true,
+		Rules:                []BranchRule{{Type: "required_signatures", RulesetID: 4, RulesetSource: "o/r"}},
+	}
+	if !reflect.DeepEqual(protection, want) {
+		t.Errorf("protection = %+v\nwant %+v", protection, want)
+	}
+
+	// Sin protección clásica solo quedan las reglas de los rulesets
+	protection, err = GetBranchProtection(newTestClient(t, &protectionServer{unprotected: true}), context.Background(), "o", "r", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if protection.Protected || protection.RequiredReviews != nil || protection.RequiredStatusChecks != nil || len(protection.Rules) != 1 {
+		t.Errorf("unprotected = %+v", protection)
+	}
+}
+
+func TestUpdateBranchProtection(t *testing.T) {
+	yes, no := true, false
+	checks := []string{"ci", "lint"}
+	fields := []string{"required_pull_request_reviews", "required_status_checks", "enforce_admins", "required_linear_history", "restrictions"}
+	// La protección vigente, tal como se reenvía al reescribirla sin cambios
+	reviews := `{"dismiss_stale_reviews":true,"require_code_owner_reviews":false,"require_last_push_approval":false,"required_approving_review_count":2}`
+	statusChecks := `{"checks":[{"app_id":15,"context":"ci"}],"strict":true}`
+	restrictions := `{"apps":[],"teams":[],"users":["ana"]}`
+
+	tests := []struct {
+		name        string
+		unprotected bool
+		changes     BranchProtectionChanges
+		want        map[string]string
+	}{
+		{
+			name:    "remove required reviews",
+			changes: BranchProtectionChanges{RequireReviews: &no},
+			want: map[string]string{
+				"required_pull_request_reviews": `null`, "required_status_checks": statusChecks,
+				"enforce_admins": `true`, "required_linear_history": `true`, "restrictions": restrictions,
+			},
+		},
+		{
+			name:    "remove required status checks",
+			changes: BranchProtectionChanges{RequireStatusChecks: &no},
+			want: map[string]string{
+				"required_pull_request_reviews": reviews, "required_status_checks": `null`,
+				"enforce_admins": `true`, "required_linear_history": `true`, "restrictions": restrictions,
+			},
+		},
+		{
+			name:    "change approvals and checks",
+			changes: BranchProtectionChanges{RequiredApprovals: intPtr(1), StatusChecks: &checks, StrictStatusChecks: &no, EnforceAdmins: &no},
+			want: map[string]string{
+				"required_pull_request_reviews": `{"dismiss_stale_reviews":true,"require_code_owner_reviews":false,"require_last_push_approval":false,"required_approving_review_count":1}`,
+				"required_status_checks":        `{"checks":[{"app_id":15,"context":"ci"},{"context":"lint"}],"strict":false}`,
+				"enforce_admins":                `false`, "required_linear_history": `true`, "restrictions": restrictions,
+			},
+		},
+		{
+			name:        "protect an unprotected branch",
+			unprotected: true,
+			changes:     BranchProtectionChanges{RequireReviews: &yes, StatusChecks: &checks},
+			want: map[string]string{
+				"required_pull_request_reviews": `{"dismiss_stale_reviews":false,"require_code_owner_reviews":false,"required_approving_review_count":1}`,
+				"required_status_checks":        `{"checks":[{"context":"ci"},{"context":"lint"}],"strict":false}`,
+				"enforce_admins":                `false`, "required_linear_history": `null`, "restrictions": `null`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &protectionServer{unprotected: tt.unprotected}
+			if _, err := UpdateBranchProtection(newTestClient(t, server), context.Background(), "o", "r", "main", tt.changes); err != nil {
+				t.Fatal(err)
+			}
+			if got := server.sent(t, fields...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PUT body:\n got %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+
+	server := &protectionServer{}
+	if _, err := UpdateBranchProtection(newTestClient(t, server), context.Background(), "o", "r", "main", BranchProtectionChanges{}); err == nil {
+		t.Error("no changes: no error")
+	}
+	if len(server.puts) != 0 {
+		t.Errorf("no changes: %d PUT requests", len(server.puts))
+	}
+}
+
+func TestDeleteBranch(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+strings.TrimPrefix(r.URL.Path, "/api/v3/repos/o/r"))
+		mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/o/r":
+			w.Write([]byte(`{"default_branch":"main"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/o/r/git/ref/heads/feature":
+			w.Write([]byte(`{"ref":"refs/heads/feature","object":{"type":"commit","sha":"abc"}}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v3/repos/o/r/git/refs/heads/feature":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	ctx := context.Background()
+
+	// La rama por defecto no se borra: ni siquiera se pide su ref
+	if _, err := DeleteBranch(client, ctx, "o", "r", "main"); err == nil || !strings.Contains(err.Error(), "default branch") {
+		t.Errorf("default branch: err = %v", err)
+	}
+	if want := []string{"GET "}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+
+	requests = nil
+	ref, err := DeleteBranch(client, ctx, "o", "r", "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.SHA != "abc" || ref.Action != "deleted" {
+		t.Errorf("ref = %+v", ref)
+	}
+	if want := []string{"GET ", "GET /git/ref/heads/feature", "DELETE /git/refs/heads/feature"}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+
+	if _, err := DeleteBranch(client, ctx, "o", "r", "missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("missing branch: err = %v", err)
+	}
+}
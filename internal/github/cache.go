@@ -0,0 +1,152 @@
+package github
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Límites de la caché de respuestas
+const (
+	maxCacheEntries = 500
+	maxCachedBody   = 1 << 20 // las respuestas mayores (logs, diffs enormes) no se guardan
+)
+
+// etagTransport guarda las respuestas GET de la API que traen ETag y las revalida con
+// If-None-Match: un 304 no consume cupo y se responde con el cuerpo guardado. Solo se
+// aplica a los hosts de la API, no a las descargas redirigidas.
+type etagTransport struct {
+	base  http.RoundTripper
+	hosts map[string]bool
+
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+	order   []string // claves por antigüedad, para descartar las más viejas
+}
+
+type cachedResponse struct {
+	etag   string
+	header http.Header
+	body   []byte
+}
+
+func newETagTransport(base http.RoundTripper) *etagTransport {
+	return &etagTransport{base: base, entries: map[string]*cachedResponse{}}
+}
+
+func (t *etagTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || !t.hosts[strings.ToLower(req.URL.Host)] ||
+		req.Header.Get("If-None-Match") != "" || req.Header.Get("Range") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	cached := t.get(key)
+	if cached != nil {
+		conditional := req.Clone(req.Context())
+		conditional.Header.Set("If-None-Match", cached.etag)
+		req = conditional
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		resp.Body.Close()
+		return cached.response(req, resp), nil
+	case resp.StatusCode == http.StatusOK && resp.Header.Get("ETag") != "":
+		return t.store(key, resp)
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		t.remove(key)
+	}
+	return resp, nil
+}
+
+// cacheKey identifica una respuesta guardada por URL, Accept y credencial: un token no
+// recibe lo que se leyó con otro. La credencial se guarda resumida, no en claro.
+func cacheKey(req *http.Request) string {
+	credential := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return req.URL.String() + "\x00" + req.Header.Get("Accept") + "\x00" + hex.EncodeToString(credential[:])
+}
+
+func (t *etagTransport) get(key string) *cachedResponse {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.entries[key]
+}
+
+// store guarda la respuesta si cabe en la caché y la devuelve con el cuerpo intacto
+func (t *etagTransport) store(key string, resp *http.Response) (*http.Response, error) {
+	if resp.ContentLength > maxCachedBody {
+		return resp, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBody+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxCachedBody {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.entries[key]; !ok {
+		t.order = append(t.order, key)
+	}
+	t.entries[key] = &cachedResponse{etag: resp.Header.Get("ETag"), header: resp.Header.Clone(), body: body}
+	for len(t.order) > maxCacheEntries {
+		delete(t.entries, t.order[0])
+		t.order = t.order[1:]
+	}
+	return resp, nil
+}
+
+func (t *etagTransport) remove(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.entries[key]; !ok {
+		return
+	}
+	delete(t.entries, key)
+	for i, k := range t.order {
+		if k == key {
+			t.order = append(t.order[:i], t.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// response reconstruye la respuesta guardada con las cabeceras de cupo del 304
+func (c *cachedResponse) response(req *http.Request, notModified *http.Response) *http.Response {
+	header := c.header.Clone()
+	for name, values := range notModified.Header {
+		if strings.HasPrefix(name, "X-Ratelimit-") || name == "Date" {
+			header[name] = values
+		}
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         notModified.Proto,
+		ProtoMajor:    notModified.ProtoMajor,
+		ProtoMinor:    notModified.ProtoMinor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(c.body)),
+		ContentLength: int64(len(c.body)),
+		Request:       req,
+	}
+}
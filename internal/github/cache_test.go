@@ -0,0 +1,267 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// etagServer sirve cuerpos con ETag y responde 304 cuando If-None-Match coincide
+type etagServer struct {
+	mu          sync.Mutex
+	bodies      map[string][]byte // ruta -> cuerpo (las demás rutas dan 404)
+	noLength    bool              // sin Content-Length (respuesta por fragmentos)
+	conditional []string          // If-None-Match recibido en cada petición
+	remaining   int
+}
+
+func (s *etagServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conditional = append(s.conditional, r.Header.Get("If-None-Match"))
+	s.remaining--
+	w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(s.remaining))
+
+	body, ok := s.bodies[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	etag := fmt.Sprintf(`"%x"`, len(body)) + `-` + r.Header.Get("Accept")
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	if s.noLength {
+		w.(http.Flusher).Flush()
+	}
+	w.Write(body)
+}
+
+func (s *etagServer) lastConditional() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conditional[len(s.conditional)-1]
+}
+
+func newTestCache(t *testing.T, server *etagServer) (*etagTransport, string) {
+	t.Helper()
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	u, _ := url.Parse(ts.URL)
+	cache := newETagTransport(http.DefaultTransport)
+	cache.hosts = map[string]bool{u.Host: true}
+	return cache, ts.URL
+}
+
+// fetch hace un GET a través de la caché y devuelve la respuesta con el cuerpo leído
+func fetch(t *testing.T, cache http.RoundTripper, url, authorization string) (*http.Response, string) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := cache.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(body)
+}
+
+func TestETagRevalidation(t *testing.T) {
+	server := &etagServer{bodies: map[string][]byte{"/repos/o/r": []byte(`{"name":"r"}`)}, remaining: 5000}
+	cache, base := newTestCache(t, server)
+
+	resp, body := fetch(t, cache, base+"/repos/o/r", "Bearer a")
+	if resp.StatusCode != 200 || body != `{"name":"r"}` || server.lastConditional() != "" {
+		t.Fatalf("first request: %d %s (If-None-Match %q)", resp.StatusCode, body, server.lastConditional())
+	}
+
+	// El 304 se devuelve como 200 con el cuerpo guardado y las cabeceras de cupo nuevas
+	resp, body = fetch(t, cache, base+"/repos/o/r", "Bearer a")
+	if server.lastConditional() == "" {
+		t.Error("second request sent without If-None-Match")
+	}
+	if resp.StatusCode != 200 || body != `{"name":"r"}` {
+		t.Errorf("revalidated response = %d %s", resp.StatusCode, body)
+	}
+	if got := resp.Header.Get("X-RateLimit-Remaining"); got != "4998" {
+		t.Errorf("X-RateLimit-Remaining = %s, want 4998", got)
+	}
+	if resp.Header.Get("ETag") == "" {
+		t.Error("cached response lost its ETag")
+	}
+
+	// Otra credencial no reutiliza lo guardado con la primera
+	fetch(t, cache, base+"/repos/o/r", "Bearer b")
+	if got := server.lastConditional(); got != "" {
+		t.Errorf("other token sent If-None-Match %q", got)
+	}
+
+	// Un 404 descarta la entrada
+	server.mu.Lock()
+	delete(server.bodies, "/repos/o/r")
+	server.mu.Unlock()
+	if resp, _ := fetch(t, cache, base+"/repos/o/r", "Bearer a"); resp.StatusCode != 404 {
+		t.Errorf("status = %d, want 404", resp.StatusCode)
+	}
+	server.mu.Lock()
+	server.bodies["/repos/o/r"] = []byte(`{"name":"r"}`)
+	server.mu.Unlock()
+	fetch(t, cache, base+"/repos/o/r", "Bearer a")
+	if got := server.lastConditional(); got != "" {
+		t.Errorf("request after 404 sent If-None-Match %q", got)
+	}
+}
+
+func TestETagCachePerToken(t *testing.T) {
+	server := &etagServer{bodies: map[string][]byte{"/api/v3/repos/o/r": []byte(`{"name":"r"}`)}}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	// La caché del cliente ve la credencial que pone authTransport
+	token := "a"
+	client, err := NewClient(tokenSourceFunc(func(context.Context, string) (string, error) { return token, nil }), ClientOptions{BaseURL: ts.URL + "/api/v3/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		token string
+		want  bool // la petición es condicional
+	}{
+		{token: "a", want: false},
+		{token: "a", want: true},
+		{token: "b", want: false},
+		{token: "b", want: true},
+		{token: "a", want: true},
+	}
+	for i, tt := range tests {
+		token = tt.token
+		repo, _, err := client.Repositories.Get(context.Background(), "o", "r")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if repo.GetName() != "r" {
+			t.Errorf("request %d: name = %q", i, repo.GetName())
+		}
+		if got := server.lastConditional() != ""; got != tt.want {
+			t.Errorf("request %d with token %s: conditional = %v, want %v", i, tt.token, got, tt.want)
+		}
+	}
+}
+
+func TestETagCacheScope(t *testing.T) {
+	server := &etagServer{bodies: map[string][]byte{"/repos/o/r": []byte(`{}`)}}
+	cache, base := newTestCache(t, server)
+	fetch(t, cache, base+"/repos/o/r", "Bearer a")
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		header map[string]string
+		want   string // If-None-Match que llega al servidor
+	}{
+		{name: "other host", method: http.MethodGet, url: strings.Replace(base, "127.0.0.1", "localhost", 1) + "/repos/o/r"},
+		{name: "not a GET", method: http.MethodHead, url: base + "/repos/o/r"},
+		{name: "caller's If-None-Match", method: http.MethodGet, url: base + "/repos/o/r", header: map[string]string{"If-None-Match": `"other"`}, want: `"other"`},
+		{name: "range request", method: http.MethodGet, url: base + "/repos/o/r", header: map[string]string{"Range": "bytes=0-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.url, nil)
+			req.Header.Set("Accept", "application/vnd.github+json")
+			req.Header.Set("Authorization", "Bearer a")
+			for name, value := range tt.header {
+				req.Header.Set(name, value)
+			}
+			resp, err := cache.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if got := server.lastConditional(); got != tt.want {
+				t.Errorf("If-None-Match = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if len(cache.entries) != 1 {
+		t.Errorf("entries = %d, want 1", len(cache.entries))
+	}
+}
+
+func TestETagCacheEviction(t *testing.T) {
+	server := &etagServer{bodies: map[string][]byte{}}
+	for i := 0; i <= maxCacheEntries; i++ {
+		server.bodies[fmt.Sprintf("/items/%d", i)] = []byte(`{}`)
+	}
+	cache, base := newTestCache(t, server)
+
+	for i := 0; i <= maxCacheEntries; i++ {
+		fetch(t, cache, fmt.Sprintf("%s/items/%d", base, i), "")
+	}
+	if len(cache.entries) != maxCacheEntries || len(cache.order) != maxCacheEntries {
+		t.Fatalf("entries = %d (order %d), want %d", len(cache.entries), len(cache.order), maxCacheEntries)
+	}
+
+	// La entrada más antigua se ha descartado; la más reciente sigue
+	fetch(t, cache, base+"/items/0", "")
+	if got := server.lastConditional(); got != "" {
+		t.Errorf("evicted entry sent If-None-Match %q", got)
+	}
+	fetch(t, cache, fmt.Sprintf("%s/items/%d", base, maxCacheEntries), "")
+	if server.lastConditional() == "" {
+		t.Error("newest entry was evicted")
+	}
+}
+
+func TestETagLargeBody(t *testing.T) {
+	large := bytes.Repeat([]byte("0123456789abcdef"), maxCachedBody/16+100)
+
+	for _, noLength := range []bool{false, true} {
+		t.Run(fmt.Sprintf("noLength=%v", noLength), func(t *testing.T) {
+			server := &etagServer{bodies: map[string][]byte{"/logs": large}, noLength: noLength}
+			cache, base := newTestCache(t, server)
+
+			_, body := fetch(t, cache, base+"/logs", "")
+			if body != string(large) {
+				t.Fatalf("body = %d bytes (prefix %q), want %d bytes intact", len(body), body[:min(len(body), 16)], len(large))
+			}
+			if len(cache.entries) != 0 {
+				t.Errorf("large body cached")
+			}
+
+			// No se guarda: la siguiente petición no es condicional
+			fetch(t, cache, base+"/logs", "")
+			if got := server.lastConditional(); got != "" {
+				t.Errorf("If-None-Match = %q after a large body", got)
+			}
+		})
+	}
+
+	// Un cuerpo que cabe se guarda
+	small := strings.Repeat("x", maxCachedBody)
+	server := &etagServer{bodies: map[string][]byte{"/small": []byte(small)}, noLength: true}
+	cache, base := newTestCache(t, server)
+	if _, body := fetch(t, cache, base+"/small", ""); body != small {
+		t.Fatalf("body = %d bytes, want %d", len(body), len(small))
+	}
+	if len(cache.entries) != 1 {
+		t.Errorf("entries = %d, want 1", len(cache.entries))
+	}
+}
@@ -0,0 +1,360 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// maxAnnotationsPerRequest es el máximo de anotaciones que admite la API por petición
+const maxAnnotationsPerRequest = 50
+
+// CheckRunRequest describe un check run que se crea desde el servidor (p. ej. el resultado
+// de un linter local). Las anotaciones se envían en lotes de 50.
+type CheckRunRequest struct {
+	Name        string
+	HeadSHA     string
+	Status      string // queued, in_progress o completed (default: completed si hay conclusión)
+	Conclusion  string // success, failure, neutral, cancelled, skipped, timed_out, action_required
+	Title       string
+	Summary     string
+	Text        string
+	DetailsURL  string
+	ExternalID  string
+	Annotations []CheckAnnotation
+}
+
+// GetChecksSummary resume los check runs (GitHub Actions, Apps) y los commit statuses de una ref
+func GetChecksSummary(client *github.Client, ctx context.Context, owner, repoName, ref string) (*ChecksSummary, error) {
+	runs, err := listCheckRuns(client, ctx, owner, repoName, ref)
+	if err != nil {
+		return nil, err
+	}
+	combined, err := getCombinedStatus(client, ctx, owner, repoName, ref)
+	if err != nil {
+		return nil, err
+	}
+	return summarize(ref, runs, combined.Statuses), nil
+}
+
+// GetChecks obtiene el detalle de los checks de una ref: check runs (con sus anotaciones
+// si annotations es true), check suites y commit statuses, además del resumen. Sin ref
+// se usa la rama por defecto del repositorio.
+func GetChecks(client *github.Client, ctx context.Context, owner, repoName, ref string, annotations bool) (*ChecksReport, error) {
+	if ref == "" {
+		repo, _, err := client.Repositories.Get(ctx, owner, repoName)
+		if err != nil {
+			return nil, err
+		}
+		ref = repo.GetDefaultBranch()
+	}
+
+	runs, err := listCheckRuns(client, ctx, owner, repoName, ref)
+	if err != nil {
+		return nil, err
+	}
+	combined, err := getCombinedStatus(client, ctx, owner, repoName, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ChecksReport{
+		Summary:   summarize(ref, runs, combined.Statuses),
+		SHA:       combined.GetSHA(),
+		CheckRuns: []CheckRunInfo{},
+		Suites:    []CheckSuiteInfo{},
+		Statuses:  []CommitStatusInfo{},
+	}
+
+	for _, run := range runs {
+		info := checkRunInfo(run)
+		if annotations && info.AnnotationsCount > 0 {
+			if info.Annotations, err = listAnnotations(client, ctx, owner, repoName, run.GetID()); err != nil {
+				return nil, err
+			}
+		}
+		report.CheckRuns = append(report.CheckRuns, info)
+	}
+
+	opt := &github.ListCheckSuiteOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		suites, resp, err := client.Checks.ListCheckSuitesForRef(ctx, owner, repoName, ref, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, suite := range suites.CheckSuites {
+			report.Suites = append(report.Suites, CheckSuiteInfo{
+				ID:         suite.GetID(),
+				App:        suite.GetApp().GetName(),
+				Branch:     suite.GetHeadBranch(),
+				Status:     suite.GetStatus(),
+				Conclusion: suite.GetConclusion(),
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	for _, status := range combined.Statuses {
+		report.Statuses = append(report.Statuses, CommitStatusInfo{
+			Context:     status.GetContext(),
+			State:       status.GetState(),
+			Description: status.GetDescription(),
+			URL:         status.GetTargetURL(),
+		})
+	}
+	return report, nil
+}
+
+// CreateCheckRun crea un check run. Requiere autenticación como GitHub App.
+func CreateCheckRun(client *github.Client, ctx context.Context, owner, repoName string, req CheckRunRequest) (*CheckRunInfo, error) {
+	status := req.Status
+	if status == "" {
+		status = "in_progress"
+		if req.Conclusion != "" {
+			status = "completed"
+		}
+	}
+	if status == "completed" && req.Conclusion == "" {
+		return nil, fmt.Errorf("conclusion required for a completed check run")
+	}
+
+	first, rest := req.Annotations, []CheckAnnotation(nil)
+	if len(first) > maxAnnotationsPerRequest {
+		first, rest = first[:maxAnnotationsPerRequest], first[maxAnnotationsPerRequest:]
+	}
+
+	opts := github.CreateCheckRunOptions{
+		Name:    req.Name,
+		HeadSHA: req.HeadSHA,
+		Status:  github.String(status),
+		Output:  req.output(first),
+	}
+	if req.Conclusion != "" {
+		opts.Conclusion = github.String(req.Conclusion)
+	}
+	if req.DetailsURL != "" {
+		opts.DetailsURL = github.String(req.DetailsURL)
+	}
+	if req.ExternalID != "" {
+		opts.ExternalID = github.String(req.ExternalID)
+	}
+
+	run, _, err := client.Checks.CreateCheckRun(ctx, owner, repoName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// El resto de anotaciones se añaden actualizando el check run
+	for len(rest) > 0 {
+		batch := rest
+		if len(batch) > maxAnnotationsPerRequest {
+			batch = batch[:maxAnnotationsPerRequest]
+		}
+		rest = rest[len(batch):]
+		if run, _, err = client.Checks.UpdateCheckRun(ctx, owner, repoName, run.GetID(), github.UpdateCheckRunOptions{
+			Name:   req.Name,
+			Output: req.output(batch),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	info := checkRunInfo(run)
+	info.Annotations = req.Annotations
+	return &info, nil
+}
+
+// CreateStatus crea un commit status (success, failure, error o pending) para un SHA
+func CreateStatus(client *github.Client, ctx context.Context, owner, repoName, sha, state, statusContext, description, targetURL string) (*CommitStatusInfo, error) {
+	input := &github.RepoStatus{State: github.String(state), Context: github.String(statusContext)}
+	if description != "" {
+		input.Description = github.String(description)
+	}
+	if targetURL != "" {
+		input.TargetURL = github.String(targetURL)
+	}
+
+	status, _, err := client.Repositories.CreateStatus(ctx, owner, repoName, sha, input)
+	if err != nil {
+		return nil, err
+	}
+	return &CommitStatusInfo{
+		Context:     status.GetContext(),
+		State:       status.GetState(),
+		Description: status.GetDescription(),
+		URL:         status.GetTargetURL(),
+		SHA:         sha,
+	}, nil
+}
+
+// output construye la salida del check run con un lote de anotaciones
+func (r CheckRunRequest) output(annotations []CheckAnnotation) *github.CheckRunOutput {
+	if r.Title == "" && r.Summary == "" && len(annotations) == 0 {
+		return nil
+	}
+	title, summary := r.Title, r.Summary
+	if title == "" {
+		title = r.Name
+	}
+	if summary == "" {
+		summary = fmt.Sprintf("%d annotations", len(r.Annotations))
+	}
+
+	output := &github.CheckRunOutput{Title: github.String(title), Summary: github.String(summary)}
+	if r.Text != "" {
+		output.Text = github.String(r.Text)
+	}
+	for _, a := range annotations {
+		endLine := a.EndLine
+		if endLine == 0 {
+			endLine = a.StartLine
+		}
+		level := a.Level
+		if level == "" {
+			level = "failure"
+		}
+		annotation := &github.CheckRunAnnotation{
+			Path:            github.String(a.Path),
+			StartLine:       github.Int(a.StartLine),
+			EndLine:         github.Int(endLine),
+			AnnotationLevel: github.String(level),
+			Message:         github.String(a.Message),
+		}
+		// La API solo admite columnas en anotaciones de una sola línea
+		if a.StartColumn > 0 && endLine == a.StartLine {
+			annotation.StartColumn = github.Int(a.StartColumn)
+			annotation.EndColumn = github.Int(max(a.EndColumn, a.StartColumn))
+		}
+		if a.Title != "" {
+			annotation.Title = github.String(a.Title)
+		}
+		output.Annotations = append(output.Annotations, annotation)
+	}
+	return output
+}
+
+// listCheckRuns devuelve todos los check runs de una ref
+func listCheckRuns(client *github.Client, ctx context.Context, owner, repoName, ref string) ([]*github.CheckRun, error) {
+	var all []*github.CheckRun
+	opt := &github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		runs, resp, err := client.Checks.ListCheckRunsForRef(ctx, owner, repoName, ref, opt)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, runs.CheckRuns...)
+		if resp.NextPage == 0 {
+			return all, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+// getCombinedStatus devuelve el estado combinado de una ref con todos sus statuses
+func getCombinedStatus(client *github.Client, ctx context.Context, owner, repoName, ref string) (*github.CombinedStatus, error) {
+	var combined *github.CombinedStatus
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := client.Repositories.GetCombinedStatus(ctx, owner, repoName, ref, opt)
+		if err != nil {
+			return nil, err
+		}
+		if combined == nil {
+			combined = page
+		} else {
+			combined.Statuses = append(combined.Statuses, page.Statuses...)
+		}
+		if resp.NextPage == 0 {
+			return combined, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+// listAnnotations devuelve todas las anotaciones de un check run
+func listAnnotations(client *github.Client, ctx context.Context, owner, repoName string, runID int64) ([]CheckAnnotation, error) {
+	result := []CheckAnnotation{}
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		annotations, resp, err := client.Checks.ListCheckRunAnnotations(ctx, owner, repoName, runID, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range annotations {
+			result = append(result, CheckAnnotation{
+				Path:        a.GetPath(),
+				StartLine:   a.GetStartLine(),
+				EndLine:     a.GetEndLine(),
+				StartColumn: a.GetStartColumn(),
+				EndColumn:   a.GetEndColumn(),
+				Level:       a.GetAnnotationLevel(),
+				Title:       a.GetTitle(),
+				Message:     a.GetMessage(),
+			})
+		}
+		if resp.NextPage == 0 {
+			return result, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+// summarize cuenta los check runs y commit statuses de una ref
+func summarize(ref string, runs []*github.CheckRun, statuses []*github.RepoStatus) *ChecksSummary {
+	summary := &ChecksSummary{Ref: ref}
+	for _, run := range runs {
+		if run.GetStatus() != "completed" {
+			summary.add("pending")
+			continue
+		}
+		summary.add(run.GetConclusion())
+	}
+	for _, status := range statuses {
+		summary.add(status.GetState())
+	}
+
+	switch {
+	case summary.Failure > 0:
+		summary.State = "failure"
+	case summary.Pending > 0:
+		summary.State = "pending"
+	case summary.Total > 0:
+		summary.State = "success"
+	default:
+		summary.State = "none"
+	}
+	return summary
+}
+
+// add cuenta un check run (por su conclusión) o un commit status (por su estado)
+func (s *ChecksSummary) add(result string) {
+	s.Total++
+	switch result {
+	case "success", "neutral":
+		s.Success++
+	case "skipped":
+		s.Skipped++
+	case "pending", "queued", "in_progress", "":
+		s.Pending++
+	default: // failure, error, cancelled, timed_out, action_required, stale...
+		s.Failure++
+	}
+}
+
+func checkRunInfo(run *github.CheckRun) CheckRunInfo {
+	return CheckRunInfo{
+		ID:               run.GetID(),
+		Name:             run.GetName(),
+		App:              run.GetApp().GetName(),
+		Status:           run.GetStatus(),
+		Conclusion:       run.GetConclusion(),
+		Title:            run.GetOutput().GetTitle(),
+		Summary:          run.GetOutput().GetSummary(),
+		URL:              run.GetHTMLURL(),
+		AnnotationsCount: run.GetOutput().GetAnnotationsCount(),
+	}
+}
@@ -0,0 +1,201 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestSummarize(t *testing.T) {
+	run := func(status, conclusion string) *github.CheckRun {
+		return &github.CheckRun{Status: github.String(status), Conclusion: github.String(conclusion)}
+	}
+	status := func(state string) *github.RepoStatus { return &github.RepoStatus{State: github.String(state)} }
+
+	tests := []struct {
+		name     string
+		runs     []*github.CheckRun
+		statuses []*github.RepoStatus
+		want     ChecksSummary
+	}{
+		{name: "nothing", want: ChecksSummary{State: "none"}},
+		{
+			name:     "all green",
+			runs:     []*github.CheckRun{run("completed", "success"), run("completed", "neutral"), run("completed", "skipped")},
+			statuses: []*github.RepoStatus{status("success")},
+			want:     ChecksSummary{State: "success", Total: 4, Success: 3, Skipped: 1},
+		},
+		{
+			name: "running",
+			runs: []*github.CheckRun{run("completed", "success"), run("in_progress", ""), run("queued", "")},
+			want: ChecksSummary{State: "pending", Total: 3, Success: 1, Pending: 2},
+		},
+		{
+			name:     "pending status",
+			statuses: []*github.RepoStatus{status("success"), status("pending")},
+			want:     ChecksSummary{State: "pending", Total: 2, Success: 1, Pending: 1},
+		},
+		{
+			name:     "failure wins over pending",
+			runs:     []*github.CheckRun{run("in_progress", ""), run("completed", "timed_out")},
+			statuses: []*github.RepoStatus{status("error")},
+			want:     ChecksSummary{State: "failure", Total: 3, Pending: 1, Failure: 2},
+		},
+		{
+			name: "other failed conclusions",
+			runs: []*github.CheckRun{run("completed", "cancelled"), run("completed", "action_required"), run("completed", "stale")},
+			want: ChecksSummary{State: "failure", Total: 3, Failure: 3},
+		},
+		{
+			name: "only skipped",
+			runs: []*github.CheckRun{run("completed", "skipped")},
+			want: ChecksSummary{State: "success", Total: 1, Skipped: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want.Ref = "main"
+			if got := summarize("main", tt.runs, tt.statuses); *got != tt.want {
+				t.Errorf("summarize = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+// checkRunServer registra las anotaciones de cada petición de creación o actualización de check runs
+type checkRunServer struct {
+	mu       sync.Mutex
+	requests []string // "POST 50", "PATCH 20"...
+	bodies   []map[string]interface{}
+}
+
+func (s *checkRunServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+		Output *struct {
+			Title       string                   `json:"title"`
+			Summary     string                   `json:"summary"`
+			Annotations []map[string]interface{} `json:"annotations"`
+		} `json:"output"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	s.mu.Lock()
+	count := 0
+	if body.Output != nil {
+		count = len(body.Output.Annotations)
+		s.bodies = append(s.bodies, body.Output.Annotations...)
+	}
+	s.requests = append(s.requests, fmt.Sprintf("%s %d", r.Method, count))
+	s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/o/r/check-runs":
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPatch && r.URL.Path == "/api/v3/repos/o/r/check-runs/9":
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	fmt.Fprintf(w, `{"id":9,"name":%q,"status":"completed","conclusion":"failure"}`, body.Name)
+}
+
+func TestCreateCheckRunBatches(t *testing.T) {
+	annotations := func(n int) []CheckAnnotation {
+		list := make([]CheckAnnotation, n)
+		for i := range list {
+			list[i] = CheckAnnotation{Path: "a.go", StartLine: i + 1, Message: fmt.Sprintf("issue %d", i)}
+		}
+		return list
+	}
+
+	tests := []struct {
+		count int
+		want  []string
+	}{
+		{count: 0, want: []string{"POST 0"}},
+		{count: 1, want: []string{"POST 1"}},
+		{count: maxAnnotationsPerRequest, want: []string{"POST 50"}},
+		{count: maxAnnotationsPerRequest + 1, want: []string{"POST 50", "PATCH 1"}},
+		{count: 120, want: []string{"POST 50", "PATCH 50", "PATCH 20"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.count), func(t *testing.T) {
+			server := &checkRunServer{}
+			client := newTestClient(t, server)
+
+			info, err := CreateCheckRun(client, context.Background(), "o", "r", CheckRunRequest{
+				Name:        "lint",
+				HeadSHA:     "0123456789abcdef0123456789abcdef01234567",
+				Conclusion:  "failure",
+				Annotations: annotations(tt.count),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(server.requests, tt.want) {
+				t.Errorf("requests = %q, want %q", server.requests, tt.want)
+			}
+			if info.ID != 9 || len(info.Annotations) != tt.count {
+				t.Errorf("info = %+v, want id 9 with %d annotations", info, tt.count)
+			}
+			// Cada anotación se envía una vez y en orden
+			for i, annotation := range server.bodies {
+				if annotation["start_line"] != float64(i+1) {
+					t.Fatalf("annotation %d has start_line %v", i, annotation["start_line"])
+				}
+			}
+			if len(server.bodies) != tt.count {
+				t.Errorf("annotations sent = %d, want %d", len(server.bodies), tt.count)
+			}
+		})
+	}
+
+	if _, err := CreateCheckRun(newTestClient(t, &checkRunServer{}), context.Background(), "o", "r", CheckRunRequest{Name: "lint", Status: "completed"}); err == nil {
+		t.Error("completed without conclusion: no error")
+	}
+}
+
+func TestGetChecksDefaultBranch(t *testing.T) {
+	var mu sync.Mutex
+	var refs []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/o/r":
+			w.Write([]byte(`{"default_branch":"trunk"}`))
+			return
+		case "/api/v3/repos/o/r/commits/trunk/check-runs", "/api/v3/repos/o/r/commits/v2/check-runs":
+			w.Write([]byte(`{"total_count":1,"check_runs":[{"id":1,"status":"completed","conclusion":"success"}]}`))
+		case "/api/v3/repos/o/r/commits/trunk/status", "/api/v3/repos/o/r/commits/v2/status":
+			w.Write([]byte(`{"sha":"abc","statuses":[]}`))
+		case "/api/v3/repos/o/r/commits/trunk/check-suites", "/api/v3/repos/o/r/commits/v2/check-suites":
+			w.Write([]byte(`{"total_count":0,"check_suites":[]}`))
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		refs = append(refs, r.URL.Path)
+		mu.Unlock()
+	}))
+
+	for _, tt := range []struct{ ref, want string }{{ref: "", want: "trunk"}, {ref: "v2", want: "v2"}} {
+		report, err := GetChecks(client, context.Background(), "o", "r", tt.ref, false)
+		if err != nil {
+			t.Fatalf("ref %q: %v", tt.ref, err)
+		}
+		if report.Summary.Ref != tt.want || report.Summary.State != "success" || report.SHA != "abc" {
+			t.Errorf("ref %q: summary = %+v, sha %s", tt.ref, report.Summary, report.SHA)
+		}
+	}
+	if len(refs) != 6 {
+		t.Errorf("requests = %q", refs)
+	}
+}
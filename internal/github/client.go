@@ -2,42 +2,44 @@ package github
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"time"
 
 	"github.com/google/go-github/v66/github"
 )
 
 // ListRepositories lista repositorios del usuario
-func ListRepositories(client *github.Client, ctx context.Context, listType string) (string, error) {
+func ListRepositories(client *github.Client, ctx context.Context, listType string, opts PageOptions) (*RepositoryList, error) {
 	if listType == "" {
 		listType = "all"
 	}
 
-	opt := &github.RepositoryListOptions{Type: listType}
-	repos, _, err := client.Repositories.List(ctx, "", opt)
+	repos, next, err := paginate(opts, func(page github.ListOptions) ([]RepositorySummary, *github.Response, error) {
+		repos, resp, err := client.Repositories.List(ctx, "", &github.RepositoryListOptions{Type: listType, ListOptions: page})
+		if err != nil {
+			return nil, nil, err
+		}
+		summaries := make([]RepositorySummary, 0, len(repos))
+		for _, repo := range repos {
+			summaries = append(summaries, RepositorySummary{
+				Name:        repo.GetName(),
+				Description: repo.GetDescription(),
+				Private:     repo.GetPrivate(),
+				URL:         repo.GetHTMLURL(),
+				Language:    repo.GetLanguage(),
+				Stars:       repo.GetStargazersCount(),
+			})
+		}
+		return summaries, resp, nil
+	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	var result []map[string]interface{}
-	for _, repo := range repos {
-		result = append(result, map[string]interface{}{
-			"name":        repo.GetName(),
-			"description": repo.GetDescription(),
-			"private":     repo.GetPrivate(),
-			"url":         repo.GetHTMLURL(),
-			"language":    repo.GetLanguage(),
-			"stars":       repo.GetStargazersCount(),
-		})
-	}
-
-	output, _ := json.MarshalIndent(result, "", "  ")
-	return string(output), nil
+	return &RepositoryList{Repositories: repos, NextCursor: next}, nil
 }
 
-// CreateRepository crea un nuevo repositorio
-func CreateRepository(client *github.Client, ctx context.Context, name, description string, private bool) (string, error) {
+// CreateRepository crea un nuevo repositorio del usuario autenticado o, si se indica org, de la organización
+func CreateRepository(client *github.Client, ctx context.Context, org, name, description string, private bool) (*RepositoryCreated, error) {
 	repo := &github.Repository{Name: github.String(name)}
 
 	if description != "" {
@@ -46,45 +48,53 @@ func CreateRepository(client *github.Client, ctx context.Context, name, descript
 
 	repo.Private = github.Bool(private)
 
-	createdRepo, _, err := client.Repositories.Create(ctx, "", repo)
+	createdRepo, _, err := client.Repositories.Create(ctx, org, repo)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return fmt.Sprintf("Repository '%s' created successfully: %s", createdRepo.GetName(), createdRepo.GetHTMLURL()), nil
+	return &RepositoryCreated{
+		Name:     createdRepo.GetName(),
+		FullName: createdRepo.GetFullName(),
+		Private:  createdRepo.GetPrivate(),
+		URL:      createdRepo.GetHTMLURL(),
+	}, nil
 }
 
 // ListPullRequests lista pull requests de un repositorio
-func ListPullRequests(client *github.Client, ctx context.Context, owner, repoName, state string) (string, error) {
+func ListPullRequests(client *github.Client, ctx context.Context, owner, repoName, state string, opts PageOptions) (*PullRequestList, error) {
 	if state == "" {
 		state = "open"
 	}
 
-	opt := &github.PullRequestListOptions{State: state}
-	prs, _, err := client.PullRequests.List(ctx, owner, repoName, opt)
+	prs, next, err := paginate(opts, func(page github.ListOptions) ([]PullRequestSummary, *github.Response, error) {
+		prs, resp, err := client.PullRequests.List(ctx, owner, repoName, &github.PullRequestListOptions{State: state, ListOptions: page})
+		if err != nil {
+			return nil, nil, err
+		}
+		summaries := make([]PullRequestSummary, 0, len(prs))
+		for _, pr := range prs {
+			summaries = append(summaries, PullRequestSummary{
+				Number: pr.GetNumber(),
+				Title:  pr.GetTitle(),
+				State:  pr.GetState(),
+				URL:    pr.GetHTMLURL(),
+				User:   pr.GetUser().GetLogin(),
+				Head:   pr.GetHead().GetRef(),
+				Base:   pr.GetBase().GetRef(),
+			})
+		}
+		return summaries, resp, nil
+	})
 	if err != nil {
-		return "", err
-	}
-
-	var result []map[string]interface{}
-	for _, pr := range prs {
-		result = append(result, map[string]interface{}{
-			"number": pr.GetNumber(),
-			"title":  pr.GetTitle(),
-			"state":  pr.GetState(),
-			"url":    pr.GetHTMLURL(),
-			"user":   pr.GetUser().GetLogin(),
-			"head":   pr.GetHead().GetRef(),
-			"base":   pr.GetBase().GetRef(),
-		})
+		return nil, err
 	}
 
-	output, _ := json.MarshalIndent(result, "", "  ")
-	return string(output), nil
+	return &PullRequestList{PullRequests: prs, NextCursor: next}, nil
 }
 
 // CreatePullRequest crea un nuevo pull request
-func CreatePullRequest(client *github.Client, ctx context.Context, owner, repoName, title, body, head, base string) (string, error) {
+func CreatePullRequest(client *github.Client, ctx context.Context, owner, repoName, title, body, head, base string) (*PullRequestCreated, error) {
 	pr := &github.NewPullRequest{
 		Title: github.String(title),
 		Head:  github.String(head),
@@ -97,14 +107,14 @@ func CreatePullRequest(client *github.Client, ctx context.Context, owner, repoNa
 
 	createdPR, _, err := client.PullRequests.Create(ctx, owner, repoName, pr)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return fmt.Sprintf("Pull Request #%d created: %s", createdPR.GetNumber(), createdPR.GetHTMLURL()), nil
+	return &PullRequestCreated{Number: createdPR.GetNumber(), URL: createdPR.GetHTMLURL()}, nil
 }
 
 // CreateFile crea un archivo usando la GitHub API
-func CreateFile(client *github.Client, ctx context.Context, owner, repo, path, content, message, branch string) (string, error) {
+func CreateFile(client *github.Client, ctx context.Context, owner, repo, path, content, message, branch string) (*FileCommit, error) {
 	if branch == "" {
 		branch = "main"
 	}
@@ -117,14 +127,20 @@ func CreateFile(client *github.Client, ctx context.Context, owner, repo, path, c
 
 	result, _, err := client.Repositories.CreateFile(ctx, owner, repo, path, fileOptions)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return fmt.Sprintf("File '%s' created successfully via API. Commit SHA: %s", path, result.Commit.GetSHA()), nil
+	return &FileCommit{
+		Path:      path,
+		Action:    "created",
+		Branch:    branch,
+		SHA:       result.GetContent().GetSHA(),
+		CommitSHA: result.Commit.GetSHA(),
+	}, nil
 }
 
 // UpdateFile actualiza un archivo usando la GitHub API
-func UpdateFile(client *github.Client, ctx context.Context, owner, repo, path, content, message, sha, branch string) (string, error) {
+func UpdateFile(client *github.Client, ctx context.Context, owner, repo, path, content, message, sha, branch string) (*FileCommit, error) {
 	if branch == "" {
 		branch = "main"
 	}
@@ -136,10 +152,56 @@ func UpdateFile(client *github.Client, ctx context.Context, owner, repo, path, c
 		Branch:  github.String(branch),
 	}
 
-	_, _, err := client.Repositories.UpdateFile(ctx, owner, repo, path, fileOptions)
+	result, _, err := client.Repositories.UpdateFile(ctx, owner, repo, path, fileOptions)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return fmt.Sprintf("File '%s' updated successfully via API", path), nil
+	return &FileCommit{
+		Path:      path,
+		Action:    "updated",
+		Branch:    branch,
+		SHA:       result.GetContent().GetSHA(),
+		CommitSHA: result.Commit.GetSHA(),
+	}, nil
+}
+
+// GetPullRequest obtiene un pull request con su mergeabilidad, revisores pendientes y resumen de checks
+func GetPullRequest(client *github.Client, ctx context.Context, owner, repoName string, number int) (*PullRequestDetail, error) {
+	pr, _, err := client.PullRequests.Get(ctx, owner, repoName, number)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PullRequestDetail{
+		Number:    pr.GetNumber(),
+		Title:     pr.GetTitle(),
+		Body:      pr.GetBody(),
+		State:     pr.GetState(),
+		Draft:     pr.GetDraft(),
+		Merged:    pr.GetMerged(),
+		URL:       pr.GetHTMLURL(),
+		User:      pr.GetUser().GetLogin(),
+		Head:      pr.GetHead().GetRef(),
+		Base:      pr.GetBase().GetRef(),
+		Commits:   pr.GetCommits(),
+		Additions: pr.GetAdditions(),
+		Deletions: pr.GetDeletions(),
+		CreatedAt: pr.GetCreatedAt().Format(time.RFC3339),
+		UpdatedAt: pr.GetUpdatedAt().Format(time.RFC3339),
+
+		HeadSHA:        pr.GetHead().GetSHA(),
+		Mergeable:      pr.Mergeable,
+		MergeableState: pr.GetMergeableState(),
+	}
+	result.RequestedReviewers, result.RequestedTeams = requestedReviewers(pr)
+
+	// Sin permiso para leer los checks el PR se devuelve igualmente
+	if checks, err := GetChecksSummary(client, ctx, owner, repoName, result.HeadSHA); err != nil {
+		result.ChecksError = err.Error()
+	} else {
+		result.Checks = checks
+	}
+
+	return result, nil
 }
@@ -0,0 +1,458 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// Modo de los archivos nuevos en el árbol Git
+const regularFileMode = "100644"
+
+// changeActions traduce cada operación al nombre de su resultado
+var changeActions = map[string]string{"create": "created", "update": "updated", "delete": "deleted", "rename": "renamed"}
+
+// FileChange es una operación sobre un archivo de un commit de github_commit_files
+type FileChange struct {
+	Action   string // create, update, delete o rename
+	Path     string // en rename, la ruta nueva
+	FromPath string // en rename, la ruta original
+	Content  string // create y update; en rename, opcional (sin él se conserva el contenido)
+	Base64   bool   // Content viene codificado en base64 (archivos binarios)
+}
+
+// CommitFilesRequest es un commit de varios archivos sobre la cabeza de una rama
+type CommitFilesRequest struct {
+	Branch          string // default: la rama por defecto del repositorio
+	Message         string
+	Changes         []FileChange
+	ExpectedHeadSHA string // si se indica, no se hace el commit si la rama ha avanzado
+}
+
+// GetFile lee un archivo del repositorio en una ref (default: la rama por defecto). Los
+// archivos de más de 1 MB, que la Contents API devuelve sin contenido o rechaza por
+// tamaño, se leen con la Blobs API. El contenido binario se devuelve en base64.
+func GetFile(client *github.Client, ctx context.Context, owner, repoName, filePath, ref string) (*RemoteFile, error) {
+	file, dir, _, err := client.Repositories.GetContents(ctx, owner, repoName, filePath, &github.RepositoryContentGetOptions{Ref: ref})
+	if isTooLarge(err) {
+		file, err = directoryEntry(client, ctx, owner, repoName, filePath, ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, fmt.Errorf("%s is a directory (%d entries)", filePath, len(dir))
+	}
+
+	result := &RemoteFile{
+		Path: file.GetPath(),
+		Type: file.GetType(),
+		SHA:  file.GetSHA(),
+		Size: file.GetSize(),
+		URL:  file.GetHTMLURL(),
+	}
+	switch file.GetType() {
+	case "symlink":
+		result.Encoding, result.Content = "utf-8", file.GetTarget()
+		return result, nil
+	case "submodule":
+		result.Encoding, result.Content = "utf-8", file.GetSubmoduleGitURL()
+		return result, nil
+	}
+
+	var content []byte
+	if file.GetEncoding() == "none" || (file.GetSize() > 0 && file.Content == nil) {
+		content, _, err = client.Git.GetBlobRaw(ctx, owner, repoName, file.GetSHA())
+	} else {
+		var text string
+		text, err = file.GetContent()
+		content = []byte(text)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if utf8.Valid(content) && !bytes.ContainsRune(content, 0) {
+		result.Encoding, result.Content = "utf-8", string(content)
+	} else {
+		result.Encoding, result.Content = "base64", base64.StdEncoding.EncodeToString(content)
+	}
+	return result, nil
+}
+
+// directoryEntry busca un archivo en el listado de su directorio, que da su SHA y tamaño
+// aunque la Contents API no devuelva el archivo por ser demasiado grande
+func directoryEntry(client *github.Client, ctx context.Context, owner, repoName, filePath, ref string) (*github.RepositoryContent, error) {
+	filePath = strings.Trim(filePath, "/")
+	parent := path.Dir(filePath)
+	if parent == "." {
+		parent = ""
+	}
+	_, dir, _, err := client.Repositories.GetContents(ctx, owner, repoName, parent, &github.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range dir {
+		if entry.GetPath() == filePath {
+			return entry, nil
+		}
+	}
+	return nil, fmt.Errorf("%s not found", filePath)
+}
+
+// isTooLarge indica si la Contents API rechazó un archivo por su tamaño
+func isTooLarge(err error) bool {
+	var apiErr *github.ErrorResponse
+	if !errors.As(err, &apiErr) || statusCode(apiErr) != http.StatusForbidden {
+		return false
+	}
+	for _, e := range apiErr.Errors {
+		if e.Code == "too_large" {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(apiErr.Message), "too large")
+}
+
+// ListDirectory lista el contenido de un directorio del repositorio en una ref
+func ListDirectory(client *github.Client, ctx context.Context, owner, repoName, dirPath, ref string) (*DirectoryListing, error) {
+	file, dir, _, err := client.Repositories.GetContents(ctx, owner, repoName, dirPath, &github.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		return nil, err
+	}
+	if file != nil {
+		return nil, fmt.Errorf("%s is a file, not a directory", dirPath)
+	}
+
+	result := &DirectoryListing{Path: strings.Trim(dirPath, "/"), Entries: []DirectoryEntry{}}
+	for _, entry := range dir {
+		result.Entries = append(result.Entries, DirectoryEntry{
+			Name: entry.GetName(),
+			Path: entry.GetPath(),
+			Type: entry.GetType(),
+			Size: entry.GetSize(),
+			SHA:  entry.GetSHA(),
+		})
+	}
+	return result, nil
+}
+
+// GetTree devuelve el árbol de una ref (default: la rama por defecto), opcionalmente
+// limitado a un directorio y recursivo. GitHub trunca los árboles recursivos muy grandes.
+func GetTree(client *github.Client, ctx context.Context, owner, repoName, ref, dirPath string, recursive bool) (*TreeListing, error) {
+	if ref == "" {
+		repo, _, err := client.Repositories.Get(ctx, owner, repoName)
+		if err != nil {
+			return nil, err
+		}
+		ref = repo.GetDefaultBranch()
+	}
+
+	dirPath = strings.Trim(dirPath, "/")
+	sha := ref
+	if dirPath != "" {
+		reader := &treeReader{client: client, ctx: ctx, owner: owner, repo: repoName, root: ref}
+		entry, err := reader.entry(dirPath)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil || entry.GetType() != "tree" {
+			return nil, fmt.Errorf("directory %s not found in %s", dirPath, ref)
+		}
+		sha = entry.GetSHA()
+	}
+
+	tree, _, err := client.Git.GetTree(ctx, owner, repoName, sha, recursive)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TreeListing{Ref: ref, Path: dirPath, SHA: tree.GetSHA(), Truncated: tree.GetTruncated(), Entries: []TreeItem{}}
+	for _, entry := range tree.Entries {
+		entryPath := entry.GetPath()
+		if dirPath != "" {
+			entryPath = dirPath + "/" + entryPath
+		}
+		result.Entries = append(result.Entries, TreeItem{
+			Path: entryPath,
+			Type: entry.GetType(),
+			Mode: entry.GetMode(),
+			Size: entry.GetSize(),
+			SHA:  entry.GetSHA(),
+		})
+	}
+	return result, nil
+}
+
+// DeleteFile borra un archivo con la Contents API. Sin sha se usa el del archivo en la
+// cabeza de la rama.
+func DeleteFile(client *github.Client, ctx context.Context, owner, repoName, filePath, message, sha, branch string) (*FileCommit, error) {
+	if sha == "" {
+		file, _, _, err := client.Repositories.GetContents(ctx, owner, repoName, filePath, &github.RepositoryContentGetOptions{Ref: branch})
+		if err != nil {
+			return nil, err
+		}
+		if file == nil {
+			return nil, fmt.Errorf("%s is a directory", filePath)
+		}
+		sha = file.GetSHA()
+	}
+
+	opts := &github.RepositoryContentFileOptions{Message: github.String(message), SHA: github.String(sha)}
+	if branch != "" {
+		opts.Branch = github.String(branch)
+	}
+	result, _, err := client.Repositories.DeleteFile(ctx, owner, repoName, filePath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileCommit{
+		Path:      filePath,
+		Action:    "deleted",
+		Branch:    branch,
+		SHA:       sha,
+		CommitSHA: result.Commit.GetSHA(),
+	}, nil
+}
+
+// CommitFiles aplica varias operaciones de archivos en un único commit con la Git Data
+// API: crea los blobs, un árbol sobre el de la cabeza de la rama y el commit, y mueve la
+// rama solo si el cambio es fast-forward.
+func CommitFiles(client *github.Client, ctx context.Context, owner, repoName string, req CommitFilesRequest) (*FilesCommit, error) {
+	if len(req.Changes) == 0 {
+		return nil, fmt.Errorf("no file changes to commit")
+	}
+	if req.Message == "" {
+		return nil, fmt.Errorf("commit message required")
+	}
+
+	branch := req.Branch
+	if branch == "" {
+		repo, _, err := client.Repositories.Get(ctx, owner, repoName)
+		if err != nil {
+			return nil, err
+		}
+		branch = repo.GetDefaultBranch()
+	}
+
+	ref, _, err := client.Git.GetRef(ctx, owner, repoName, "heads/"+branch)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("branch %s not found in %s/%s", branch, owner, repoName)
+		}
+		return nil, err
+	}
+	head := ref.GetObject().GetSHA()
+	if req.ExpectedHeadSHA != "" && head != req.ExpectedHeadSHA {
+		return nil, fmt.Errorf("branch %s is at %s, expected %s", branch, head, req.ExpectedHeadSHA)
+	}
+	parent, _, err := client.Git.GetCommit(ctx, owner, repoName, head)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &treeReader{client: client, ctx: ctx, owner: owner, repo: repoName, root: parent.GetTree().GetSHA()}
+	entries, files, err := changeEntries(client, ctx, owner, repoName, tree, req.Changes)
+	if err != nil {
+		return nil, err
+	}
+
+	newTree, _, err := client.Git.CreateTree(ctx, owner, repoName, tree.root, entries)
+	if err != nil {
+		return nil, err
+	}
+	commit, _, err := client.Git.CreateCommit(ctx, owner, repoName, &github.Commit{
+		Message: github.String(req.Message),
+		Tree:    &github.Tree{SHA: newTree.SHA},
+		Parents: []*github.Commit{{SHA: github.String(head)}},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ref.Object.SHA = commit.SHA
+	if _, _, err := client.Git.UpdateRef(ctx, owner, repoName, ref, false); err != nil {
+		var apiErr *github.ErrorResponse
+		if errors.As(err, &apiErr) && statusCode(apiErr) == http.StatusUnprocessableEntity {
+			return nil, fmt.Errorf("branch %s moved while committing (commit %s was not applied): %v", branch, commit.GetSHA(), err)
+		}
+		return nil, err
+	}
+
+	return &FilesCommit{
+		Branch:    branch,
+		CommitSHA: commit.GetSHA(),
+		ParentSHA: head,
+		URL:       commit.GetHTMLURL(),
+		Files:     files,
+	}, nil
+}
+
+// changeEntries valida las operaciones contra el árbol de la cabeza y las traduce a
+// entradas del árbol nuevo, creando los blobs de los contenidos
+func changeEntries(client *github.Client, ctx context.Context, owner, repoName string, tree *treeReader, changes []FileChange) ([]*github.TreeEntry, []FileChanged, error) {
+	var entries []*github.TreeEntry
+	var files []FileChanged
+	touched := map[string]bool{}
+
+	for _, change := range changes {
+		filePath, fromPath := strings.Trim(change.Path, "/"), ""
+		if change.Action == "rename" {
+			fromPath = strings.Trim(change.FromPath, "/")
+		}
+		for _, p := range []string{filePath, fromPath} {
+			if p == "" {
+				continue
+			}
+			if touched[p] {
+				return nil, nil, fmt.Errorf("%s appears in more than one change", p)
+			}
+			touched[p] = true
+		}
+		if filePath == "" {
+			return nil, nil, fmt.Errorf("%s: path required", change.Action)
+		}
+
+		existing, err := tree.entry(filePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		mode, blobSHA := regularFileMode, ""
+
+		switch change.Action {
+		case "create":
+			if existing != nil {
+				return nil, nil, fmt.Errorf("create %s: file already exists", filePath)
+			}
+		case "update":
+			if existing == nil || existing.GetType() != "blob" {
+				return nil, nil, fmt.Errorf("update %s: file not found", filePath)
+			}
+			mode = existing.GetMode()
+		case "delete":
+			if existing == nil || existing.GetType() != "blob" {
+				return nil, nil, fmt.Errorf("delete %s: file not found", filePath)
+			}
+			entries = append(entries, &github.TreeEntry{Path: github.String(filePath), Mode: existing.Mode, Type: github.String("blob")})
+			files = append(files, FileChanged{Path: filePath, Action: changeActions["delete"]})
+			continue
+		case "rename":
+			if fromPath == "" {
+				return nil, nil, fmt.Errorf("rename %s: from_path required", filePath)
+			}
+			if existing != nil {
+				return nil, nil, fmt.Errorf("rename %s: destination already exists", filePath)
+			}
+			source, err := tree.entry(fromPath)
+			if err != nil {
+				return nil, nil, err
+			}
+			if source == nil || source.GetType() != "blob" {
+				return nil, nil, fmt.Errorf("rename %s: file not found", fromPath)
+			}
+			entries = append(entries, &github.TreeEntry{Path: github.String(fromPath), Mode: source.Mode, Type: github.String("blob")})
+			mode = source.GetMode()
+			if change.Content == "" {
+				blobSHA = source.GetSHA()
+			}
+		default:
+			return nil, nil, fmt.Errorf("unknown action %q for %s (create, update, delete or rename)", change.Action, filePath)
+		}
+
+		if blobSHA == "" {
+			content, err := changeContent(change)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s %s: %v", change.Action, filePath, err)
+			}
+			blob, _, err := client.Git.CreateBlob(ctx, owner, repoName, &github.Blob{
+				Content:  github.String(base64.StdEncoding.EncodeToString(content)),
+				Encoding: github.String("base64"),
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+			blobSHA = blob.GetSHA()
+		}
+
+		entries = append(entries, &github.TreeEntry{
+			Path: github.String(filePath),
+			Mode: github.String(mode),
+			Type: github.String("blob"),
+			SHA:  github.String(blobSHA),
+		})
+		files = append(files, FileChanged{Path: filePath, FromPath: fromPath, Action: changeActions[change.Action], SHA: blobSHA})
+	}
+	return entries, files, nil
+}
+
+// changeContent devuelve los bytes del contenido de una operación
+func changeContent(change FileChange) ([]byte, error) {
+	if !change.Base64 {
+		return []byte(change.Content), nil
+	}
+	content, err := base64.StdEncoding.DecodeString(change.Content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 content: %v", err)
+	}
+	return content, nil
+}
+
+// treeReader busca entradas en el árbol de un commit bajando directorio a directorio,
+// sin pedir el árbol recursivo completo (que se trunca en repositorios grandes)
+type treeReader struct {
+	client *github.Client
+	ctx    context.Context
+	owner  string
+	repo   string
+	root   string
+
+	trees map[string]*github.Tree // por SHA
+}
+
+// entry devuelve la entrada de una ruta, o nil si no existe
+func (r *treeReader) entry(filePath string) (*github.TreeEntry, error) {
+	dir, name := path.Split(filePath)
+	sha := r.root
+	if dir != "" {
+		parent, err := r.entry(strings.TrimSuffix(dir, "/"))
+		if err != nil || parent == nil || parent.GetType() != "tree" {
+			return nil, err
+		}
+		sha = parent.GetSHA()
+	}
+
+	tree, err := r.tree(sha)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range tree.Entries {
+		if entry.GetPath() == name {
+			return entry, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *treeReader) tree(sha string) (*github.Tree, error) {
+	if tree, ok := r.trees[sha]; ok {
+		return tree, nil
+	}
+	tree, _, err := r.client.Git.GetTree(r.ctx, r.owner, r.repo, sha, false)
+	if err != nil {
+		return nil, err
+	}
+	if r.trees == nil {
+		r.trees = map[string]*github.Tree{}
+	}
+	r.trees[sha] = tree
+	return tree, nil
+}
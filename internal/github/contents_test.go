@@ -0,0 +1,258 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// testTree es el árbol de la cabeza en las pruebas de changeEntries
+func testTree(client *github.Client) *treeReader {
+	blob := func(name, mode, sha string) *github.TreeEntry {
+		return &github.TreeEntry{Path: github.String(name), Mode: github.String(mode), Type: github.String("blob"), SHA: github.String(sha)}
+	}
+	return &treeReader{client: client, ctx: context.Background(), owner: "o", repo: "r", root: "root", trees: map[string]*github.Tree{
+		"root": {Entries: []*github.TreeEntry{
+			blob("README.md", regularFileMode, "readme"),
+			blob("run.sh", "100755", "script"),
+			{Path: github.String("docs"), Mode: github.String("040000"), Type: github.String("tree"), SHA: github.String("docs-tree")},
+		}},
+		"docs-tree": {Entries: []*github.TreeEntry{blob("guide.md", regularFileMode, "guide")}},
+	}}
+}
+
+func TestChangeEntries(t *testing.T) {
+	var mu sync.Mutex
+	var blobs []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/repos/o/r/git/blobs") {
+			http.NotFound(w, r)
+			return
+		}
+		var blob struct{ Content, Encoding string }
+		json.NewDecoder(r.Body).Decode(&blob)
+		content, _ := base64.StdEncoding.DecodeString(blob.Content)
+		mu.Lock()
+		blobs = append(blobs, string(content))
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"sha": "blob:" + string(content)})
+	}))
+
+	tests := []struct {
+		name        string
+		changes     []FileChange
+		wantEntries []string // "ruta modo sha" (sha vacío: borrado)
+		wantFiles   []FileChanged
+		wantBlobs   []string
+		wantErr     string
+	}{
+		{
+			name:        "create",
+			changes:     []FileChange{{Action: "create", Path: "new/dir/a.txt", Content: "hello"}},
+			wantEntries: []string{"new/dir/a.txt 100644 blob:hello"},
+			wantFiles:   []FileChanged{{Path: "new/dir/a.txt", Action: "created", SHA: "blob:hello"}},
+			wantBlobs:   []string{"hello"},
+		},
+		{
+			name:        "update keeps mode",
+			changes:     []FileChange{{Action: "update", Path: "/run.sh/", Content: "#!/bin/sh"}},
+			wantEntries: []string{"run.sh 100755 blob:#!/bin/sh"},
+			wantFiles:   []FileChanged{{Path: "run.sh", Action: "updated", SHA: "blob:#!/bin/sh"}},
+			wantBlobs:   []string{"#!/bin/sh"},
+		},
+		{
+			name:        "base64 content",
+			changes:     []FileChange{{Action: "update", Path: "docs/guide.md", Content: base64.StdEncoding.EncodeToString([]byte("bin\x00")), Base64: true}},
+			wantEntries: []string{"docs/guide.md 100644 blob:bin\x00"},
+			wantFiles:   []FileChanged{{Path: "docs/guide.md", Action: "updated", SHA: "blob:bin\x00"}},
+			wantBlobs:   []string{"bin\x00"},
+		},
+		{
+			name:        "delete",
+			changes:     []FileChange{{Action: "delete", Path: "docs/guide.md"}},
+			wantEntries: []string{"docs/guide.md 100644 "},
+			wantFiles:   []FileChanged{{Path: "docs/guide.md", Action: "deleted"}},
+		},
+		{
+			name:        "rename keeps content",
+			changes:     []FileChange{{Action: "rename", FromPath: "run.sh", Path: "bin/run.sh"}},
+			wantEntries: []string{"run.sh 100755 ", "bin/run.sh 100755 script"},
+			wantFiles:   []FileChanged{{Path: "bin/run.sh", FromPath: "run.sh", Action: "renamed", SHA: "script"}},
+		},
+		{
+			name:        "rename with content",
+			changes:     []FileChange{{Action: "rename", FromPath: "README.md", Path: "README", Content: "new"}},
+			wantEntries: []string{"README.md 100644 ", "README 100644 blob:new"},
+			wantFiles:   []FileChanged{{Path: "README", FromPath: "README.md", Action: "renamed", SHA: "blob:new"}},
+			wantBlobs:   []string{"new"},
+		},
+		{
+			name: "several changes",
+			changes: []FileChange{
+				{Action: "delete", Path: "README.md"},
+				{Action: "create", Path: "docs/new.md", Content: "x"},
+			},
+			wantEntries: []string{"README.md 100644 ", "docs/new.md 100644 blob:x"},
+			wantFiles:   []FileChanged{{Path: "README.md", Action: "deleted"}, {Path: "docs/new.md", Action: "created", SHA: "blob:x"}},
+			wantBlobs:   []string{"x"},
+		},
+		{name: "create existing", changes: []FileChange{{Action: "create", Path: "README.md", Content: "x"}}, wantErr: "create README.md: file already exists"},
+		{name: "update missing", changes: []FileChange{{Action: "update", Path: "missing.txt", Content: "x"}}, wantErr: "update missing.txt: file not found"},
+		{name: "update directory", changes: []FileChange{{Action: "update", Path: "docs", Content: "x"}}, wantErr: "update docs: file not found"},
+		{name: "delete missing", changes: []FileChange{{Action: "delete", Path: "docs/missing.md"}}, wantErr: "delete docs/missing.md: file not found"},
+		{name: "rename without source", changes: []FileChange{{Action: "rename", Path: "b.txt"}}, wantErr: "rename b.txt: from_path required"},
+		{name: "rename missing source", changes: []FileChange{{Action: "rename", FromPath: "a.txt", Path: "b.txt"}}, wantErr: "rename a.txt: file not found"},
+		{name: "rename over existing", changes: []FileChange{{Action: "rename", FromPath: "run.sh", Path: "README.md"}}, wantErr: "rename README.md: destination already exists"},
+		{name: "path required", changes: []FileChange{{Action: "create", Path: "/", Content: "x"}}, wantErr: "create: path required"},
+		{name: "unknown action", changes: []FileChange{{Action: "chmod", Path: "run.sh"}}, wantErr: `unknown action "chmod"`},
+		{name: "invalid base64", changes: []FileChange{{Action: "create", Path: "a.bin", Content: "***", Base64: true}}, wantErr: "create a.bin: invalid base64 content"},
+		{
+			name:    "same path twice",
+			changes: []FileChange{{Action: "update", Path: "README.md", Content: "x"}, {Action: "delete", Path: "/README.md"}},
+			wantErr: "README.md appears in more than one change",
+		},
+		{
+			name:    "rename source changed too",
+			changes: []FileChange{{Action: "rename", FromPath: "run.sh", Path: "bin/run.sh"}, {Action: "update", Path: "run.sh", Content: "x"}},
+			wantErr: "run.sh appears in more than one change",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			blobs = nil
+			mu.Unlock()
+
+			entries, files, err := changeEntries(client, context.Background(), "o", "r", testTree(client), tt.changes)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, entry := range entries {
+				if entry.GetType() != "blob" {
+					t.Errorf("%s: type = %q, want blob", entry.GetPath(), entry.GetType())
+				}
+				got = append(got, entry.GetPath()+" "+entry.GetMode()+" "+entry.GetSHA())
+			}
+			if !reflect.DeepEqual(got, tt.wantEntries) {
+				t.Errorf("entries = %q, want %q", got, tt.wantEntries)
+			}
+			if !reflect.DeepEqual(files, tt.wantFiles) {
+				t.Errorf("files = %+v, want %+v", files, tt.wantFiles)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if !reflect.DeepEqual(blobs, tt.wantBlobs) {
+				t.Errorf("blobs created = %q, want %q", blobs, tt.wantBlobs)
+			}
+		})
+	}
+}
+
+// contentsServer simula la Contents API con un archivo pequeño, uno de más de 1 MB
+// (encoding none) y otro que la API rechaza por tamaño, y la Blobs API en crudo.
+// Guarda cada petición con su ref.
+type contentsServer struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (s *contentsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.Path+"@"+r.URL.Query().Get("ref"))
+	s.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/api/v3/repos/o/r/contents/small.txt":
+		w.Write([]byte(`{"type":"file","path":"small.txt","sha":"small","size":5,"encoding":"base64","content":"aGVs\nbG8="}`))
+	case "/api/v3/repos/o/r/contents/docs/big.bin":
+		w.Write([]byte(`{"type":"file","path":"docs/big.bin","sha":"big","size":2000000,"encoding":"none","content":""}`))
+	case "/api/v3/repos/o/r/contents/huge.txt":
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"This API returns blobs up to 1 MB in size. The requested blob is too large to fetch via the API, but you can use the Git Data API to request blobs up to 100 MB in size.","errors":[{"resource":"Blob","field":"data","code":"too_large"}]}`))
+	case "/api/v3/repos/o/r/contents/", "/api/v3/repos/o/r/contents":
+		w.Write([]byte(`[{"type":"file","name":"huge.txt","path":"huge.txt","sha":"huge","size":3000000},{"type":"file","name":"small.txt","path":"small.txt","sha":"small","size":5}]`))
+	case "/api/v3/repos/o/r/git/blobs/big":
+		if !strings.Contains(r.Header.Get("Accept"), "raw") {
+			http.Error(w, "want raw", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("\x00\x01binary"))
+	case "/api/v3/repos/o/r/git/blobs/huge":
+		w.Write([]byte("huge text"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestGetFile(t *testing.T) {
+	tests := []struct {
+		path         string
+		wantEncoding string
+		wantContent  string
+		wantSHA      string
+		wantRequests []string
+	}{
+		{
+			path:         "small.txt",
+			wantEncoding: "utf-8",
+			wantContent:  "hello",
+			wantSHA:      "small",
+			wantRequests: []string{"/api/v3/repos/o/r/contents/small.txt@main"},
+		},
+		{
+			path:         "docs/big.bin",
+			wantEncoding: "base64",
+			wantContent:  base64.StdEncoding.EncodeToString([]byte("\x00\x01binary")),
+			wantSHA:      "big",
+			wantRequests: []string{"/api/v3/repos/o/r/contents/docs/big.bin@main", "/api/v3/repos/o/r/git/blobs/big@"},
+		},
+		{
+			path:         "huge.txt",
+			wantEncoding: "utf-8",
+			wantContent:  "huge text",
+			wantSHA:      "huge",
+			wantRequests: []string{"/api/v3/repos/o/r/contents/huge.txt@main", "/api/v3/repos/o/r/contents/@main", "/api/v3/repos/o/r/git/blobs/huge@"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			server := &contentsServer{}
+			file, err := GetFile(newTestClient(t, server), context.Background(), "o", "r", tt.path, "main")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if file.Encoding != tt.wantEncoding || file.Content != tt.wantContent || file.SHA != tt.wantSHA || file.Path != tt.path {
+				t.Errorf("file = %+v", file)
+			}
+			if !reflect.DeepEqual(server.requests, tt.wantRequests) {
+				t.Errorf("requests = %q, want %q", server.requests, tt.wantRequests)
+			}
+		})
+	}
+
+	// Un 403 que no es por tamaño no se intenta leer por otra vía
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"Resource not accessible by integration"}`))
+	}))
+	if _, err := GetFile(client, context.Background(), "o", "r", "huge.txt", ""); err == nil || isTooLarge(err) {
+		t.Errorf("forbidden: err = %v", err)
+	}
+}
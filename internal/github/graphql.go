@@ -0,0 +1,67 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// GraphQLError son los errores devueltos por la API GraphQL en una respuesta 200
+type GraphQLError struct {
+	Errors []GraphQLErrorItem
+}
+
+// GraphQLErrorItem es uno de los errores de una respuesta GraphQL
+type GraphQLErrorItem struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+func (e *GraphQLError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, item := range e.Errors {
+		messages = append(messages, item.Message)
+	}
+	return "GraphQL: " + strings.Join(messages, "; ")
+}
+
+// graphQL ejecuta una consulta o mutación GraphQL con el cliente REST (misma autenticación,
+// CA y proxy). owner selecciona el token en perfiles de GitHub App.
+func graphQL(client *github.Client, ctx context.Context, owner, query string, variables map[string]interface{}, out interface{}) error {
+	req, err := client.NewRequest("POST", graphQLURL(client), map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return err
+	}
+
+	var resp struct {
+		Data   json.RawMessage    `json:"data"`
+		Errors []GraphQLErrorItem `json:"errors"`
+	}
+	if _, err := client.Do(WithOwner(ctx, owner), req, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		return &GraphQLError{Errors: resp.Errors}
+	}
+	if out == nil || len(resp.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(resp.Data, out)
+}
+
+// graphQLURL deriva el endpoint GraphQL de la URL de la API REST:
+// https://api.github.com/graphql o https://host/api/graphql en GitHub Enterprise Server
+func graphQLURL(client *github.Client) string {
+	base := *client.BaseURL
+	if strings.HasSuffix(base.Path, "/api/v3/") {
+		base.Path = strings.TrimSuffix(base.Path, "v3/") + "graphql"
+		return base.String()
+	}
+	return base.ResolveReference(&url.URL{Path: "graphql"}).String()
+}
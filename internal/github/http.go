@@ -0,0 +1,131 @@
+package github
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// ClientOptions configura la conexión con GitHub. Vacío equivale a api.github.com
+// con la configuración de red del sistema.
+type ClientOptions struct {
+	BaseURL   string // URL de la API, p. ej. https://github.empresa.com/api/v3/
+	UploadURL string // URL de subidas; por defecto se deriva de BaseURL (/api/uploads/)
+	CABundle  string // archivo PEM con certificados de CA adicionales
+	Proxy     string // URL del proxy; por defecto HTTPS_PROXY/HTTP_PROXY/NO_PROXY
+}
+
+// NewClient crea el cliente de la GitHub API. Cada petición se autentica con el
+// token de source (token personal o GitHub App, ver auth.go), se reintenta ante los
+// límites de la API y los errores transitorios (ver ratelimit.go) y las lecturas se
+// revalidan con ETag (ver cache.go).
+func NewClient(source TokenSource, opts ClientOptions) (*github.Client, error) {
+	transport, err := newTransport(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// La caché va debajo de la autenticación para distinguir las respuestas por credencial
+	cache := newETagTransport(transport)
+	auth := &authTransport{base: cache, source: source}
+	client, err := newAPIClient(&retryTransport{base: auth}, opts)
+	if err != nil {
+		return nil, err
+	}
+	auth.hosts = apiHosts(client)
+	cache.hosts = auth.hosts
+	return client, nil
+}
+
+// apiHosts son los hosts de la API de un cliente, los únicos que reciben credenciales
+func apiHosts(client *github.Client) map[string]bool {
+	return map[string]bool{
+		strings.ToLower(client.BaseURL.Host):   true,
+		strings.ToLower(client.UploadURL.Host): true,
+	}
+}
+
+// newAPIClient crea el cliente go-github sobre un transporte ya autenticado
+func newAPIClient(transport http.RoundTripper, opts ClientOptions) (*github.Client, error) {
+	client := github.NewClient(&http.Client{Transport: transport})
+	if opts.BaseURL == "" {
+		return client, nil
+	}
+
+	uploadURL := opts.UploadURL
+	if uploadURL == "" {
+		uploadURL = defaultUploadURL(opts.BaseURL)
+	}
+	client, err := client.WithEnterpriseURLs(opts.BaseURL, uploadURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub Enterprise URL: %v", err)
+	}
+	return client, nil
+}
+
+// Host devuelve el host web de GitHub para unas opciones (el de los remotos Git)
+func (o ClientOptions) Host() string {
+	if o.BaseURL == "" {
+		return "github.com"
+	}
+	u, err := url.Parse(o.BaseURL)
+	if err != nil || u.Hostname() == "" {
+		return "github.com"
+	}
+	// En GitHub Enterprise Cloud con residencia de datos la API está en api.<host>
+	return strings.TrimPrefix(u.Hostname(), "api.")
+}
+
+// newTransport construye el transporte HTTP con la CA y el proxy configurados
+func newTransport(opts ClientOptions) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.Proxy != "" {
+		proxyURL, err := url.Parse(opts.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", opts.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if opts.CABundle != "" {
+		pool, err := LoadCABundle(opts.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return transport, nil
+}
+
+// LoadCABundle añade los certificados PEM del archivo a los del sistema
+func LoadCABundle(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA bundle: %v", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificates found in CA bundle %s", file)
+	}
+	return pool, nil
+}
+
+// defaultUploadURL deriva la URL de subidas de GitHub Enterprise a partir de la URL de la API
+func defaultUploadURL(baseURL string) string {
+	if i := strings.Index(baseURL, "/api/v3"); i >= 0 {
+		return baseURL[:i] + "/api/uploads/"
+	}
+	return baseURL
+}
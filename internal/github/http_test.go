@@ -0,0 +1,219 @@
+package github
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// newTestClient crea un cliente de la API contra un servidor de pruebas
+func newTestClient(t *testing.T, handler http.Handler) *github.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := NewClient(StaticToken("t"), ClientOptions{BaseURL: server.URL + "/api/v3/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestEnterpriseURLs(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       ClientOptions
+		wantBase   string
+		wantUpload string
+		wantHost   string
+	}{
+		{name: "github.com", opts: ClientOptions{}, wantBase: "https://api.github.com/", wantUpload: "https://uploads.github.com/", wantHost: "github.com"},
+		{name: "enterprise server", opts: ClientOptions{BaseURL: "https://ghe.example.com/api/v3/"}, wantBase: "https://ghe.example.com/api/v3/", wantUpload: "https://ghe.example.com/api/uploads/", wantHost: "ghe.example.com"},
+		{name: "no trailing slash", opts: ClientOptions{BaseURL: "https://ghe.example.com/api/v3"}, wantBase: "https://ghe.example.com/api/v3/", wantUpload: "https://ghe.example.com/api/uploads/", wantHost: "ghe.example.com"},
+		{name: "explicit upload URL", opts: ClientOptions{BaseURL: "https://ghe.example.com/api/v3/", UploadURL: "https://uploads.ghe.example.com/api/uploads/"}, wantBase: "https://ghe.example.com/api/v3/", wantUpload: "https://uploads.ghe.example.com/api/uploads/", wantHost: "ghe.example.com"},
+		{name: "data residency", opts: ClientOptions{BaseURL: "https://api.acme.ghe.com/"}, wantBase: "https://api.acme.ghe.com/", wantHost: "acme.ghe.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(StaticToken("t"), tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := client.BaseURL.String(); got != tt.wantBase {
+				t.Errorf("BaseURL = %s, want %s", got, tt.wantBase)
+			}
+			if got := client.UploadURL.String(); tt.wantUpload != "" && got != tt.wantUpload {
+				t.Errorf("UploadURL = %s, want %s", got, tt.wantUpload)
+			}
+			if got := tt.opts.Host(); got != tt.wantHost {
+				t.Errorf("Host() = %s, want %s", got, tt.wantHost)
+			}
+		})
+	}
+}
+
+// headerRecorder guarda la cabecera Authorization de cada ruta recibida
+type headerRecorder struct {
+	mu   sync.Mutex
+	auth map[string]string
+}
+
+func (h *headerRecorder) record(r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.auth == nil {
+		h.auth = map[string]string{}
+	}
+	h.auth[r.URL.Path] = r.Header.Get("Authorization")
+}
+
+func (h *headerRecorder) get(path string) (string, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	value, ok := h.auth[path]
+	return value, ok
+}
+
+func TestTokenOnlySentToAPIHosts(t *testing.T) {
+	var seen headerRecorder
+
+	download := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen.record(r)
+		w.Write([]byte("log contents"))
+	}))
+	defer download.Close()
+
+	uploads := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen.record(r)
+		w.Write([]byte(`{}`))
+	}))
+	defer uploads.Close()
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen.record(r)
+		if strings.HasSuffix(r.URL.Path, "/logs") {
+			http.Redirect(w, r, download.URL+"/signed/logs.zip", http.StatusFound)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+
+	client, err := NewClient(StaticToken("secret"), ClientOptions{BaseURL: api.URL + "/api/v3/", UploadURL: uploads.URL + "/api/uploads/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for _, url := range []string{"repos/o/r/actions/runs/1/logs", client.UploadURL.String() + "repos/o/r/releases/1/assets"} {
+		req, err := client.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.BareDo(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	for path, want := range map[string]string{
+		"/api/v3/repos/o/r/actions/runs/1/logs":    "Bearer secret",
+		"/api/uploads/repos/o/r/releases/1/assets": "Bearer secret",
+		"/signed/logs.zip":                         "",
+	} {
+		got, ok := seen.get(path)
+		if !ok {
+			t.Errorf("%s: not requested", path)
+		} else if got != want {
+			t.Errorf("%s: Authorization = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestCABundle(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"full_name":"o/r"}`))
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	opts := ClientOptions{BaseURL: server.URL + "/api/v3/"}
+	client, err := NewClient(StaticToken("t"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Sin la CA el error de certificado se reintentaría: basta con el primer intento
+	short, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	if _, _, err := client.Repositories.Get(short, "o", "r"); err == nil {
+		t.Fatal("request without the CA bundle succeeded")
+	}
+
+	opts.CABundle = bundle
+	client, err = NewClient(StaticToken("t"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, _, err := client.Repositories.Get(ctx, "o", "r")
+	if err != nil {
+		t.Fatalf("request with the CA bundle: %v", err)
+	}
+	if repo.GetFullName() != "o/r" {
+		t.Errorf("full name = %q", repo.GetFullName())
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	os.WriteFile(empty, []byte("not a certificate"), 0600)
+	if _, err := NewClient(StaticToken("t"), ClientOptions{CABundle: empty}); err == nil {
+		t.Error("bundle without certificates accepted")
+	}
+}
+
+func TestProxy(t *testing.T) {
+	var proxied []string
+	var mu sync.Mutex
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		proxied = append(proxied, r.URL.String()+" "+r.Header.Get("Authorization"))
+		mu.Unlock()
+		w.Write([]byte(`{"full_name":"o/r"}`))
+	}))
+	defer proxy.Close()
+
+	client, err := NewClient(StaticToken("t"), ClientOptions{BaseURL: "http://ghe.invalid/api/v3/", Proxy: proxy.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.Repositories.Get(context.Background(), "o", "r"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "http://ghe.invalid/api/v3/repos/o/r Bearer t"
+	if len(proxied) != 1 || proxied[0] != want {
+		t.Errorf("proxied = %q, want [%q]", proxied, want)
+	}
+
+	if _, err := NewClient(StaticToken("t"), ClientOptions{Proxy: "://bad"}); err == nil {
+		t.Error("invalid proxy URL accepted")
+	}
+}
@@ -0,0 +1,235 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// IssueFilter son los filtros de ListIssues. Los campos vacíos no filtran.
+type IssueFilter struct {
+	State     string   // open, closed o all (default: open)
+	Labels    []string // issues con todas estas etiquetas
+	Assignee  string   // login, "none" o "*"
+	Milestone string   // número, "none" o "*"
+	Since     string   // RFC 3339: solo issues actualizados desde entonces
+	PageOptions
+}
+
+// IssueChanges son los campos de un issue a crear o modificar. Los punteros nil no se
+// envían; un slice vacío (no nil) borra todas las etiquetas o asignados, y el milestone 0 lo quita.
+type IssueChanges struct {
+	Title       *string
+	Body        *string
+	State       *string // open o closed
+	StateReason *string // completed, not_planned o reopened
+	Labels      *[]string
+	Assignees   *[]string
+	Milestone   *int
+}
+
+// ListIssues lista los issues de un repositorio. Los pull requests, que la API
+// devuelve junto a los issues, se omiten: tienen su propia herramienta.
+func ListIssues(client *github.Client, ctx context.Context, owner, repoName string, filter IssueFilter) (*IssueList, error) {
+	opt := &github.IssueListByRepoOptions{
+		State:     filter.State,
+		Labels:    filter.Labels,
+		Assignee:  filter.Assignee,
+		Milestone: filter.Milestone,
+	}
+	if opt.State == "" {
+		opt.State = "open"
+	}
+	if filter.Since != "" {
+		since, err := time.Parse(time.RFC3339, filter.Since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid since %q: expected RFC 3339 (e.g. 2024-01-31T00:00:00Z)", filter.Since)
+		}
+		opt.Since = since
+	}
+
+	issues, next, err := paginate(filter.PageOptions, func(page github.ListOptions) ([]IssueSummary, *github.Response, error) {
+		opt.ListOptions = page
+		issues, resp, err := client.Issues.ListByRepo(ctx, owner, repoName, opt)
+		if err != nil {
+			return nil, nil, err
+		}
+		summaries := make([]IssueSummary, 0, len(issues))
+		for _, issue := range issues {
+			if issue.IsPullRequest() {
+				continue
+			}
+			summaries = append(summaries, issueSummary(issue))
+		}
+		return summaries, resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &IssueList{Issues: issues, NextCursor: next}, nil
+}
+
+// GetIssue obtiene un issue y, si se pide, sus comentarios (todas las páginas)
+func GetIssue(client *github.Client, ctx context.Context, owner, repoName string, number int, withComments bool) (*IssueDetail, error) {
+	issue, _, err := client.Issues.Get(ctx, owner, repoName, number)
+	if err != nil {
+		return nil, err
+	}
+
+	result := issueDetail(issue)
+	if !withComments || issue.GetComments() == 0 {
+		return result, nil
+	}
+
+	opt := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := client.Issues.ListComments(ctx, owner, repoName, number, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, comment := range comments {
+			result.CommentList = append(result.CommentList, issueComment(comment))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return result, nil
+}
+
+// CreateIssue crea un issue
+func CreateIssue(client *github.Client, ctx context.Context, owner, repoName string, fields IssueChanges) (*IssueDetail, error) {
+	issue, _, err := client.Issues.Create(ctx, owner, repoName, fields.request())
+	if err != nil {
+		return nil, err
+	}
+	return issueDetail(issue), nil
+}
+
+// UpdateIssue modifica un issue (título, cuerpo, estado, etiquetas, asignados o milestone)
+func UpdateIssue(client *github.Client, ctx context.Context, owner, repoName string, number int, changes IssueChanges) (*IssueDetail, error) {
+	// Issues.Edit omite el milestone nil: la petición se construye aquí para poder enviar null
+	req, err := client.NewRequest(http.MethodPatch, fmt.Sprintf("repos/%v/%v/issues/%d", owner, repoName, number), changes.edit())
+	if err != nil {
+		return nil, err
+	}
+	issue := new(github.Issue)
+	if _, err := client.Do(ctx, req, issue); err != nil {
+		return nil, err
+	}
+	return issueDetail(issue), nil
+}
+
+// CommentIssue añade un comentario a un issue (o pull request)
+func CommentIssue(client *github.Client, ctx context.Context, owner, repoName string, number int, body string) (*IssueCommentCreated, error) {
+	comment, _, err := client.Issues.CreateComment(ctx, owner, repoName, number, &github.IssueComment{Body: github.String(body)})
+	if err != nil {
+		return nil, err
+	}
+	return &IssueCommentCreated{Number: number, ID: comment.GetID(), URL: comment.GetHTMLURL()}, nil
+}
+
+// LockIssue bloquea o desbloquea la conversación de un issue
+func LockIssue(client *github.Client, ctx context.Context, owner, repoName string, number int, lock bool, reason string) (*IssueLock, error) {
+	var err error
+	if lock {
+		_, err = client.Issues.Lock(ctx, owner, repoName, number, &github.LockIssueOptions{LockReason: reason})
+	} else {
+		reason = ""
+		_, err = client.Issues.Unlock(ctx, owner, repoName, number)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &IssueLock{Number: number, Locked: lock, Reason: reason}, nil
+}
+
+func (c IssueChanges) request() *github.IssueRequest {
+	request := &github.IssueRequest{
+		Title:       c.Title,
+		Body:        c.Body,
+		State:       c.State,
+		StateReason: c.StateReason,
+		Labels:      c.Labels,
+		Assignees:   c.Assignees,
+	}
+	if c.Milestone != nil && *c.Milestone != 0 {
+		request.Milestone = c.Milestone
+	}
+	return request
+}
+
+// issueEdit es el cuerpo de la edición de un issue: su milestone oculta el de
+// IssueRequest y admite null, que quita el milestone actual
+type issueEdit struct {
+	*github.IssueRequest
+	Milestone json.RawMessage `json:"milestone,omitempty"`
+}
+
+func (c IssueChanges) edit() issueEdit {
+	edit := issueEdit{IssueRequest: c.request()}
+	switch {
+	case c.Milestone == nil:
+	case *c.Milestone == 0:
+		edit.Milestone = json.RawMessage("null")
+	default:
+		edit.Milestone = json.RawMessage(strconv.Itoa(*c.Milestone))
+	}
+	return edit
+}
+
+func issueSummary(issue *github.Issue) IssueSummary {
+	summary := IssueSummary{
+		Number:    issue.GetNumber(),
+		Title:     issue.GetTitle(),
+		State:     issue.GetState(),
+		URL:       issue.GetHTMLURL(),
+		User:      issue.GetUser().GetLogin(),
+		Labels:    []string{},
+		Assignees: []string{},
+		Milestone: issue.GetMilestone().GetTitle(),
+		Comments:  issue.GetComments(),
+		CreatedAt: issue.GetCreatedAt().Format(time.RFC3339),
+		UpdatedAt: issue.GetUpdatedAt().Format(time.RFC3339),
+	}
+	for _, label := range issue.Labels {
+		summary.Labels = append(summary.Labels, label.GetName())
+	}
+	for _, assignee := range issue.Assignees {
+		summary.Assignees = append(summary.Assignees, assignee.GetLogin())
+	}
+	return summary
+}
+
+func issueDetail(issue *github.Issue) *IssueDetail {
+	detail := &IssueDetail{
+		IssueSummary: issueSummary(issue),
+		Body:         issue.GetBody(),
+		StateReason:  issue.GetStateReason(),
+		Locked:       issue.GetLocked(),
+		LockReason:   issue.GetActiveLockReason(),
+		CommentList:  []IssueComment{},
+	}
+	if issue.ClosedAt != nil {
+		detail.ClosedAt = issue.GetClosedAt().Format(time.RFC3339)
+	}
+	return detail
+}
+
+func issueComment(comment *github.IssueComment) IssueComment {
+	return IssueComment{
+		ID:        comment.GetID(),
+		User:      comment.GetUser().GetLogin(),
+		Body:      comment.GetBody(),
+		URL:       comment.GetHTMLURL(),
+		CreatedAt: comment.GetCreatedAt().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,171 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// issueServer responde a las peticiones de issues y guarda la última recibida
+type issueServer struct {
+	mu    sync.Mutex
+	query url.Values
+	body  map[string]json.RawMessage
+}
+
+func (s *issueServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	raw, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.query = r.URL.Query()
+	s.body = nil
+	if len(raw) > 0 {
+		json.Unmarshal(raw, &s.body)
+	}
+	s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/o/r/issues":
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`[{"number":3,"title":"last","state":"open"}]`))
+			return
+		}
+		w.Header().Set("Link", `<`+r.URL.Path+`?page=2>; rel="next"`)
+		w.Write([]byte(`[
+			{"number":1,"title":"bug","state":"open","labels":[{"name":"bug"}],"assignees":[{"login":"ana"}],"milestone":{"title":"v1"}},
+			{"number":2,"title":"a pull request","state":"open","pull_request":{"url":"x"}}
+		]`))
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/o/r/issues":
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"number":7,"title":"new","state":"open"}`))
+	case r.Method == http.MethodPatch && r.URL.Path == "/api/v3/repos/o/r/issues/7":
+		w.Write([]byte(`{"number":7,"title":"new","state":"closed","state_reason":"completed"}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// sent devuelve el cuerpo JSON de la última petición, con cada campo como texto JSON
+func (s *issueServer) sent() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fields := map[string]string{}
+	for name, value := range s.body {
+		fields[name] = string(value)
+	}
+	return fields
+}
+
+func TestCreateIssue(t *testing.T) {
+	server := &issueServer{}
+	client := newTestClient(t, server)
+	title, labels := "new", []string{"bug"}
+
+	tests := []struct {
+		name   string
+		fields IssueChanges
+		want   map[string]string
+	}{
+		{name: "title only", fields: IssueChanges{Title: &title}, want: map[string]string{"title": `"new"`}},
+		{name: "labels and milestone", fields: IssueChanges{Title: &title, Labels: &labels, Milestone: intPtr(3)}, want: map[string]string{"title": `"new"`, "labels": `["bug"]`, "milestone": `3`}},
+		{name: "milestone 0 not sent", fields: IssueChanges{Title: &title, Milestone: intPtr(0)}, want: map[string]string{"title": `"new"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue, err := CreateIssue(client, context.Background(), "o", "r", tt.fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if issue.Number != 7 {
+				t.Errorf("number = %d, want 7", issue.Number)
+			}
+			if got := server.sent(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("body = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateIssue(t *testing.T) {
+	server := &issueServer{}
+	client := newTestClient(t, server)
+	closed, reason, none := "closed", "completed", []string{}
+
+	tests := []struct {
+		name    string
+		changes IssueChanges
+		want    map[string]string
+	}{
+		{name: "state", changes: IssueChanges{State: &closed, StateReason: &reason}, want: map[string]string{"state": `"closed"`, "state_reason": `"completed"`}},
+		{name: "set milestone", changes: IssueChanges{Milestone: intPtr(5)}, want: map[string]string{"milestone": `5`}},
+		{name: "clear milestone", changes: IssueChanges{Milestone: intPtr(0)}, want: map[string]string{"milestone": `null`}},
+		{name: "clear labels and assignees", changes: IssueChanges{Labels: &none, Assignees: &none}, want: map[string]string{"labels": `[]`, "assignees": `[]`}},
+		{name: "nothing", changes: IssueChanges{}, want: map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue, err := UpdateIssue(client, context.Background(), "o", "r", 7, tt.changes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if issue.Number != 7 || issue.State != "closed" || issue.StateReason != "completed" {
+				t.Errorf("issue = %+v", issue)
+			}
+			if got := server.sent(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("body = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := UpdateIssue(client, context.Background(), "o", "r", 8, IssueChanges{}); err == nil {
+		t.Error("missing issue: no error")
+	}
+}
+
+func TestListIssues(t *testing.T) {
+	server := &issueServer{}
+	client := newTestClient(t, server)
+
+	list, err := ListIssues(client, context.Background(), "o", "r", IssueFilter{
+		Labels:    []string{"bug", "ui"},
+		Assignee:  "none",
+		Milestone: "*",
+		Since:     "2024-01-31T00:00:00Z",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server.mu.Lock()
+	query := server.query
+	server.mu.Unlock()
+	for name, want := range map[string]string{"state": "open", "labels": "bug,ui", "assignee": "none", "milestone": "*", "since": "2024-01-31T00:00:00Z"} {
+		if got := query.Get(name); got != want {
+			t.Errorf("query %s = %q, want %q", name, got, want)
+		}
+	}
+
+	// Los pull requests se omiten y las páginas se recorren hasta completar la petición
+	var numbers []int
+	for _, issue := range list.Issues {
+		numbers = append(numbers, issue.Number)
+	}
+	if !reflect.DeepEqual(numbers, []int{1, 3}) {
+		t.Errorf("issues = %v, want [1 3]", numbers)
+	}
+	first := list.Issues[0]
+	if !reflect.DeepEqual(first.Labels, []string{"bug"}) || !reflect.DeepEqual(first.Assignees, []string{"ana"}) || first.Milestone != "v1" {
+		t.Errorf("first issue = %+v", first)
+	}
+
+	if _, err := ListIssues(client, context.Background(), "o", "r", IssueFilter{Since: "yesterday"}); err == nil || !strings.Contains(err.Error(), "RFC 3339") {
+		t.Errorf("invalid since: err = %v", err)
+	}
+}
+
+func intPtr(n int) *int { return &n }
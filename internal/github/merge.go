@@ -0,0 +1,260 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// Motivos por los que no se puede fusionar o actualizar un pull request. Se devuelven
+// en el campo Reason de los resultados en lugar del error de la API.
+const (
+	ReasonAlreadyMerged     = "already_merged"
+	ReasonClosed            = "closed"
+	ReasonDraft             = "draft"
+	ReasonConflicts         = "conflicts"
+	ReasonBehind            = "behind_base"
+	ReasonChecksPending     = "checks_pending"
+	ReasonChecksFailing     = "checks_failing"
+	ReasonReviewRequired    = "review_required"
+	ReasonBlocked           = "blocked"
+	ReasonHeadModified      = "head_modified"
+	ReasonMethodNotAllowed  = "method_not_allowed"
+	ReasonNotMergeable      = "not_mergeable"
+	ReasonUpToDate          = "already_up_to_date"
+	ReasonAutoMergeDisabled = "auto_merge_not_allowed"
+	ReasonCleanStatus       = "clean_status"
+	ReasonNoProtection      = "no_branch_protection"
+	ReasonForbidden         = "forbidden"
+	ReasonNotFound          = "not_found"
+	ReasonRejected          = "rejected"
+)
+
+// MergeOptions configura la fusión de un pull request
+type MergeOptions struct {
+	Method        string // merge (default), squash o rebase
+	CommitTitle   string
+	CommitMessage string
+	SHA           string // la fusión falla si el head del PR ya no es este commit
+}
+
+// MergePullRequest fusiona un pull request. Los rechazos esperables (conflictos, checks
+// pendientes, revisiones, head modificado...) se devuelven en MergeResult.Reason.
+func MergePullRequest(client *github.Client, ctx context.Context, owner, repoName string, number int, opts MergeOptions) (*MergeResult, error) {
+	if opts.Method == "" {
+		opts.Method = "merge"
+	}
+	result := &MergeResult{Number: number, Method: opts.Method}
+
+	pr, _, err := client.PullRequests.Get(ctx, owner, repoName, number)
+	if err != nil {
+		return nil, err
+	}
+	if reason := stateReason(pr); reason != "" {
+		return result.fail(reason, "pull request is "+strings.ReplaceAll(reason, "_", " ")), nil
+	}
+
+	merged, _, err := client.PullRequests.Merge(ctx, owner, repoName, number, opts.CommitMessage, &github.PullRequestOptions{
+		CommitTitle: opts.CommitTitle,
+		SHA:         opts.SHA,
+		MergeMethod: opts.Method,
+	})
+	if err == nil {
+		result.Merged = merged.GetMerged()
+		result.SHA = merged.GetSHA()
+		result.Message = merged.GetMessage()
+		return result, nil
+	}
+
+	var apiErr *github.ErrorResponse
+	if !errors.As(err, &apiErr) {
+		return nil, err
+	}
+
+	result.MergeableState = pr.GetMergeableState()
+	message := strings.ToLower(apiErr.Message)
+	switch {
+	case statusCode(apiErr) == http.StatusConflict || strings.Contains(message, "head branch was modified"):
+		return result.fail(ReasonHeadModified, apiErr.Message), nil
+	case strings.Contains(message, "merges are not allowed") || strings.Contains(message, "merge method"):
+		return result.fail(ReasonMethodNotAllowed, apiErr.Message), nil
+	case strings.Contains(message, "review"):
+		return result.fail(ReasonReviewRequired, apiErr.Message), nil
+	}
+	if reason := statusReason(apiErr); reason != "" {
+		return result.fail(reason, apiErr.Message), nil
+	}
+
+	// "Pull Request is not mergeable" y similares: el motivo real está en mergeable_state y los checks
+	checks, checksErr := GetChecksSummary(client, ctx, owner, repoName, pr.GetHead().GetSHA())
+	if checksErr == nil {
+		result.Checks = checks
+	}
+	switch {
+	case pr.GetMergeableState() == "dirty":
+		return result.fail(ReasonConflicts, apiErr.Message), nil
+	case pr.GetMergeableState() == "behind":
+		return result.fail(ReasonBehind, apiErr.Message), nil
+	case checks != nil && checks.State == "failure":
+		return result.fail(ReasonChecksFailing, apiErr.Message), nil
+	case (checks != nil && checks.State == "pending") || strings.Contains(message, "status check"):
+		return result.fail(ReasonChecksPending, apiErr.Message), nil
+	case pr.GetMergeableState() == "blocked":
+		return result.fail(ReasonBlocked, apiErr.Message), nil
+	}
+	return result.fail(ReasonNotMergeable, apiErr.Message), nil
+}
+
+// UpdatePullRequestBranch incorpora al PR los cambios nuevos de su rama base
+func UpdatePullRequestBranch(client *github.Client, ctx context.Context, owner, repoName string, number int, expectedHeadSHA string) (*BranchUpdateResult, error) {
+	result := &BranchUpdateResult{Number: number}
+
+	opts := &github.PullRequestBranchUpdateOptions{}
+	if expectedHeadSHA != "" {
+		opts.ExpectedHeadSHA = github.String(expectedHeadSHA)
+	}
+
+	update, _, err := client.PullRequests.UpdateBranch(ctx, owner, repoName, number, opts)
+	var accepted *github.AcceptedError
+	switch {
+	case err == nil:
+		result.Updated = true
+		result.Message = update.GetMessage()
+		return result, nil
+	case errors.As(err, &accepted):
+		// La API responde 202: la actualización se hace en segundo plano
+		result.Updated = true
+		result.Message = "Updating pull request branch."
+		return result, nil
+	}
+
+	var apiErr *github.ErrorResponse
+	if !errors.As(err, &apiErr) {
+		return nil, err
+	}
+
+	message := strings.ToLower(apiErr.Message)
+	switch {
+	case strings.Contains(message, "expected_head_sha") || strings.Contains(message, "head sha"):
+		return result.fail(ReasonHeadModified, apiErr.Message), nil
+	case strings.Contains(message, "conflict"):
+		return result.fail(ReasonConflicts, apiErr.Message), nil
+	case strings.Contains(message, "no new commits"):
+		return result.fail(ReasonUpToDate, apiErr.Message), nil
+	}
+	if reason := statusReason(apiErr); reason != "" {
+		return result.fail(reason, apiErr.Message), nil
+	}
+	return result.fail(ReasonRejected, apiErr.Message), nil
+}
+
+// EnableAutoMerge activa la fusión automática (GraphQL): el PR se fusionará en cuanto
+// se cumplan las reglas de protección de la rama base
+func EnableAutoMerge(client *github.Client, ctx context.Context, owner, repoName string, number int, opts MergeOptions) (*AutoMergeResult, error) {
+	if opts.Method == "" {
+		opts.Method = "merge"
+	}
+	result := &AutoMergeResult{Number: number, Method: opts.Method}
+
+	pr, _, err := client.PullRequests.Get(ctx, owner, repoName, number)
+	if err != nil {
+		return nil, err
+	}
+	if reason := stateReason(pr); reason != "" && reason != ReasonDraft {
+		return result.fail(reason, "pull request is "+strings.ReplaceAll(reason, "_", " ")), nil
+	}
+
+	input := map[string]interface{}{
+		"pullRequestId": pr.GetNodeID(),
+		"mergeMethod":   strings.ToUpper(opts.Method),
+	}
+	if opts.CommitTitle != "" {
+		input["commitHeadline"] = opts.CommitTitle
+	}
+	if opts.CommitMessage != "" {
+		input["commitBody"] = opts.CommitMessage
+	}
+	if opts.SHA != "" {
+		input["expectedHeadOid"] = opts.SHA
+	}
+
+	const mutation = `mutation($input: EnablePullRequestAutoMergeInput!) {
+  enablePullRequestAutoMerge(input: $input) {
+    pullRequest { autoMergeRequest { enabledAt } }
+  }
+}`
+	err = graphQL(client, ctx, owner, mutation, map[string]interface{}{"input": input}, nil)
+	if err == nil {
+		result.Enabled = true
+		return result, nil
+	}
+
+	var gqlErr *GraphQLError
+	if !errors.As(err, &gqlErr) {
+		var apiErr *github.ErrorResponse
+		if errors.As(err, &apiErr) {
+			if reason := statusReason(apiErr); reason != "" {
+				return result.fail(reason, apiErr.Message), nil
+			}
+		}
+		return nil, err
+	}
+
+	message := strings.ToLower(gqlErr.Error())
+	switch {
+	case strings.Contains(message, "clean status"):
+		return result.fail(ReasonCleanStatus, gqlErr.Error()), nil
+	case strings.Contains(message, "protected branch rules not configured"):
+		return result.fail(ReasonNoProtection, gqlErr.Error()), nil
+	case strings.Contains(message, "auto merge is not allowed") || strings.Contains(message, "auto-merge is not allowed"):
+		return result.fail(ReasonAutoMergeDisabled, gqlErr.Error()), nil
+	case strings.Contains(message, "head"):
+		return result.fail(ReasonHeadModified, gqlErr.Error()), nil
+	case strings.Contains(message, "not allowed"):
+		return result.fail(ReasonMethodNotAllowed, gqlErr.Error()), nil
+	}
+	return result.fail(ReasonRejected, gqlErr.Error()), nil
+}
+
+// SetPullRequestState cierra (closed) o reabre (open) un pull request
+func SetPullRequestState(client *github.Client, ctx context.Context, owner, repoName string, number int, state string) (*PullRequestState, error) {
+	pr, _, err := client.PullRequests.Edit(ctx, owner, repoName, number, &github.PullRequest{State: github.String(state)})
+	if err != nil {
+		return nil, err
+	}
+	return &PullRequestState{Number: pr.GetNumber(), State: pr.GetState(), Merged: pr.GetMerged(), URL: pr.GetHTMLURL()}, nil
+}
+
+// stateReason indica por qué un PR no admite fusión según su estado ("" si está abierto y listo)
+func stateReason(pr *github.PullRequest) string {
+	switch {
+	case pr.GetMerged():
+		return ReasonAlreadyMerged
+	case pr.GetState() == "closed":
+		return ReasonClosed
+	case pr.GetDraft():
+		return ReasonDraft
+	}
+	return ""
+}
+
+// statusReason traduce los códigos HTTP de permisos y recursos inexistentes
+func statusReason(apiErr *github.ErrorResponse) string {
+	switch statusCode(apiErr) {
+	case http.StatusForbidden:
+		return ReasonForbidden
+	case http.StatusNotFound:
+		return ReasonNotFound
+	}
+	return ""
+}
+
+func statusCode(apiErr *github.ErrorResponse) int {
+	if apiErr.Response == nil {
+		return 0
+	}
+	return apiErr.Response.StatusCode
+}
@@ -0,0 +1,94 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMergePullRequestReasons(t *testing.T) {
+	const open = `{"number":7,"state":"open","mergeable_state":"%s","head":{"sha":"abc"}}`
+
+	tests := []struct {
+		name         string
+		pr           string
+		mergeStatus  int
+		mergeMessage string
+		checkRun     string // status/conclusion del check run del head ("" = ninguno)
+		want         string
+	}{
+		{name: "merged", pr: fmt.Sprintf(open, "clean"), want: ""},
+		{name: "already merged", pr: `{"number":7,"state":"closed","merged":true}`, mergeStatus: 405, want: ReasonAlreadyMerged},
+		{name: "closed", pr: `{"number":7,"state":"closed"}`, mergeStatus: 405, want: ReasonClosed},
+		{name: "draft", pr: `{"number":7,"state":"open","draft":true}`, mergeStatus: 405, want: ReasonDraft},
+		{name: "head modified", pr: fmt.Sprintf(open, "clean"), mergeStatus: 409, mergeMessage: "Head branch was modified. Review and try the merge again.", want: ReasonHeadModified},
+		{name: "method not allowed", pr: fmt.Sprintf(open, "clean"), mergeStatus: 405, mergeMessage: "Squash merges are not allowed on this repository.", want: ReasonMethodNotAllowed},
+		{name: "review required", pr: fmt.Sprintf(open, "blocked"), mergeStatus: 405, mergeMessage: "At least 1 approving review is required by reviewers with write access.", want: ReasonReviewRequired},
+		{name: "forbidden", pr: fmt.Sprintf(open, "clean"), mergeStatus: 403, mergeMessage: "Resource not accessible by integration", want: ReasonForbidden},
+		{name: "conflicts", pr: fmt.Sprintf(open, "dirty"), mergeStatus: 405, mergeMessage: "Pull Request is not mergeable", want: ReasonConflicts},
+		{name: "behind", pr: fmt.Sprintf(open, "behind"), mergeStatus: 405, mergeMessage: "Pull Request is not mergeable", want: ReasonBehind},
+		{name: "checks failing", pr: fmt.Sprintf(open, "blocked"), mergeStatus: 405, mergeMessage: "Pull Request is not mergeable", checkRun: `"status":"completed","conclusion":"failure"`, want: ReasonChecksFailing},
+		{name: "checks pending", pr: fmt.Sprintf(open, "blocked"), mergeStatus: 405, mergeMessage: "Pull Request is not mergeable", checkRun: `"status":"in_progress"`, want: ReasonChecksPending},
+		{name: "required status check", pr: fmt.Sprintf(open, "blocked"), mergeStatus: 405, mergeMessage: "Required status check \"ci\" is expected.", want: ReasonChecksPending},
+		{name: "blocked", pr: fmt.Sprintf(open, "blocked"), mergeStatus: 405, mergeMessage: "Pull Request is not mergeable", checkRun: `"status":"completed","conclusion":"success"`, want: ReasonBlocked},
+		{name: "not mergeable", pr: fmt.Sprintf(open, "unknown"), mergeStatus: 405, mergeMessage: "Pull Request is not mergeable", want: ReasonNotMergeable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := false
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path := strings.TrimPrefix(r.URL.Path, "/api/v3/repos/o/r")
+				switch {
+				case r.Method == http.MethodGet && path == "/pulls/7":
+					w.Write([]byte(tt.pr))
+				case r.Method == http.MethodPut && path == "/pulls/7/merge":
+					if tt.mergeStatus != 0 {
+						w.WriteHeader(tt.mergeStatus)
+						fmt.Fprintf(w, `{"message":%q}`, tt.mergeMessage)
+						return
+					}
+					merged = true
+					w.Write([]byte(`{"merged":true,"sha":"def","message":"Pull Request successfully merged"}`))
+				case path == "/commits/abc/check-runs":
+					if tt.checkRun == "" {
+						w.Write([]byte(`{"total_count":0,"check_runs":[]}`))
+						return
+					}
+					fmt.Fprintf(w, `{"total_count":1,"check_runs":[{"name":"ci",%s}]}`, tt.checkRun)
+				case path == "/commits/abc/status":
+					w.Write([]byte(`{"state":"pending","statuses":[]}`))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+
+			result, err := MergePullRequest(client, context.Background(), "o", "r", 7, MergeOptions{Method: "squash"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Reason != tt.want {
+				t.Errorf("reason = %q (%s), want %q", result.Reason, result.Message, tt.want)
+			}
+			if result.Merged != (tt.want == "") || merged != (tt.want == "") {
+				t.Errorf("merged = %v (request sent: %v)", result.Merged, merged)
+			}
+			if result.Method != "squash" {
+				t.Errorf("method = %q", result.Method)
+			}
+		})
+	}
+}
+
+func TestMergePullRequestError(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`))
+	}))
+	// Si el PR no existe el error de la API se devuelve tal cual
+	if _, err := MergePullRequest(client, context.Background(), "o", "r", 7, MergeOptions{}); err == nil {
+		t.Error("missing pull request: want error")
+	}
+}
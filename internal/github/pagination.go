@@ -0,0 +1,110 @@
+package github
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// Valores por defecto de la paginación
+const (
+	defaultPerPage = 30
+	maxPerPage     = 100
+)
+
+// PageOptions controlan la paginación de los listados. Se piden páginas siguiendo la
+// cabecera Link hasta reunir MaxItems elementos; si quedan más, el resultado incluye un
+// cursor con el que continuar justo donde se cortó.
+type PageOptions struct {
+	Page     int    // primera página (default: 1)
+	PerPage  int    // tamaño de página de la API (default: 30, máximo 100)
+	MaxItems int    // máximo de elementos a devolver (default: una página)
+	Cursor   string // NextCursor de un listado anterior: sustituye a Page y PerPage
+}
+
+// pageCursor es la posición de continuación de un listado: página, tamaño de página y
+// elementos ya devueltos de esa página
+type pageCursor struct {
+	page, perPage, offset int
+}
+
+func (c pageCursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d:%d", c.page, c.perPage, c.offset)))
+}
+
+func decodeCursor(cursor string) (pageCursor, error) {
+	var c pageCursor
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		_, err = fmt.Sscanf(string(raw), "%d:%d:%d", &c.page, &c.perPage, &c.offset)
+	}
+	if err != nil || c.page < 1 || c.perPage < 1 || c.perPage > maxPerPage || c.offset < 0 {
+		return pageCursor{}, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	return c, nil
+}
+
+// paginate reúne los elementos de un listado página a página. fetch pide una página y
+// devuelve sus elementos ya convertidos (y filtrados, si el listado filtra). Devuelve el
+// cursor con el que continuar, o "" si no quedan más elementos.
+func paginate[T any](opts PageOptions, fetch func(opt github.ListOptions) ([]T, *github.Response, error)) ([]T, string, error) {
+	position := pageCursor{page: opts.Page, perPage: opts.PerPage}
+	if opts.Cursor != "" {
+		var err error
+		if position, err = decodeCursor(opts.Cursor); err != nil {
+			return nil, "", err
+		}
+	}
+	if position.page < 1 {
+		position.page = 1
+	}
+	if position.perPage < 1 {
+		position.perPage = defaultPerPage
+	}
+	position.perPage = min(position.perPage, maxPerPage)
+
+	limit := opts.MaxItems
+	if limit < 1 {
+		limit = position.perPage
+	}
+
+	items := []T{}
+	for {
+		page, resp, err := fetch(github.ListOptions{Page: position.page, PerPage: position.perPage})
+		if err != nil {
+			return nil, "", err
+		}
+		if position.offset < len(page) {
+			page = page[position.offset:]
+		} else {
+			page = nil
+		}
+
+		if room := limit - len(items); len(page) > room {
+			items = append(items, page[:room]...)
+			position.offset += room
+			return items, position.encode(), nil
+		}
+		items = append(items, page...)
+
+		if resp.NextPage == 0 {
+			return items, "", nil
+		}
+		position = pageCursor{page: resp.NextPage, perPage: position.perPage}
+		if len(items) == limit {
+			return items, position.encode(), nil
+		}
+	}
+}
+
+// listText es el texto de un listado: sus elementos en JSON y, si quedan más, el cursor
+// con el que pedirlos
+func listText(items any, nextCursor string) string {
+	output, _ := json.MarshalIndent(items, "", "  ")
+	if nextCursor == "" {
+		return string(output)
+	}
+	return fmt.Sprintf("%s\nnextCursor: %s", output, nextCursor)
+}
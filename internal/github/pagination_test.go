@@ -0,0 +1,160 @@
+package github
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestCursorEncoding(t *testing.T) {
+	for _, c := range []pageCursor{{page: 1, perPage: 30}, {page: 7, perPage: 100, offset: 42}} {
+		got, err := decodeCursor(c.encode())
+		if err != nil {
+			t.Fatalf("decodeCursor(%v): %v", c, err)
+		}
+		if got != c {
+			t.Errorf("round trip = %v, want %v", got, c)
+		}
+	}
+
+	encode := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "***"},
+		{name: "not numbers", cursor: encode("a:b:c")},
+		{name: "missing fields", cursor: encode("2:30")},
+		{name: "page zero", cursor: encode("0:30:0")},
+		{name: "per page zero", cursor: encode("1:0:0")},
+		{name: "per page too large", cursor: encode("1:101:0")},
+		{name: "negative offset", cursor: encode("1:30:-1")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := decodeCursor(tt.cursor); err == nil {
+				t.Errorf("decodeCursor(%q) = %v, want error", tt.cursor, got)
+			}
+		})
+	}
+}
+
+// fakeList simula un listado de la API de n elementos (1..n) y anota las páginas pedidas
+type fakeList struct {
+	n        int
+	requests []github.ListOptions
+}
+
+func (f *fakeList) fetch(opt github.ListOptions) ([]int, *github.Response, error) {
+	f.requests = append(f.requests, opt)
+	resp := &github.Response{}
+	var page []int
+	for i := (opt.Page-1)*opt.PerPage + 1; i <= min(opt.Page*opt.PerPage, f.n); i++ {
+		page = append(page, i)
+	}
+	if opt.Page*opt.PerPage < f.n {
+		resp.NextPage = opt.Page + 1
+	}
+	return page, resp, nil
+}
+
+func seq(from, to int) []int {
+	items := []int{}
+	for i := from; i <= to; i++ {
+		items = append(items, i)
+	}
+	return items
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name       string
+		total      int
+		opts       PageOptions
+		want       []int
+		wantCursor *pageCursor
+		wantPages  int
+	}{
+		{name: "defaults", total: 100, opts: PageOptions{}, want: seq(1, 30), wantCursor: &pageCursor{page: 2, perPage: 30}, wantPages: 1},
+		{name: "single page", total: 10, opts: PageOptions{}, want: seq(1, 10), wantPages: 1},
+		{name: "empty", total: 0, opts: PageOptions{}, want: []int{}, wantPages: 1},
+		{name: "explicit page", total: 50, opts: PageOptions{Page: 2, PerPage: 20}, want: seq(21, 40), wantCursor: &pageCursor{page: 3, perPage: 20}, wantPages: 1},
+		{name: "per page capped", total: 500, opts: PageOptions{PerPage: 1000}, want: seq(1, 100), wantCursor: &pageCursor{page: 2, perPage: 100}, wantPages: 1},
+		{name: "several pages", total: 100, opts: PageOptions{PerPage: 10, MaxItems: 35}, want: seq(1, 35), wantCursor: &pageCursor{page: 4, perPage: 10, offset: 5}, wantPages: 4},
+		{name: "ends at page boundary", total: 100, opts: PageOptions{PerPage: 10, MaxItems: 20}, want: seq(1, 20), wantCursor: &pageCursor{page: 3, perPage: 10}, wantPages: 2},
+		{name: "all items", total: 25, opts: PageOptions{PerPage: 10, MaxItems: 100}, want: seq(1, 25), wantPages: 3},
+		{name: "exactly max items", total: 20, opts: PageOptions{PerPage: 10, MaxItems: 20}, want: seq(1, 20), wantPages: 2},
+		{name: "cursor with offset", total: 100, opts: PageOptions{Cursor: pageCursor{page: 4, perPage: 10, offset: 5}.encode(), MaxItems: 10}, want: seq(36, 45), wantCursor: &pageCursor{page: 5, perPage: 10, offset: 5}, wantPages: 2},
+		{name: "cursor overrides page", total: 100, opts: PageOptions{Page: 9, PerPage: 50, Cursor: pageCursor{page: 2, perPage: 10}.encode()}, want: seq(11, 20), wantCursor: &pageCursor{page: 3, perPage: 10}, wantPages: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := &fakeList{n: tt.total}
+			items, cursor, err := paginate(tt.opts, list.fetch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(items, tt.want) {
+				t.Errorf("items = %v, want %v", items, tt.want)
+			}
+			wantCursor := ""
+			if tt.wantCursor != nil {
+				wantCursor = tt.wantCursor.encode()
+			}
+			if cursor != wantCursor {
+				got, _ := decodeCursor(cursor)
+				t.Errorf("cursor = %q (%v), want %v", cursor, got, tt.wantCursor)
+			}
+			if len(list.requests) != tt.wantPages {
+				t.Errorf("pages requested = %v, want %d", list.requests, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestPaginateResume(t *testing.T) {
+	// Siguiendo los cursores se recorre el listado completo sin repetir ni saltar elementos
+	for _, opts := range []PageOptions{{PerPage: 10, MaxItems: 7}, {PerPage: 7, MaxItems: 10}, {PerPage: 5}} {
+		list := &fakeList{n: 53}
+		var all []int
+		for requests := 0; ; requests++ {
+			if requests > 20 {
+				t.Fatalf("%+v: cursor does not advance", opts)
+			}
+			items, cursor, err := paginate(opts, list.fetch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			all = append(all, items...)
+			if cursor == "" {
+				break
+			}
+			opts.Cursor = cursor
+		}
+		if want := seq(1, 53); !reflect.DeepEqual(all, want) {
+			t.Errorf("%+v: items = %v, want 1..53", opts, all)
+		}
+	}
+}
+
+func TestPaginateErrors(t *testing.T) {
+	fetchErr := errors.New("boom")
+	_, _, err := paginate(PageOptions{}, func(github.ListOptions) ([]int, *github.Response, error) {
+		return nil, nil, fetchErr
+	})
+	if !errors.Is(err, fetchErr) {
+		t.Errorf("fetch error = %v, want %v", err, fetchErr)
+	}
+
+	list := &fakeList{n: 10}
+	if _, _, err := paginate(PageOptions{Cursor: "bad!"}, list.fetch); err == nil {
+		t.Error("invalid cursor accepted")
+	}
+	if len(list.requests) != 0 {
+		t.Errorf("invalid cursor requested %d pages", len(list.requests))
+	}
+}
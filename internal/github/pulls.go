@@ -0,0 +1,182 @@
+package github
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// ReviewRequest es una revisión a crear. Sin Event la revisión queda pendiente
+// (visible solo para su autor hasta que se envíe desde GitHub).
+type ReviewRequest struct {
+	Event    string // APPROVE, REQUEST_CHANGES o COMMENT
+	Body     string
+	CommitID string // commit revisado (default: el último del PR)
+	Comments []ReviewCommentDraft
+}
+
+// ReviewCommentDraft es un comentario de una revisión anclado a una línea del diff.
+// StartLine/StartSide marcan el inicio de un comentario multilínea.
+type ReviewCommentDraft struct {
+	Path      string
+	Line      int
+	Side      string // RIGHT (versión nueva, default) o LEFT (versión anterior)
+	StartLine int
+	StartSide string
+	Body      string
+}
+
+// ListPullRequestFiles lista los archivos modificados por un pull request con sus parches
+func ListPullRequestFiles(client *github.Client, ctx context.Context, owner, repoName string, number int, opts PageOptions) (*PullRequestFileList, error) {
+	files, next, err := paginate(opts, func(page github.ListOptions) ([]PullRequestFile, *github.Response, error) {
+		files, resp, err := client.PullRequests.ListFiles(ctx, owner, repoName, number, &page)
+		if err != nil {
+			return nil, nil, err
+		}
+		result := make([]PullRequestFile, 0, len(files))
+		for _, file := range files {
+			result = append(result, PullRequestFile{
+				Path:         file.GetFilename(),
+				PreviousPath: file.GetPreviousFilename(),
+				Status:       file.GetStatus(),
+				Additions:    file.GetAdditions(),
+				Deletions:    file.GetDeletions(),
+				Patch:        file.GetPatch(),
+			})
+		}
+		return result, resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &PullRequestFileList{Number: number, Files: files, NextCursor: next}, nil
+}
+
+// GetPullRequestDiff obtiene el diff unificado de un pull request
+func GetPullRequestDiff(client *github.Client, ctx context.Context, owner, repoName string, number int) (*PullRequestDiff, error) {
+	diff, _, err := client.PullRequests.GetRaw(ctx, owner, repoName, number, github.RawOptions{Type: github.Diff})
+	if err != nil {
+		return nil, err
+	}
+	return &PullRequestDiff{Number: number, Diff: diff}, nil
+}
+
+// CreateReview crea una revisión de un pull request con comentarios en línea
+func CreateReview(client *github.Client, ctx context.Context, owner, repoName string, number int, review ReviewRequest) (*ReviewCreated, error) {
+	request := &github.PullRequestReviewRequest{}
+	if review.Event != "" {
+		request.Event = github.String(review.Event)
+	}
+	if review.Body != "" {
+		request.Body = github.String(review.Body)
+	}
+	if review.CommitID != "" {
+		request.CommitID = github.String(review.CommitID)
+	}
+
+	for _, comment := range review.Comments {
+		draft := &github.DraftReviewComment{
+			Path: github.String(comment.Path),
+			Body: github.String(comment.Body),
+			Line: github.Int(comment.Line),
+			Side: github.String(comment.Side),
+		}
+		if comment.Side == "" {
+			draft.Side = github.String("RIGHT")
+		}
+		if comment.StartLine != 0 {
+			draft.StartLine = github.Int(comment.StartLine)
+			draft.StartSide = draft.Side
+			if comment.StartSide != "" {
+				draft.StartSide = github.String(comment.StartSide)
+			}
+		}
+		request.Comments = append(request.Comments, draft)
+	}
+
+	created, _, err := client.PullRequests.CreateReview(ctx, owner, repoName, number, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReviewCreated{
+		Number:   number,
+		ID:       created.GetID(),
+		State:    created.GetState(),
+		URL:      created.GetHTMLURL(),
+		Comments: len(review.Comments),
+	}, nil
+}
+
+// ListReviewComments lista los comentarios en línea de las revisiones de un pull request
+func ListReviewComments(client *github.Client, ctx context.Context, owner, repoName string, number int, opts PageOptions) (*ReviewCommentList, error) {
+	comments, next, err := paginate(opts, func(page github.ListOptions) ([]ReviewComment, *github.Response, error) {
+		comments, resp, err := client.PullRequests.ListComments(ctx, owner, repoName, number, &github.PullRequestListCommentsOptions{ListOptions: page})
+		if err != nil {
+			return nil, nil, err
+		}
+		result := make([]ReviewComment, 0, len(comments))
+		for _, comment := range comments {
+			result = append(result, reviewComment(comment))
+		}
+		return result, resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ReviewCommentList{Number: number, Comments: comments, NextCursor: next}, nil
+}
+
+// ReplyToReviewComment responde en el hilo de un comentario de revisión
+func ReplyToReviewComment(client *github.Client, ctx context.Context, owner, repoName string, number int, commentID int64, body string) (*ReviewComment, error) {
+	comment, _, err := client.PullRequests.CreateCommentInReplyTo(ctx, owner, repoName, number, body, commentID)
+	if err != nil {
+		return nil, err
+	}
+	result := reviewComment(comment)
+	return &result, nil
+}
+
+// RequestReviewers solicita revisión a usuarios y/o equipos (slug del equipo)
+func RequestReviewers(client *github.Client, ctx context.Context, owner, repoName string, number int, reviewers, teams []string) (*ReviewersRequested, error) {
+	pr, _, err := client.PullRequests.RequestReviewers(ctx, owner, repoName, number, github.ReviewersRequest{
+		Reviewers:     reviewers,
+		TeamReviewers: teams,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reviewersList, teamList := requestedReviewers(pr)
+	return &ReviewersRequested{Number: number, Reviewers: reviewersList, Teams: teamList}, nil
+}
+
+// requestedReviewers devuelve los logins y equipos con revisión pendiente
+func requestedReviewers(pr *github.PullRequest) ([]string, []string) {
+	reviewers, teams := []string{}, []string{}
+	for _, user := range pr.RequestedReviewers {
+		reviewers = append(reviewers, user.GetLogin())
+	}
+	for _, team := range pr.RequestedTeams {
+		teams = append(teams, team.GetSlug())
+	}
+	return reviewers, teams
+}
+
+func reviewComment(comment *github.PullRequestComment) ReviewComment {
+	return ReviewComment{
+		ID:        comment.GetID(),
+		InReplyTo: comment.GetInReplyTo(),
+		ReviewID:  comment.GetPullRequestReviewID(),
+		User:      comment.GetUser().GetLogin(),
+		Path:      comment.GetPath(),
+		Line:      comment.GetLine(),
+		Side:      comment.GetSide(),
+		StartLine: comment.GetStartLine(),
+		CommitID:  comment.GetCommitID(),
+		Body:      comment.GetBody(),
+		URL:       comment.GetHTMLURL(),
+		CreatedAt: comment.GetCreatedAt().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,179 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// Reintentos de las peticiones a la API
+const (
+	maxRetries         = 3
+	maxRateLimitWait   = 65 * time.Second // más allá se devuelve el error de límite sin esperar
+	secondaryLimitWait = time.Minute      // espera recomendada por GitHub si el límite no indica cuánto
+)
+
+// retryBaseDelay es la espera del primer reintento, que se dobla en cada intento
+var retryBaseDelay = time.Second
+
+// retryTransport reintenta las peticiones rechazadas por los límites de la API (primario
+// y secundario) cuando el cupo se renueva pronto, y las idempotentes que fallan por un
+// error de red o un 5xx, con esperas exponenciales aleatorizadas.
+type retryTransport struct {
+	base http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		wait, retry := retryDelay(req, resp, err, attempt)
+		if !retry {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+
+		next := req.Clone(req.Context())
+		if req.GetBody != nil {
+			if next.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+		req = next
+	}
+}
+
+// retryDelay decide si se reintenta una petición y cuánto se espera antes
+func retryDelay(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if attempt >= maxRetries || req.Context().Err() != nil {
+		return 0, false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return 0, false // el cuerpo no se puede volver a enviar
+	}
+
+	if err != nil {
+		return backoff(attempt), idempotent(req.Method)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests:
+		// La petición rechazada por un límite no se ha procesado: se puede repetir sea cual sea el método
+		wait, limited := rateLimitWait(resp)
+		if !limited || wait > maxRateLimitWait {
+			return 0, false
+		}
+		wait += rand.N(retryBaseDelay)
+		// Si el plazo de la petición vence antes, se devuelve ya el error de límite
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < wait {
+			return 0, false
+		}
+		return wait, true
+	case resp.StatusCode >= http.StatusInternalServerError && resp.StatusCode != http.StatusNotImplemented:
+		return backoff(attempt), idempotent(req.Method)
+	}
+	return 0, false
+}
+
+// rateLimitWait devuelve la espera que indica una respuesta rechazada por un límite:
+// Retry-After (límite secundario), X-RateLimit-Reset con el cupo agotado (primario) o,
+// si el límite secundario llega sin cabeceras, el minuto que recomienda GitHub
+func rateLimitWait(resp *http.Response) (time.Duration, bool) {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+		if err == nil {
+			return max(time.Until(time.Unix(reset, 0)), 0), true
+		}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || secondaryLimitBody(resp) {
+		return secondaryLimitWait, true
+	}
+	return 0, false
+}
+
+// secondaryLimitBody indica si el cuerpo de un 403 es el mensaje del límite secundario.
+// El cuerpo leído se repone para que el llamador reciba la respuesta completa.
+func secondaryLimitBody(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusForbidden || resp.Body == nil {
+		return false
+	}
+	head, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
+
+	message := strings.ToLower(string(head))
+	return strings.Contains(message, "secondary rate limit") || strings.Contains(message, "abuse detection")
+}
+
+// backoff es la espera exponencial del intento, aleatorizada entre la mitad y el total
+func backoff(attempt int) time.Duration {
+	delay := retryBaseDelay << attempt
+	return delay/2 + rand.N(delay/2)
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// RateLimits consulta el cupo restante de cada recurso de la API. La consulta no
+// consume cupo.
+func RateLimits(client *github.Client, ctx context.Context) (*RateLimitReport, error) {
+	limits, _, err := client.RateLimit.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RateLimitReport{Resources: []RateBucket{}}
+	for _, bucket := range []struct {
+		name string
+		rate *github.Rate
+	}{
+		{"core", limits.Core},
+		{"search", limits.Search},
+		{"code_search", limits.CodeSearch},
+		{"graphql", limits.GraphQL},
+		{"integration_manifest", limits.IntegrationManifest},
+		{"source_import", limits.SourceImport},
+		{"code_scanning_upload", limits.CodeScanningUpload},
+		{"actions_runner_registration", limits.ActionsRunnerRegistration},
+		{"scim", limits.SCIM},
+		{"dependency_snapshots", limits.DependencySnapshots},
+		{"audit_log", limits.AuditLog},
+	} {
+		if bucket.rate == nil {
+			continue
+		}
+		report.Resources = append(report.Resources, RateBucket{
+			Resource:  bucket.name,
+			Limit:     bucket.rate.Limit,
+			Remaining: bucket.rate.Remaining,
+			Used:      bucket.rate.Limit - bucket.rate.Remaining,
+			Reset:     bucket.rate.Reset.Format(time.RFC3339),
+		})
+	}
+	return report, nil
+}
@@ -0,0 +1,221 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	get, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos/o/r", nil)
+	post, _ := http.NewRequest(http.MethodPost, "https://api.github.com/repos/o/r/issues", strings.NewReader("{}"))
+	stream, _ := http.NewRequest(http.MethodPost, "https://api.github.com/repos/o/r/issues", io.NopCloser(strings.NewReader("{}")))
+
+	respond := func(status int, header ...string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}}
+		for i := 0; i+1 < len(header); i += 2 {
+			resp.Header.Set(header[i], header[i+1])
+		}
+		return resp
+	}
+	reset := func(d time.Duration) string { return strconv.FormatInt(time.Now().Add(d).Unix(), 10) }
+	secondary := func() *http.Response {
+		resp := respond(403)
+		resp.Body = io.NopCloser(strings.NewReader(`{"message":"You have exceeded a secondary rate limit. Please wait a few minutes before you try again."}`))
+		return resp
+	}
+	deadline, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	getWithDeadline := get.WithContext(deadline)
+
+	tests := []struct {
+		name      string
+		req       *http.Request
+		resp      *http.Response
+		err       error
+		attempt   int
+		wantRetry bool
+		minWait   time.Duration
+		maxWait   time.Duration
+	}{
+		{name: "success", req: get, resp: respond(200)},
+		{name: "retry-after", req: get, resp: respond(403, "Retry-After", "30"), wantRetry: true, minWait: 30 * time.Second, maxWait: 30*time.Second + retryBaseDelay},
+		{name: "retry-after on POST", req: post, resp: respond(429, "Retry-After", "2"), wantRetry: true, minWait: 2 * time.Second, maxWait: 2*time.Second + retryBaseDelay},
+		{name: "rate limit reset", req: get, resp: respond(403, "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", reset(20*time.Second)), wantRetry: true, minWait: 18 * time.Second, maxWait: 20*time.Second + retryBaseDelay},
+		{name: "reset in the past", req: get, resp: respond(403, "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", reset(-time.Minute)), wantRetry: true, maxWait: retryBaseDelay},
+		{name: "retry-after beyond cutoff", req: get, resp: respond(403, "Retry-After", strconv.Itoa(int(maxRateLimitWait/time.Second)+1))},
+		{name: "reset beyond cutoff", req: get, resp: respond(403, "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", reset(10*time.Minute))},
+		{name: "429 without headers", req: get, resp: respond(429), wantRetry: true, minWait: time.Minute, maxWait: time.Minute + retryBaseDelay},
+		{name: "secondary limit without headers", req: get, resp: secondary(), wantRetry: true, minWait: secondaryLimitWait, maxWait: secondaryLimitWait + retryBaseDelay},
+		{name: "secondary limit past the deadline", req: getWithDeadline, resp: secondary()},
+		{name: "retry-after past the deadline", req: getWithDeadline, resp: respond(429, "Retry-After", "30")},
+		{name: "retry-after within the deadline", req: getWithDeadline, resp: respond(429, "Retry-After", "2"), wantRetry: true, minWait: 2 * time.Second, maxWait: 2*time.Second + retryBaseDelay},
+		{name: "forbidden", req: get, resp: respond(403, "X-RateLimit-Remaining", "4000")},
+		{name: "server error", req: get, resp: respond(502), wantRetry: true, minWait: retryBaseDelay / 2, maxWait: retryBaseDelay},
+		{name: "backoff grows", req: get, resp: respond(503), attempt: 2, wantRetry: true, minWait: 2 * retryBaseDelay, maxWait: 4 * retryBaseDelay},
+		{name: "server error on POST", req: post, resp: respond(502)},
+		{name: "not implemented", req: get, resp: respond(501)},
+		{name: "network error", req: get, err: errors.New("connection reset"), wantRetry: true, minWait: retryBaseDelay / 2, maxWait: retryBaseDelay},
+		{name: "network error on POST", req: post, err: errors.New("connection reset")},
+		{name: "body without GetBody", req: stream, resp: respond(429, "Retry-After", "1")},
+		{name: "attempts exhausted", req: get, resp: respond(502), attempt: maxRetries},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, retry := retryDelay(tt.req, tt.resp, tt.err, tt.attempt)
+			if retry != tt.wantRetry {
+				t.Fatalf("retry = %v, want %v", retry, tt.wantRetry)
+			}
+			if retry && (wait < tt.minWait || wait > tt.maxWait) {
+				t.Errorf("wait = %v, want between %v and %v", wait, tt.minWait, tt.maxWait)
+			}
+		})
+	}
+}
+
+// shortRetries acorta las esperas de los reintentos durante un test
+func shortRetries(t *testing.T) {
+	previous := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	t.Cleanup(func() { retryBaseDelay = previous })
+}
+
+// scriptedServer responde con los códigos indicados, uno por petición (200 al acabarse),
+// y guarda el método y el cuerpo de cada petición
+type scriptedServer struct {
+	mu       sync.Mutex
+	statuses []int
+	header   http.Header
+	requests []string
+}
+
+func (s *scriptedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Method+" "+string(body))
+	status := http.StatusOK
+	if len(s.statuses) > 0 {
+		status, s.statuses = s.statuses[0], s.statuses[1:]
+	}
+	if status != http.StatusOK {
+		for name, values := range s.header {
+			w.Header()[name] = values
+		}
+	}
+	w.WriteHeader(status)
+	w.Write([]byte(`{}`))
+}
+
+func TestRetryTransport(t *testing.T) {
+	shortRetries(t)
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		statuses   []int
+		header     http.Header
+		wantStatus int
+		wantCalls  int
+	}{
+		{name: "5xx retried", method: http.MethodGet, statuses: []int{503, 502}, wantStatus: 200, wantCalls: 3},
+		{name: "5xx gives up", method: http.MethodGet, statuses: []int{500, 500, 500, 500, 500}, wantStatus: 500, wantCalls: maxRetries + 1},
+		{name: "POST not retried on 5xx", method: http.MethodPost, body: `{"title":"x"}`, statuses: []int{502}, wantStatus: 502, wantCalls: 1},
+		{name: "secondary limit", method: http.MethodPost, body: `{"title":"x"}`, statuses: []int{403}, header: http.Header{"Retry-After": {"0"}}, wantStatus: 200, wantCalls: 2},
+		{name: "primary limit", method: http.MethodGet, statuses: []int{403}, header: http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"0"}}, wantStatus: 200, wantCalls: 2},
+		{name: "limit beyond cutoff", method: http.MethodGet, statuses: []int{429}, header: http.Header{"Retry-After": {"3600"}}, wantStatus: 429, wantCalls: 1},
+		{name: "client error", method: http.MethodGet, statuses: []int{422}, wantStatus: 422, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := &scriptedServer{statuses: tt.statuses, header: tt.header}
+			server := httptest.NewServer(script)
+			defer server.Close()
+
+			var body io.Reader
+			if tt.body != "" {
+				body = bytes.NewReader([]byte(tt.body))
+			}
+			req, err := http.NewRequestWithContext(context.Background(), tt.method, server.URL, body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := (&retryTransport{base: http.DefaultTransport}).RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if len(script.requests) != tt.wantCalls {
+				t.Fatalf("requests = %q, want %d", script.requests, tt.wantCalls)
+			}
+			// Cada reintento reenvía el cuerpo completo
+			for i, got := range script.requests {
+				if want := tt.method + " " + tt.body; got != want {
+					t.Errorf("request %d = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransportCancel(t *testing.T) {
+	script := &scriptedServer{statuses: []int{429}, header: http.Header{"Retry-After": {"30"}}}
+	server := httptest.NewServer(script)
+	defer server.Close()
+
+	// Sin plazo se espera al Retry-After; cancelar corta la espera
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+
+	start := time.Now()
+	if _, err := (&retryTransport{base: http.DefaultTransport}).RoundTrip(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cancelled wait took %v", elapsed)
+	}
+}
+
+func TestRetryTransportSecondaryLimit(t *testing.T) {
+	const message = `{"message":"You have exceeded a secondary rate limit"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(message))
+	}))
+	defer server.Close()
+
+	// Sin cabeceras la espera es de un minuto: con un plazo menor se devuelve el 403 sin esperar
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+
+	start := time.Now()
+	resp, err := (&retryTransport{base: http.DefaultTransport}).RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("gave up after %v", elapsed)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusForbidden || string(body) != message {
+		t.Errorf("response = %d %s, want the 403 intact", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,228 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// ReleaseChanges son los campos de un release a crear o modificar. Los punteros nil no se envían.
+type ReleaseChanges struct {
+	TagName              *string
+	Target               *string // rama o SHA desde el que se crea el tag si no existe
+	Name                 *string
+	Body                 *string
+	Draft                *bool
+	Prerelease           *bool
+	MakeLatest           *string // true, false o legacy
+	GenerateReleaseNotes *bool   // solo al crear: notas generadas a partir de los PRs
+}
+
+// ListReleases lista los releases de un repositorio, del más reciente al más antiguo
+func ListReleases(client *github.Client, ctx context.Context, owner, repoName string, opts PageOptions) (*ReleaseList, error) {
+	releases, next, err := paginate(opts, func(page github.ListOptions) ([]ReleaseSummary, *github.Response, error) {
+		releases, resp, err := client.Repositories.ListReleases(ctx, owner, repoName, &page)
+		if err != nil {
+			return nil, nil, err
+		}
+		summaries := make([]ReleaseSummary, 0, len(releases))
+		for _, release := range releases {
+			summaries = append(summaries, releaseSummary(release))
+		}
+		return summaries, resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ReleaseList{Releases: releases, NextCursor: next}, nil
+}
+
+// GetRelease obtiene un release por ID o por tag; sin ninguno de los dos, el último publicado
+func GetRelease(client *github.Client, ctx context.Context, owner, repoName string, id int64, tag string) (*ReleaseDetail, error) {
+	release, err := findRelease(client, ctx, owner, repoName, id, tag)
+	if err != nil {
+		return nil, err
+	}
+	return releaseDetail(release), nil
+}
+
+// CreateRelease crea un release (y el tag, desde Target, si aún no existe)
+func CreateRelease(client *github.Client, ctx context.Context, owner, repoName string, fields ReleaseChanges) (*ReleaseDetail, error) {
+	release, _, err := client.Repositories.CreateRelease(ctx, owner, repoName, fields.request())
+	if err != nil {
+		return nil, err
+	}
+	return releaseDetail(release), nil
+}
+
+// UpdateRelease modifica un release identificado por ID o por tag
+func UpdateRelease(client *github.Client, ctx context.Context, owner, repoName string, id int64, tag string, changes ReleaseChanges) (*ReleaseDetail, error) {
+	if id == 0 {
+		release, err := findRelease(client, ctx, owner, repoName, 0, tag)
+		if err != nil {
+			return nil, err
+		}
+		id = release.GetID()
+	}
+
+	changes.GenerateReleaseNotes = nil
+	release, _, err := client.Repositories.EditRelease(ctx, owner, repoName, id, changes.request())
+	if err != nil {
+		return nil, err
+	}
+	return releaseDetail(release), nil
+}
+
+// UploadReleaseAsset sube un archivo como asset de un release identificado por ID o por tag
+func UploadReleaseAsset(client *github.Client, ctx context.Context, owner, repoName string, id int64, tag string, file *os.File, name, label string) (*ReleaseAsset, error) {
+	if id == 0 {
+		release, err := findRelease(client, ctx, owner, repoName, 0, tag)
+		if err != nil {
+			return nil, err
+		}
+		id = release.GetID()
+	}
+
+	asset, _, err := client.Repositories.UploadReleaseAsset(ctx, owner, repoName, id, &github.UploadOptions{Name: name, Label: label}, file)
+	if err != nil {
+		return nil, err
+	}
+	result := releaseAsset(asset)
+	return &result, nil
+}
+
+// FindReleaseAsset busca un asset por nombre en un release identificado por ID o por tag
+func FindReleaseAsset(client *github.Client, ctx context.Context, owner, repoName string, id int64, tag, name string) (*ReleaseAsset, error) {
+	release, err := findRelease(client, ctx, owner, repoName, id, tag)
+	if err != nil {
+		return nil, err
+	}
+	for _, asset := range release.Assets {
+		if asset.GetName() == name {
+			result := releaseAsset(asset)
+			return &result, nil
+		}
+	}
+	return nil, fmt.Errorf("asset %q not found in release %s", name, release.GetTagName())
+}
+
+// DownloadReleaseAsset copia el contenido de un asset en w y devuelve los bytes escritos.
+// Las descargas redirigidas a almacenamiento externo no reciben el token.
+func DownloadReleaseAsset(client *github.Client, ctx context.Context, owner, repoName string, assetID int64, w io.Writer) (int64, error) {
+	rc, redirectURL, err := client.Repositories.DownloadReleaseAsset(ctx, owner, repoName, assetID, client.Client())
+	if err != nil {
+		return 0, err
+	}
+	if rc == nil {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, redirectURL, nil)
+		if err != nil {
+			return 0, err
+		}
+		resp, err := client.Client().Do(req)
+		if err != nil {
+			return 0, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return 0, fmt.Errorf("downloading asset: %s", resp.Status)
+		}
+		rc = resp.Body
+	}
+	defer rc.Close()
+	return io.Copy(w, rc)
+}
+
+// findRelease resuelve un release por ID, por tag o el último publicado. Los borradores
+// no se pueden obtener por tag, así que se buscan también en la lista de releases.
+func findRelease(client *github.Client, ctx context.Context, owner, repoName string, id int64, tag string) (*github.RepositoryRelease, error) {
+	switch {
+	case id != 0:
+		release, _, err := client.Repositories.GetRelease(ctx, owner, repoName, id)
+		return release, err
+	case tag == "":
+		release, _, err := client.Repositories.GetLatestRelease(ctx, owner, repoName)
+		return release, err
+	}
+
+	release, _, err := client.Repositories.GetReleaseByTag(ctx, owner, repoName, tag)
+	if err == nil || !isNotFound(err) {
+		return release, err
+	}
+
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		releases, resp, listErr := client.Repositories.ListReleases(ctx, owner, repoName, opt)
+		if listErr != nil {
+			return nil, listErr
+		}
+		for _, release := range releases {
+			if release.GetDraft() && release.GetTagName() == tag {
+				return release, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return nil, err
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+func (c ReleaseChanges) request() *github.RepositoryRelease {
+	return &github.RepositoryRelease{
+		TagName:              c.TagName,
+		TargetCommitish:      c.Target,
+		Name:                 c.Name,
+		Body:                 c.Body,
+		Draft:                c.Draft,
+		Prerelease:           c.Prerelease,
+		MakeLatest:           c.MakeLatest,
+		GenerateReleaseNotes: c.GenerateReleaseNotes,
+	}
+}
+
+func releaseSummary(release *github.RepositoryRelease) ReleaseSummary {
+	summary := ReleaseSummary{
+		ID:         release.GetID(),
+		Tag:        release.GetTagName(),
+		Name:       release.GetName(),
+		Draft:      release.GetDraft(),
+		Prerelease: release.GetPrerelease(),
+		Author:     release.GetAuthor().GetLogin(),
+		URL:        release.GetHTMLURL(),
+		CreatedAt:  release.GetCreatedAt().Format(time.RFC3339),
+	}
+	if release.PublishedAt != nil {
+		summary.PublishedAt = release.GetPublishedAt().Format(time.RFC3339)
+	}
+	return summary
+}
+
+func releaseDetail(release *github.RepositoryRelease) *ReleaseDetail {
+	detail := &ReleaseDetail{
+		ReleaseSummary: releaseSummary(release),
+		Target:         release.GetTargetCommitish(),
+		Body:           release.GetBody(),
+		Assets:         []ReleaseAsset{},
+	}
+	for _, asset := range release.Assets {
+		detail.Assets = append(detail.Assets, releaseAsset(asset))
+	}
+	return detail
+}
+
+func releaseAsset(asset *github.ReleaseAsset) ReleaseAsset {
+	return ReleaseAsset{
+		ID:          asset.GetID(),
+		Name:        asset.GetName(),
+		Label:       asset.GetLabel(),
+		ContentType: asset.GetContentType(),
+		Size:        asset.GetSize(),
+		Downloads:   asset.GetDownloadCount(),
+		URL:         asset.GetBrowserDownloadURL(),
+	}
+}
@@ -0,0 +1,281 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// topicPattern es el formato que GitHub admite para los topics
+var topicPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,49}$`)
+
+// RepositoryChanges son los cambios de la configuración de un repositorio. Los
+// punteros nil conservan el valor actual.
+type RepositoryChanges struct {
+	Description   *string
+	Homepage      *string
+	Visibility    *string // public, private o internal (organizaciones de Enterprise)
+	DefaultBranch *string
+	Archived      *bool // un repositorio archivado es de solo lectura
+	IsTemplate    *bool
+
+	HasIssues      *bool
+	HasWiki        *bool
+	HasProjects    *bool
+	HasDiscussions *bool
+
+	AllowMergeCommit    *bool
+	AllowSquashMerge    *bool
+	AllowRebaseMerge    *bool
+	AllowAutoMerge      *bool
+	AllowUpdateBranch   *bool
+	DeleteBranchOnMerge *bool
+}
+
+// RepositoryTemplate es la petición de un repositorio creado desde una plantilla
+type RepositoryTemplate struct {
+	TemplateOwner      string
+	TemplateRepo       string
+	Owner              string // usuario u organización del repositorio nuevo (default: el autenticado)
+	Name               string
+	Description        string
+	Private            bool
+	IncludeAllBranches bool // por defecto solo se copia la rama por defecto
+}
+
+// GetRepository obtiene los metadatos de un repositorio con sus topics y los
+// permisos del usuario autenticado
+func GetRepository(client *github.Client, ctx context.Context, owner, repoName string) (*RepositoryDetail, error) {
+	repo, _, err := client.Repositories.Get(ctx, owner, repoName)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("repository %s/%s not found", owner, repoName)
+		}
+		return nil, err
+	}
+	return repositoryDetail(repo), nil
+}
+
+// UpdateRepository modifica la configuración de un repositorio
+func UpdateRepository(client *github.Client, ctx context.Context, owner, repoName string, changes RepositoryChanges) (*RepositoryDetail, error) {
+	if changes == (RepositoryChanges{}) {
+		return nil, fmt.Errorf("no repository changes")
+	}
+
+	edit := &github.Repository{
+		Description:         changes.Description,
+		Homepage:            changes.Homepage,
+		Visibility:          changes.Visibility,
+		DefaultBranch:       changes.DefaultBranch,
+		Archived:            changes.Archived,
+		IsTemplate:          changes.IsTemplate,
+		HasIssues:           changes.HasIssues,
+		HasWiki:             changes.HasWiki,
+		HasProjects:         changes.HasProjects,
+		HasDiscussions:      changes.HasDiscussions,
+		AllowMergeCommit:    changes.AllowMergeCommit,
+		AllowSquashMerge:    changes.AllowSquashMerge,
+		AllowRebaseMerge:    changes.AllowRebaseMerge,
+		AllowAutoMerge:      changes.AllowAutoMerge,
+		AllowUpdateBranch:   changes.AllowUpdateBranch,
+		DeleteBranchOnMerge: changes.DeleteBranchOnMerge,
+	}
+
+	repo, _, err := client.Repositories.Edit(ctx, owner, repoName, edit)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("repository %s/%s not found", owner, repoName)
+		}
+		return nil, err
+	}
+	return repositoryDetail(repo), nil
+}
+
+// CreateRepositoryFromTemplate crea un repositorio con los archivos de un repositorio plantilla
+func CreateRepositoryFromTemplate(client *github.Client, ctx context.Context, template RepositoryTemplate) (*RepositoryCreated, error) {
+	request := &github.TemplateRepoRequest{
+		Name:               github.String(template.Name),
+		Private:            github.Bool(template.Private),
+		IncludeAllBranches: github.Bool(template.IncludeAllBranches),
+	}
+	if template.Owner != "" {
+		request.Owner = github.String(template.Owner)
+	}
+	if template.Description != "" {
+		request.Description = github.String(template.Description)
+	}
+
+	repo, _, err := client.Repositories.CreateFromTemplate(ctx, template.TemplateOwner, template.TemplateRepo, request)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("template repository %s/%s not found", template.TemplateOwner, template.TemplateRepo)
+		}
+		return nil, err
+	}
+
+	return &RepositoryCreated{
+		Name:     repo.GetName(),
+		FullName: repo.GetFullName(),
+		Private:  repo.GetPrivate(),
+		URL:      repo.GetHTMLURL(),
+		Template: template.TemplateOwner + "/" + template.TemplateRepo,
+	}, nil
+}
+
+// ForkRepository bifurca un repositorio en la cuenta del usuario o en una organización.
+// GitHub copia el contenido en segundo plano: el fork puede tardar en estar disponible.
+func ForkRepository(client *github.Client, ctx context.Context, owner, repoName, org, name string, defaultBranchOnly bool) (*RepositoryCreated, error) {
+	fork, _, err := client.Repositories.CreateFork(ctx, owner, repoName, &github.RepositoryCreateForkOptions{
+		Organization:      org,
+		Name:              name,
+		DefaultBranchOnly: defaultBranchOnly,
+	})
+	if err != nil && !isAccepted(err) {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("repository %s/%s not found", owner, repoName)
+		}
+		return nil, err
+	}
+
+	return &RepositoryCreated{
+		Name:     fork.GetName(),
+		FullName: fork.GetFullName(),
+		Private:  fork.GetPrivate(),
+		URL:      fork.GetHTMLURL(),
+		Parent:   owner + "/" + repoName,
+	}, nil
+}
+
+// DeleteRepository borra un repositorio. No se puede deshacer.
+func DeleteRepository(client *github.Client, ctx context.Context, owner, repoName string) (*RepositoryDeleted, error) {
+	if _, err := client.Repositories.Delete(ctx, owner, repoName); err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("repository %s/%s not found", owner, repoName)
+		}
+		return nil, err
+	}
+	return &RepositoryDeleted{FullName: owner + "/" + repoName}, nil
+}
+
+// SetTopics sustituye los topics de un repositorio; una lista vacía los quita todos
+func SetTopics(client *github.Client, ctx context.Context, owner, repoName string, topics []string) (*RepositoryTopics, error) {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, topic := range topics {
+		topic = strings.ToLower(strings.TrimSpace(topic))
+		if !topicPattern.MatchString(topic) {
+			return nil, fmt.Errorf("invalid topic %q: use lowercase letters, digits and hyphens (up to 50 characters)", topic)
+		}
+		if !seen[topic] {
+			seen[topic] = true
+			names = append(names, topic)
+		}
+	}
+
+	names, _, err := client.Repositories.ReplaceAllTopics(ctx, owner, repoName, names)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("repository %s/%s not found", owner, repoName)
+		}
+		return nil, err
+	}
+	return &RepositoryTopics{FullName: owner + "/" + repoName, Topics: names}, nil
+}
+
+// TransferRepository transfiere un repositorio a otro usuario u organización,
+// opcionalmente con otro nombre. GitHub la completa en segundo plano.
+func TransferRepository(client *github.Client, ctx context.Context, owner, repoName, newOwner, newName string, teamIDs []int64) (*RepositoryTransfer, error) {
+	request := github.TransferRequest{NewOwner: newOwner, TeamID: teamIDs}
+	if newName != "" {
+		request.NewName = github.String(newName)
+	}
+
+	repo, _, err := client.Repositories.Transfer(ctx, owner, repoName, request)
+	var accepted *github.AcceptedError
+	switch {
+	case errors.As(err, &accepted):
+		// La API responde 202 con el repositorio en su nueva ubicación
+		repo = &github.Repository{}
+		json.Unmarshal(accepted.Raw, repo)
+	case isNotFound(err):
+		return nil, fmt.Errorf("repository %s/%s not found", owner, repoName)
+	case err != nil:
+		return nil, err
+	}
+
+	if newName == "" {
+		newName = repoName
+	}
+	result := &RepositoryTransfer{From: owner + "/" + repoName, To: newOwner + "/" + newName, URL: repo.GetHTMLURL()}
+	if repo.GetFullName() != "" {
+		result.To = repo.GetFullName()
+	}
+	return result, nil
+}
+
+// repositoryDetail convierte el repositorio de la API en el resultado
+func repositoryDetail(repo *github.Repository) *RepositoryDetail {
+	detail := &RepositoryDetail{
+		FullName:      repo.GetFullName(),
+		Description:   repo.GetDescription(),
+		Homepage:      repo.GetHomepage(),
+		URL:           repo.GetHTMLURL(),
+		CloneURL:      repo.GetCloneURL(),
+		SSHURL:        repo.GetSSHURL(),
+		Visibility:    repo.GetVisibility(),
+		Private:       repo.GetPrivate(),
+		Fork:          repo.GetFork(),
+		Archived:      repo.GetArchived(),
+		IsTemplate:    repo.GetIsTemplate(),
+		DefaultBranch: repo.GetDefaultBranch(),
+		Language:      repo.GetLanguage(),
+		License:       repo.GetLicense().GetSPDXID(),
+		Topics:        repo.Topics,
+		Stars:         repo.GetStargazersCount(),
+		Forks:         repo.GetForksCount(),
+		Watchers:      repo.GetSubscribersCount(),
+		OpenIssues:    repo.GetOpenIssuesCount(),
+		SizeKB:        repo.GetSize(),
+
+		HasIssues:      repo.GetHasIssues(),
+		HasWiki:        repo.GetHasWiki(),
+		HasProjects:    repo.GetHasProjects(),
+		HasDiscussions: repo.GetHasDiscussions(),
+
+		MergeSettings: MergeSettings{
+			AllowMergeCommit:    repo.GetAllowMergeCommit(),
+			AllowSquashMerge:    repo.GetAllowSquashMerge(),
+			AllowRebaseMerge:    repo.GetAllowRebaseMerge(),
+			AllowAutoMerge:      repo.GetAllowAutoMerge(),
+			AllowUpdateBranch:   repo.GetAllowUpdateBranch(),
+			DeleteBranchOnMerge: repo.GetDeleteBranchOnMerge(),
+		},
+		Permissions: repo.GetPermissions(),
+
+		CreatedAt: repo.GetCreatedAt().Format(time.RFC3339),
+		UpdatedAt: repo.GetUpdatedAt().Format(time.RFC3339),
+		PushedAt:  repo.GetPushedAt().Format(time.RFC3339),
+	}
+	if detail.Topics == nil {
+		detail.Topics = []string{}
+	}
+	if detail.Visibility == "" {
+		detail.Visibility = "public"
+		if detail.Private {
+			detail.Visibility = "private"
+		}
+	}
+	if parent := repo.GetParent(); parent != nil {
+		detail.Parent = parent.GetFullName()
+	}
+	if template := repo.GetTemplateRepository(); template != nil {
+		detail.Template = template.GetFullName()
+	}
+	return detail
+}
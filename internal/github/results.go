@@ -0,0 +1,1082 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Resultados de las operaciones de la GitHub API. Los campos exportados se
+// devuelven como structuredContent; String() genera el texto legible.
+
+// RepositorySummary es un repositorio en un listado
+type RepositorySummary struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Private     bool   `json:"private"`
+	URL         string `json:"url"`
+	Language    string `json:"language"`
+	Stars       int    `json:"stars"`
+}
+
+// RepositoryList es el resultado de github_list_repos
+type RepositoryList struct {
+	Repositories []RepositorySummary `json:"repositories"`
+	NextCursor   string              `json:"nextCursor,omitempty"` // cursor para continuar; vacío si no hay más
+}
+
+func (r *RepositoryList) String() string {
+	return listText(r.Repositories, r.NextCursor)
+}
+
+// RepositoryCreated es el repositorio recién creado, desde cero, desde una plantilla o como fork
+type RepositoryCreated struct {
+	Name     string `json:"name"`
+	FullName string `json:"fullName"`
+	Private  bool   `json:"private"`
+	URL      string `json:"url"`
+	Template string `json:"template,omitempty"` // repositorio plantilla de origen
+	Parent   string `json:"parent,omitempty"`   // repositorio bifurcado
+}
+
+func (r *RepositoryCreated) String() string {
+	switch {
+	case r.Parent != "":
+		return fmt.Sprintf("Fork '%s' of %s created: %s (contents are copied in the background and may take a few minutes)", r.FullName, r.Parent, r.URL)
+	case r.Template != "":
+		return fmt.Sprintf("Repository '%s' created from template %s: %s", r.FullName, r.Template, r.URL)
+	}
+	return fmt.Sprintf("Repository '%s' created successfully: %s", r.Name, r.URL)
+}
+
+// MergeSettings son los métodos de fusión y ajustes de PRs de un repositorio
+type MergeSettings struct {
+	AllowMergeCommit    bool `json:"allowMergeCommit"`
+	AllowSquashMerge    bool `json:"allowSquashMerge"`
+	AllowRebaseMerge    bool `json:"allowRebaseMerge"`
+	AllowAutoMerge      bool `json:"allowAutoMerge"`
+	AllowUpdateBranch   bool `json:"allowUpdateBranch"`
+	DeleteBranchOnMerge bool `json:"deleteBranchOnMerge"`
+}
+
+// RepositoryDetail es el resultado de github_get_repo y github_update_repo
+type RepositoryDetail struct {
+	FullName      string   `json:"fullName"`
+	Description   string   `json:"description"`
+	Homepage      string   `json:"homepage,omitempty"`
+	URL           string   `json:"url"`
+	CloneURL      string   `json:"cloneUrl"`
+	SSHURL        string   `json:"sshUrl"`
+	Visibility    string   `json:"visibility"`
+	Private       bool     `json:"private"`
+	Fork          bool     `json:"fork"`
+	Parent        string   `json:"parent,omitempty"`   // repositorio del que es fork
+	Template      string   `json:"template,omitempty"` // plantilla de la que se creó
+	IsTemplate    bool     `json:"isTemplate"`
+	Archived      bool     `json:"archived"`
+	DefaultBranch string   `json:"defaultBranch"`
+	Language      string   `json:"language"`
+	License       string   `json:"license,omitempty"` // identificador SPDX
+	Topics        []string `json:"topics"`
+	Stars         int      `json:"stars"`
+	Forks         int      `json:"forks"`
+	Watchers      int      `json:"watchers"`
+	OpenIssues    int      `json:"openIssues"` // issues y PRs abiertos
+	SizeKB        int      `json:"sizeKb"`
+
+	HasIssues      bool `json:"hasIssues"`
+	HasWiki        bool `json:"hasWiki"`
+	HasProjects    bool `json:"hasProjects"`
+	HasDiscussions bool `json:"hasDiscussions"`
+
+	MergeSettings MergeSettings   `json:"mergeSettings"`
+	Permissions   map[string]bool `json:"permissions,omitempty"` // del usuario autenticado: admin, maintain, push, triage, pull
+
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+	PushedAt  string `json:"pushedAt"`
+}
+
+func (r *RepositoryDetail) String() string {
+	output, _ := json.MarshalIndent(r, "", "  ")
+	return string(output)
+}
+
+// RepositoryDeleted es el repositorio borrado
+type RepositoryDeleted struct {
+	FullName string `json:"fullName"`
+}
+
+func (r *RepositoryDeleted) String() string {
+	return fmt.Sprintf("Repository %s deleted", r.FullName)
+}
+
+// RepositoryTopics son los topics de un repositorio tras sustituirlos
+type RepositoryTopics struct {
+	FullName string   `json:"fullName"`
+	Topics   []string `json:"topics"`
+}
+
+func (r *RepositoryTopics) String() string {
+	if len(r.Topics) == 0 {
+		return fmt.Sprintf("Topics of %s removed", r.FullName)
+	}
+	return fmt.Sprintf("Topics of %s: %s", r.FullName, strings.Join(r.Topics, ", "))
+}
+
+// RepositoryTransfer es la transferencia solicitada de un repositorio
+type RepositoryTransfer struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	URL  string `json:"url,omitempty"`
+}
+
+func (r *RepositoryTransfer) String() string {
+	return fmt.Sprintf("Transfer of %s to %s requested (a personal account owner must accept it by email)", r.From, r.To)
+}
+
+// RateBucket es el cupo de un recurso de la API (core, search, graphql...)
+type RateBucket struct {
+	Resource  string `json:"resource"`
+	Limit     int    `json:"limit"`
+	Remaining int    `json:"remaining"`
+	Used      int    `json:"used"`
+	Reset     string `json:"reset"` // momento en que se renueva el cupo
+}
+
+// RateLimitReport es el resultado de github_rate_limit
+type RateLimitReport struct {
+	Resources []RateBucket `json:"resources"`
+}
+
+func (r *RateLimitReport) String() string {
+	var b strings.Builder
+	for _, bucket := range r.Resources {
+		fmt.Fprintf(&b, "%s: %d/%d remaining (resets at %s)\n", bucket.Resource, bucket.Remaining, bucket.Limit, bucket.Reset)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// PullRequestSummary es un pull request en un listado
+type PullRequestSummary struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	URL    string `json:"url"`
+	User   string `json:"user"`
+	Head   string `json:"head"`
+	Base   string `json:"base"`
+}
+
+// PullRequestList es el resultado de github_list_prs
+type PullRequestList struct {
+	PullRequests []PullRequestSummary `json:"pullRequests"`
+	NextCursor   string               `json:"nextCursor,omitempty"`
+}
+
+func (r *PullRequestList) String() string {
+	return listText(r.PullRequests, r.NextCursor)
+}
+
+// PullRequestCreated es el pull request recién creado
+type PullRequestCreated struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+}
+
+func (r *PullRequestCreated) String() string {
+	return fmt.Sprintf("Pull Request #%d created: %s", r.Number, r.URL)
+}
+
+// PullRequestDetail es un pull request con su detalle
+type PullRequestDetail struct {
+	Number    int    `json:"number"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	State     string `json:"state"`
+	Draft     bool   `json:"draft"`
+	Merged    bool   `json:"merged"`
+	URL       string `json:"url"`
+	User      string `json:"user"`
+	Head      string `json:"head"`
+	Base      string `json:"base"`
+	Commits   int    `json:"commits"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+
+	HeadSHA            string         `json:"headSha"`
+	Mergeable          *bool          `json:"mergeable"`      // null mientras GitHub lo calcula
+	MergeableState     string         `json:"mergeableState"` // clean, blocked, behind, dirty, unstable...
+	RequestedReviewers []string       `json:"requestedReviewers"`
+	RequestedTeams     []string       `json:"requestedTeams"`
+	Checks             *ChecksSummary `json:"checks,omitempty"`
+	ChecksError        string         `json:"checksError,omitempty"` // p. ej. token sin permiso de lectura de checks
+}
+
+func (r *PullRequestDetail) String() string {
+	output, _ := json.MarshalIndent(r, "", "  ")
+	return string(output)
+}
+
+// ChecksSummary resume los check runs y commit statuses de una ref
+type ChecksSummary struct {
+	Ref     string `json:"ref"`
+	State   string `json:"state"` // success, failure, pending o none
+	Total   int    `json:"total"`
+	Success int    `json:"success"`
+	Failure int    `json:"failure"`
+	Pending int    `json:"pending"`
+	Skipped int    `json:"skipped"`
+}
+
+// ChecksReport es el detalle de los checks de una ref
+type ChecksReport struct {
+	Summary   *ChecksSummary     `json:"summary"`
+	SHA       string             `json:"sha"`
+	CheckRuns []CheckRunInfo     `json:"checkRuns"`
+	Suites    []CheckSuiteInfo   `json:"suites"`
+	Statuses  []CommitStatusInfo `json:"statuses"`
+}
+
+func (r *ChecksReport) String() string {
+	output, _ := json.MarshalIndent(r, "", "  ")
+	return string(output)
+}
+
+// CheckRunInfo es un check run con su salida y, si se piden, sus anotaciones
+type CheckRunInfo struct {
+	ID               int64             `json:"id"`
+	Name             string            `json:"name"`
+	App              string            `json:"app"`
+	Status           string            `json:"status"`
+	Conclusion       string            `json:"conclusion,omitempty"`
+	Title            string            `json:"title,omitempty"`
+	Summary          string            `json:"summary,omitempty"`
+	URL              string            `json:"url"`
+	AnnotationsCount int               `json:"annotationsCount"`
+	Annotations      []CheckAnnotation `json:"annotations,omitempty"`
+}
+
+func (r *CheckRunInfo) String() string {
+	return fmt.Sprintf("Check run %q %s %s with %d annotation(s): %s", r.Name, r.Status, r.Conclusion, len(r.Annotations), r.URL)
+}
+
+// CheckAnnotation es una anotación de un check run sobre un archivo y rango de líneas
+type CheckAnnotation struct {
+	Path        string `json:"path"`                // relativa a la raíz del repositorio
+	LocalPath   string `json:"localPath,omitempty"` // el archivo en el workspace local, si existe
+	StartLine   int    `json:"startLine"`
+	EndLine     int    `json:"endLine"`
+	StartColumn int    `json:"startColumn,omitempty"`
+	EndColumn   int    `json:"endColumn,omitempty"`
+	Level       string `json:"level"` // notice, warning o failure
+	Title       string `json:"title,omitempty"`
+	Message     string `json:"message"`
+}
+
+// CheckSuiteInfo es una check suite (un conjunto de check runs de una App)
+type CheckSuiteInfo struct {
+	ID         int64  `json:"id"`
+	App        string `json:"app"`
+	Branch     string `json:"branch,omitempty"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion,omitempty"`
+}
+
+// CommitStatusInfo es un commit status (API de statuses, anterior a los checks)
+type CommitStatusInfo struct {
+	Context     string `json:"context"`
+	State       string `json:"state"` // success, failure, error o pending
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url,omitempty"`
+	SHA         string `json:"sha,omitempty"`
+}
+
+func (r *CommitStatusInfo) String() string {
+	return fmt.Sprintf("Status %q set to %s on %s", r.Context, r.State, r.SHA)
+}
+
+// PullRequestFile es un archivo modificado por un pull request
+type PullRequestFile struct {
+	Path         string `json:"path"`
+	PreviousPath string `json:"previousPath,omitempty"` // en archivos renombrados
+	Status       string `json:"status"`                 // added, modified, removed, renamed...
+	Additions    int    `json:"additions"`
+	Deletions    int    `json:"deletions"`
+	Patch        string `json:"patch"` // vacío en archivos binarios o diffs muy grandes
+}
+
+// PullRequestFileList es el resultado de github_pr_files
+type PullRequestFileList struct {
+	Number     int               `json:"number"`
+	Files      []PullRequestFile `json:"files"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+}
+
+func (r *PullRequestFileList) String() string {
+	return listText(r.Files, r.NextCursor)
+}
+
+// PullRequestDiff es el diff unificado de un pull request
+type PullRequestDiff struct {
+	Number int    `json:"number"`
+	Diff   string `json:"diff"`
+}
+
+func (r *PullRequestDiff) String() string {
+	return r.Diff
+}
+
+// ReviewCreated es la revisión recién creada
+type ReviewCreated struct {
+	Number   int    `json:"number"`
+	ID       int64  `json:"id"`
+	State    string `json:"state"` // APPROVED, CHANGES_REQUESTED, COMMENTED o PENDING
+	URL      string `json:"url"`
+	Comments int    `json:"comments"`
+}
+
+func (r *ReviewCreated) String() string {
+	return fmt.Sprintf("Review %s on #%d with %d inline comment(s): %s", r.State, r.Number, r.Comments, r.URL)
+}
+
+// ReviewComment es un comentario en línea de una revisión
+type ReviewComment struct {
+	ID        int64  `json:"id"`
+	InReplyTo int64  `json:"inReplyTo,omitempty"` // comentario raíz del hilo
+	ReviewID  int64  `json:"reviewId"`
+	User      string `json:"user"`
+	Path      string `json:"path"`
+	Line      int    `json:"line"` // 0 si el comentario quedó obsoleto (outdated)
+	Side      string `json:"side"`
+	StartLine int    `json:"startLine,omitempty"`
+	CommitID  string `json:"commitId"`
+	Body      string `json:"body"`
+	URL       string `json:"url"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// ReviewCommentList es el resultado de github_list_review_comments
+type ReviewCommentList struct {
+	Number     int             `json:"number"`
+	Comments   []ReviewComment `json:"comments"`
+	NextCursor string          `json:"nextCursor,omitempty"`
+}
+
+func (r *ReviewCommentList) String() string {
+	return listText(r.Comments, r.NextCursor)
+}
+
+// ReviewersRequested son los revisores pendientes tras solicitar revisión
+type ReviewersRequested struct {
+	Number    int      `json:"number"`
+	Reviewers []string `json:"reviewers"`
+	Teams     []string `json:"teams"`
+}
+
+func (r *ReviewersRequested) String() string {
+	return fmt.Sprintf("Review requested on #%d. Pending reviewers: %s; teams: %s",
+		r.Number, strings.Join(r.Reviewers, ", "), strings.Join(r.Teams, ", "))
+}
+
+// FileCommit es el commit generado al crear o actualizar un archivo por la API
+type FileCommit struct {
+	Path      string `json:"path"`
+	Action    string `json:"action"` // "created" o "updated"
+	Branch    string `json:"branch"`
+	SHA       string `json:"sha"`       // SHA del nuevo blob
+	CommitSHA string `json:"commitSha"` // SHA del commit
+}
+
+func (r *FileCommit) String() string {
+	return fmt.Sprintf("File '%s' %s successfully via API. Commit SHA: %s", r.Path, r.Action, r.CommitSHA)
+}
+
+// BranchSummary es una rama en un listado
+type BranchSummary struct {
+	Name      string `json:"name"`
+	SHA       string `json:"sha"`
+	Protected bool   `json:"protected"`
+}
+
+// BranchList es el resultado de github_list_branches
+type BranchList struct {
+	Branches   []BranchSummary `json:"branches"`
+	NextCursor string          `json:"nextCursor,omitempty"`
+}
+
+func (r *BranchList) String() string {
+	return listText(r.Branches, r.NextCursor)
+}
+
+// BranchRef es la rama creada o borrada por la API
+type BranchRef struct {
+	Name   string `json:"name"`
+	SHA    string `json:"sha"`
+	From   string `json:"from,omitempty"` // rama o SHA de partida de una rama creada
+	Action string `json:"action"`         // created o deleted
+}
+
+func (r *BranchRef) String() string {
+	if r.From != "" && r.From != r.SHA {
+		return fmt.Sprintf("Branch %s %s from %s at %s", r.Name, r.Action, r.From, r.SHA)
+	}
+	return fmt.Sprintf("Branch %s %s at %s", r.Name, r.Action, r.SHA)
+}
+
+// CompareCommit es un commit de una comparación
+type CompareCommit struct {
+	SHA     string `json:"sha"`
+	Message string `json:"message"` // primera línea
+	Author  string `json:"author"`
+	Date    string `json:"date"`
+}
+
+// Comparison es el resultado de github_compare
+type Comparison struct {
+	Base         string            `json:"base"`
+	Head         string            `json:"head"`
+	Status       string            `json:"status"` // ahead, behind, diverged o identical
+	AheadBy      int               `json:"aheadBy"`
+	BehindBy     int               `json:"behindBy"`
+	TotalCommits int               `json:"totalCommits"`
+	MergeBaseSHA string            `json:"mergeBaseSha"`
+	URL          string            `json:"url"`
+	Commits      []CompareCommit   `json:"commits"`
+	Files        []PullRequestFile `json:"files"`
+}
+
+func (r *Comparison) String() string {
+	output, _ := json.MarshalIndent(r, "", "  ")
+	return string(output)
+}
+
+// RequiredReviews es la revisión obligatoria de una rama protegida
+type RequiredReviews struct {
+	Approvals        int  `json:"approvals"`
+	DismissStale     bool `json:"dismissStale"`
+	CodeOwners       bool `json:"codeOwners"`
+	LastPushApproval bool `json:"lastPushApproval"`
+}
+
+// RequiredStatusChecks son los checks obligatorios de una rama protegida
+type RequiredStatusChecks struct {
+	Strict bool     `json:"strict"` // la rama debe estar al día con la base
+	Checks []string `json:"checks"`
+}
+
+// Actors son los usuarios, equipos y apps de una restricción
+type Actors struct {
+	Users []string `json:"users"`
+	Teams []string `json:"teams"`
+	Apps  []string `json:"apps"`
+}
+
+// BranchRule es una regla de un ruleset que se aplica a la rama
+type BranchRule struct {
+	Type          string          `json:"type"` // pull_request, required_status_checks, non_fast_forward...
+	RulesetID     int64           `json:"rulesetId"`
+	RulesetSource string          `json:"rulesetSource"` // repositorio u organización del ruleset
+	Parameters    json.RawMessage `json:"parameters,omitempty"`
+}
+
+// BranchProtection es el resultado de github_get_branch_protection
+type BranchProtection struct {
+	Branch                        string                `json:"branch"`
+	Protected                     bool                  `json:"protected"` // tiene protección clásica
+	RequiredReviews               *RequiredReviews      `json:"requiredReviews,omitempty"`
+	RequiredStatusChecks          *RequiredStatusChecks `json:"requiredStatusChecks,omitempty"`
+	PushRestrictions              *Actors               `json:"pushRestrictions,omitempty"`
+	EnforceAdmins                 bool                  `json:"enforceAdmins"`
+	RequireLinearHistory          bool                  `json:"requireLinearHistory"`
+	AllowForcePushes              bool                  `json:"allowForcePushes"`
+	AllowDeletions                bool                  `json:"allowDeletions"`
+	RequireConversationResolution bool                  `json:"requireConversationResolution"`
+	LockBranch                    bool                  `json:"lockBranch"`
+	Rules                         []BranchRule          `json:"rules"` // reglas de rulesets activos
+}
+
+func (r *BranchProtection) String() string {
+	output, _ := json.MarshalIndent(r, "", "  ")
+	return string(output)
+}
+
+// RemoteFile es un archivo leído de un repositorio remoto
+type RemoteFile struct {
+	Path     string `json:"path"`
+	Type     string `json:"type"` // file, symlink o submodule
+	SHA      string `json:"sha"`  // SHA del blob: el 'sha' que pide update_file
+	Size     int    `json:"size"`
+	Encoding string `json:"encoding"` // utf-8 o base64 (contenido binario)
+	Content  string `json:"content"`
+	URL      string `json:"url"`
+}
+
+func (r *RemoteFile) String() string {
+	return r.Content
+}
+
+// DirectoryEntry es un elemento de un directorio remoto
+type DirectoryEntry struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Type string `json:"type"` // file, dir, symlink o submodule
+	Size int    `json:"size"`
+	SHA  string `json:"sha"`
+}
+
+// DirectoryListing es el resultado de github_list_directory
+type DirectoryListing struct {
+	Path    string           `json:"path"`
+	Entries []DirectoryEntry `json:"entries"`
+}
+
+func (r *DirectoryListing) String() string {
+	output, _ := json.MarshalIndent(r.Entries, "", "  ")
+	return string(output)
+}
+
+// TreeItem es una entrada de un árbol Git
+type TreeItem struct {
+	Path string `json:"path"`
+	Type string `json:"type"` // blob, tree o commit (submódulo)
+	Mode string `json:"mode"`
+	Size int    `json:"size,omitempty"`
+	SHA  string `json:"sha"`
+}
+
+// TreeListing es el resultado de github_get_tree
+type TreeListing struct {
+	Ref       string     `json:"ref"`
+	Path      string     `json:"path,omitempty"`
+	SHA       string     `json:"sha"`
+	Truncated bool       `json:"truncated"` // GitHub cortó el árbol recursivo: pedir subdirectorios por separado
+	Entries   []TreeItem `json:"entries"`
+}
+
+func (r *TreeListing) String() string {
+	var b strings.Builder
+	for _, entry := range r.Entries {
+		if entry.Type == "tree" {
+			fmt.Fprintf(&b, "%s/\n", entry.Path)
+		} else {
+			fmt.Fprintf(&b, "%s\n", entry.Path)
+		}
+	}
+	if r.Truncated {
+		b.WriteString("(truncated: list subdirectories separately)\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// FileChanged es un archivo modificado por github_commit_files
+type FileChanged struct {
+	Path     string `json:"path"`
+	FromPath string `json:"fromPath,omitempty"` // ruta original de un renombrado
+	Action   string `json:"action"`             // created, updated, deleted o renamed
+	SHA      string `json:"sha,omitempty"`      // SHA del blob (vacío si se borró)
+}
+
+// FilesCommit es el commit creado por github_commit_files
+type FilesCommit struct {
+	Branch    string        `json:"branch"`
+	CommitSHA string        `json:"commitSha"`
+	ParentSHA string        `json:"parentSha"`
+	URL       string        `json:"url"`
+	Files     []FileChanged `json:"files"`
+}
+
+func (r *FilesCommit) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Commit %s on %s with %d file(s): %s", r.CommitSHA, r.Branch, len(r.Files), r.URL)
+	for _, file := range r.Files {
+		if file.FromPath != "" {
+			fmt.Fprintf(&b, "\n  %s %s -> %s", file.Action, file.FromPath, file.Path)
+		} else {
+			fmt.Fprintf(&b, "\n  %s %s", file.Action, file.Path)
+		}
+	}
+	return b.String()
+}
+
+// IssueSummary es un issue en un listado
+type IssueSummary struct {
+	Number    int      `json:"number"`
+	Title     string   `json:"title"`
+	State     string   `json:"state"`
+	URL       string   `json:"url"`
+	User      string   `json:"user"`
+	Labels    []string `json:"labels"`
+	Assignees []string `json:"assignees"`
+	Milestone string   `json:"milestone"`
+	Comments  int      `json:"comments"`
+	CreatedAt string   `json:"createdAt"`
+	UpdatedAt string   `json:"updatedAt"`
+}
+
+// IssueList es el resultado de github_list_issues
+type IssueList struct {
+	Issues     []IssueSummary `json:"issues"`
+	NextCursor string         `json:"nextCursor,omitempty"` // vacío si no hay más
+}
+
+func (r *IssueList) String() string {
+	return listText(r.Issues, r.NextCursor)
+}
+
+// IssueComment es un comentario de un issue
+type IssueComment struct {
+	ID        int64  `json:"id"`
+	User      string `json:"user"`
+	Body      string `json:"body"`
+	URL       string `json:"url"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// IssueDetail es un issue con su cuerpo y, opcionalmente, sus comentarios
+type IssueDetail struct {
+	IssueSummary
+	Body        string         `json:"body"`
+	StateReason string         `json:"stateReason"`
+	Locked      bool           `json:"locked"`
+	LockReason  string         `json:"lockReason"`
+	ClosedAt    string         `json:"closedAt"`
+	CommentList []IssueComment `json:"commentList"`
+}
+
+func (r *IssueDetail) String() string {
+	output, _ := json.MarshalIndent(r, "", "  ")
+	return string(output)
+}
+
+// IssueCommentCreated es el comentario recién creado
+type IssueCommentCreated struct {
+	Number int    `json:"number"`
+	ID     int64  `json:"id"`
+	URL    string `json:"url"`
+}
+
+func (r *IssueCommentCreated) String() string {
+	return fmt.Sprintf("Comment added to #%d: %s", r.Number, r.URL)
+}
+
+// IssueLock es el estado de bloqueo de la conversación de un issue
+type IssueLock struct {
+	Number int    `json:"number"`
+	Locked bool   `json:"locked"`
+	Reason string `json:"reason"`
+}
+
+func (r *IssueLock) String() string {
+	if !r.Locked {
+		return fmt.Sprintf("Issue #%d unlocked", r.Number)
+	}
+	if r.Reason == "" {
+		return fmt.Sprintf("Issue #%d locked", r.Number)
+	}
+	return fmt.Sprintf("Issue #%d locked (%s)", r.Number, r.Reason)
+}
+
+// MergeResult es el resultado de github_merge_pr. Si no se pudo fusionar, Reason
+// indica el motivo (ver las constantes Reason*) y Message el texto de la API.
+type MergeResult struct {
+	Number         int            `json:"number"`
+	Merged         bool           `json:"merged"`
+	Method         string         `json:"method"`
+	SHA            string         `json:"sha,omitempty"`
+	Message        string         `json:"message"`
+	Reason         string         `json:"reason,omitempty"`
+	MergeableState string         `json:"mergeableState,omitempty"`
+	Checks         *ChecksSummary `json:"checks,omitempty"`
+}
+
+func (r *MergeResult) fail(reason, message string) *MergeResult {
+	r.Reason, r.Message = reason, message
+	return r
+}
+
+// Failed indica que la fusión se rechazó (el resultado se marca como error)
+func (r *MergeResult) Failed() bool {
+	return r.Reason != ""
+}
+
+func (r *MergeResult) String() string {
+	if r.Failed() {
+		return fmt.Sprintf("Pull Request #%d not merged (%s): %s", r.Number, r.Reason, r.Message)
+	}
+	return fmt.Sprintf("Pull Request #%d merged (%s): %s", r.Number, r.Method, r.SHA)
+}
+
+// BranchUpdateResult es el resultado de github_update_pr_branch
+type BranchUpdateResult struct {
+	Number  int    `json:"number"`
+	Updated bool   `json:"updated"`
+	Message string `json:"message"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+func (r *BranchUpdateResult) fail(reason, message string) *BranchUpdateResult {
+	r.Reason, r.Message = reason, message
+	return r
+}
+
+// Failed indica que la actualización se rechazó
+func (r *BranchUpdateResult) Failed() bool {
+	return r.Reason != ""
+}
+
+func (r *BranchUpdateResult) String() string {
+	if r.Failed() {
+		return fmt.Sprintf("Pull Request #%d branch not updated (%s): %s", r.Number, r.Reason, r.Message)
+	}
+	return fmt.Sprintf("Pull Request #%d: %s", r.Number, r.Message)
+}
+
+// AutoMergeResult es el resultado de github_enable_auto_merge
+type AutoMergeResult struct {
+	Number  int    `json:"number"`
+	Enabled bool   `json:"enabled"`
+	Method  string `json:"method"`
+	Message string `json:"message,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+func (r *AutoMergeResult) fail(reason, message string) *AutoMergeResult {
+	r.Reason, r.Message = reason, message
+	return r
+}
+
+// Failed indica que no se pudo activar la fusión automática
+func (r *AutoMergeResult) Failed() bool {
+	return r.Reason != ""
+}
+
+func (r *AutoMergeResult) String() string {
+	if r.Failed() {
+		return fmt.Sprintf("Auto-merge not enabled on #%d (%s): %s", r.Number, r.Reason, r.Message)
+	}
+	return fmt.Sprintf("Auto-merge (%s) enabled on #%d", r.Method, r.Number)
+}
+
+// PullRequestState es el estado de un pull request tras cerrarlo o reabrirlo
+type PullRequestState struct {
+	Number int    `json:"number"`
+	State  string `json:"state"`
+	Merged bool   `json:"merged"`
+	URL    string `json:"url"`
+}
+
+func (r *PullRequestState) String() string {
+	return fmt.Sprintf("Pull Request #%d is now %s: %s", r.Number, r.State, r.URL)
+}
+
+// WorkflowSummary es un workflow de GitHub Actions
+type WorkflowSummary struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+	State string `json:"state"` // active, disabled_manually...
+	URL   string `json:"url"`
+}
+
+// WorkflowList es el resultado de github_list_workflows
+type WorkflowList struct {
+	Workflows  []WorkflowSummary `json:"workflows"`
+	TotalCount int               `json:"totalCount"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+}
+
+func (r *WorkflowList) String() string {
+	return listText(r.Workflows, r.NextCursor)
+}
+
+// RunSummary es una ejecución de un workflow
+type RunSummary struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	WorkflowID int64  `json:"workflowId"`
+	RunNumber  int    `json:"runNumber"`
+	Attempt    int    `json:"attempt"`
+	Event      string `json:"event"`
+	Branch     string `json:"branch"`
+	HeadSHA    string `json:"headSha"`
+	Status     string `json:"status"`     // queued, in_progress, completed...
+	Conclusion string `json:"conclusion"` // success, failure, cancelled... (vacío si no ha terminado)
+	Actor      string `json:"actor"`
+	URL        string `json:"url"`
+	CreatedAt  string `json:"createdAt"`
+	UpdatedAt  string `json:"updatedAt"`
+}
+
+// RunList es el resultado de github_list_runs
+type RunList struct {
+	Runs       []RunSummary `json:"runs"`
+	TotalCount int          `json:"totalCount"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+}
+
+func (r *RunList) String() string {
+	return listText(r.Runs, r.NextCursor)
+}
+
+// StepSummary es un step de un job
+type StepSummary struct {
+	Number     int64  `json:"number"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+}
+
+// JobSummary es un job de una ejecución con sus steps
+type JobSummary struct {
+	ID          int64         `json:"id"`
+	Name        string        `json:"name"`
+	Status      string        `json:"status"`
+	Conclusion  string        `json:"conclusion"`
+	URL         string        `json:"url"`
+	StartedAt   string        `json:"startedAt,omitempty"`
+	CompletedAt string        `json:"completedAt,omitempty"`
+	Steps       []StepSummary `json:"steps"`
+}
+
+// RunDetail es una ejecución con sus jobs y steps
+type RunDetail struct {
+	RunSummary
+	Jobs []JobSummary `json:"jobs"`
+}
+
+func (r *RunDetail) String() string {
+	output, _ := json.MarshalIndent(r, "", "  ")
+	return string(output)
+}
+
+// LogExcerpt es el fragmento de log de un job o de uno de sus steps
+type LogExcerpt struct {
+	Job        string `json:"job"`
+	JobID      int64  `json:"jobId"`
+	Step       string `json:"step,omitempty"`
+	StepNumber int64  `json:"stepNumber,omitempty"`
+	Conclusion string `json:"conclusion"`
+	TotalLines int    `json:"totalLines"` // líneas tras aplicar grep
+	Lines      int    `json:"lines"`      // líneas devueltas tras aplicar tail
+	Content    string `json:"content"`
+}
+
+// RunLogs es el resultado de github_get_run_logs
+type RunLogs struct {
+	RunID    int64        `json:"runId"`
+	Excerpts []LogExcerpt `json:"excerpts"`
+}
+
+func (r *RunLogs) String() string {
+	var b strings.Builder
+	for i, excerpt := range r.Excerpts {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		title := excerpt.Job
+		if excerpt.Step != "" {
+			title += fmt.Sprintf(" › %d. %s", excerpt.StepNumber, excerpt.Step)
+		}
+		fmt.Fprintf(&b, "=== %s (%s) — %d of %d lines ===\n%s", title, excerpt.Conclusion, excerpt.Lines, excerpt.TotalLines, excerpt.Content)
+	}
+	if len(r.Excerpts) == 0 {
+		fmt.Fprintf(&b, "Run %d has no jobs", r.RunID)
+	}
+	return b.String()
+}
+
+// RunAction es una operación aceptada sobre una ejecución (rerun, cancel)
+type RunAction struct {
+	RunID  int64  `json:"runId"`
+	Action string `json:"action"`
+}
+
+func (r *RunAction) String() string {
+	return fmt.Sprintf("Run %d: %s requested", r.RunID, strings.ReplaceAll(r.Action, "_", " "))
+}
+
+// WorkflowDispatch es el evento workflow_dispatch enviado
+type WorkflowDispatch struct {
+	Workflow string   `json:"workflow"`
+	Ref      string   `json:"ref"`
+	Inputs   []string `json:"inputs"`
+}
+
+func (r *WorkflowDispatch) String() string {
+	return fmt.Sprintf("Workflow %s dispatched on %s (use github_list_runs with event=workflow_dispatch to follow it)", r.Workflow, r.Ref)
+}
+
+// ReleaseSummary es un release en los listados
+type ReleaseSummary struct {
+	ID          int64  `json:"id"`
+	Tag         string `json:"tag"`
+	Name        string `json:"name"`
+	Draft       bool   `json:"draft"`
+	Prerelease  bool   `json:"prerelease"`
+	Author      string `json:"author"`
+	URL         string `json:"url"`
+	CreatedAt   string `json:"createdAt"`
+	PublishedAt string `json:"publishedAt,omitempty"` // vacío en los borradores
+}
+
+// ReleaseList es el resultado de github_list_releases
+type ReleaseList struct {
+	Releases   []ReleaseSummary `json:"releases"`
+	NextCursor string           `json:"nextCursor,omitempty"` // vacío si no hay más
+}
+
+func (r *ReleaseList) String() string {
+	return listText(r.Releases, r.NextCursor)
+}
+
+// ReleaseDetail es un release con sus notas y assets
+type ReleaseDetail struct {
+	ReleaseSummary
+	Target string         `json:"target"` // rama o SHA del que se creó el tag
+	Body   string         `json:"body"`
+	Assets []ReleaseAsset `json:"assets"`
+}
+
+func (r *ReleaseDetail) String() string {
+	output, _ := json.MarshalIndent(r, "", "  ")
+	return string(output)
+}
+
+// ReleaseAsset es un archivo adjunto a un release
+type ReleaseAsset struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Label       string `json:"label,omitempty"`
+	ContentType string `json:"contentType"`
+	Size        int    `json:"size"`
+	Downloads   int    `json:"downloads"`
+	URL         string `json:"url"`
+}
+
+func (r *ReleaseAsset) String() string {
+	return fmt.Sprintf("Asset %s (%d bytes): %s", r.Name, r.Size, r.URL)
+}
+
+// SearchPage son los datos comunes de una página de resultados de búsqueda
+type SearchPage struct {
+	Total         int    `json:"total"`
+	Incomplete    bool   `json:"incomplete"`           // la búsqueda agotó su tiempo: puede faltar algún resultado
+	NextCursor    string `json:"nextCursor,omitempty"` // vacío si no hay más
+	RateRemaining int    `json:"rateRemaining"`        // búsquedas restantes en el cupo de búsqueda
+	RateReset     string `json:"rateReset"`
+}
+
+// TextMatch es un fragmento del resultado donde aparecen los términos buscados
+type TextMatch struct {
+	Property string   `json:"property"` // campo del fragmento: content, title, body...
+	Fragment string   `json:"fragment"`
+	Terms    []string `json:"terms"`
+}
+
+// CodeMatch es un archivo encontrado por github_search_code
+type CodeMatch struct {
+	Repo      string      `json:"repo"`
+	Path      string      `json:"path"`
+	SHA       string      `json:"sha"`
+	URL       string      `json:"url"`
+	Fragments []TextMatch `json:"fragments,omitempty"`
+}
+
+// CodeSearchResult es el resultado de github_search_code
+type CodeSearchResult struct {
+	SearchPage
+	Items []CodeMatch `json:"items"`
+}
+
+func (r *CodeSearchResult) String() string {
+	output, _ := json.MarshalIndent(r, "", "  ")
+	return string(output)
+}
+
+// IssueMatch es un issue o pull request encontrado por github_search_issues
+type IssueMatch struct {
+	IssueSummary
+	Repo        string      `json:"repo"`
+	PullRequest bool        `json:"pullRequest"`
+	Fragments   []TextMatch `json:"fragments,omitempty"`
+}
+
+// IssueSearchResult es el resultado de github_search_issues
+type IssueSearchResult struct {
+	SearchPage
+	Items []IssueMatch `json:"items"`
+}
+
+func (r *IssueSearchResult) String() string {
+	output, _ := json.MarshalIndent(r, "", "  ")
+	return string(output)
+}
+
+// CommitMatch es un commit encontrado por github_search_commits
+type CommitMatch struct {
+	Repo    string `json:"repo"`
+	SHA     string `json:"sha"`
+	Message string `json:"message"`
+	Author  string `json:"author"`
+	Login   string `json:"login,omitempty"` // vacío si el email no corresponde a un usuario
+	Date    string `json:"date"`
+	URL     string `json:"url"`
+}
+
+// CommitSearchResult es el resultado de github_search_commits
+type CommitSearchResult struct {
+	SearchPage
+	Items []CommitMatch `json:"items"`
+}
+
+func (r *CommitSearchResult) String() string {
+	output, _ := json.MarshalIndent(r, "", "  ")
+	return string(output)
+}
+
+// RepoMatch es un repositorio encontrado por github_search_repos
+type RepoMatch struct {
+	FullName    string      `json:"fullName"`
+	Description string      `json:"description"`
+	Private     bool        `json:"private"`
+	Archived    bool        `json:"archived"`
+	Language    string      `json:"language"`
+	Stars       int         `json:"stars"`
+	Topics      []string    `json:"topics"`
+	URL         string      `json:"url"`
+	UpdatedAt   string      `json:"updatedAt"`
+	Fragments   []TextMatch `json:"fragments,omitempty"`
+}
+
+// RepoSearchResult es el resultado de github_search_repos
+type RepoSearchResult struct {
+	SearchPage
+	Items []RepoMatch `json:"items"`
+}
+
+func (r *RepoSearchResult) String() string {
+	output, _ := json.MarshalIndent(r, "", "  ")
+	return string(output)
+}
+
+// UserMatch es un usuario u organización encontrado por github_search_users
+type UserMatch struct {
+	Login     string      `json:"login"`
+	Type      string      `json:"type"` // User u Organization
+	URL       string      `json:"url"`
+	Fragments []TextMatch `json:"fragments,omitempty"`
+}
+
+// UserSearchResult es el resultado de github_search_users
+type UserSearchResult struct {
+	SearchPage
+	Items []UserMatch `json:"items"`
+}
+
+func (r *UserSearchResult) String() string {
+	output, _ := json.MarshalIndent(r, "", "  ")
+	return string(output)
+}
@@ -0,0 +1,245 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// maxSearchWait es lo máximo que se espera a que se renueve el cupo de búsqueda (se
+// renueva cada minuto) antes de devolver el error de límite
+const maxSearchWait = 65 * time.Second
+
+// SearchOptions son las opciones comunes de las búsquedas
+type SearchOptions struct {
+	Sort  string // depende del tipo de búsqueda (stars, updated, author-date...)
+	Order string // asc o desc
+	PageOptions
+}
+
+// SearchCode busca código. La búsqueda de código tiene su propio cupo (10 por minuto).
+func SearchCode(client *github.Client, ctx context.Context, query string, opts SearchOptions) (*CodeSearchResult, error) {
+	result := &CodeSearchResult{}
+	items, next, err := paginate(opts.PageOptions, func(page github.ListOptions) ([]CodeMatch, *github.Response, error) {
+		found, resp, err := search(ctx, func() (*github.CodeSearchResult, *github.Response, error) {
+			return client.Search.Code(ctx, query, opts.request(page))
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		result.SearchPage = searchPage(found.GetTotal(), found.GetIncompleteResults(), resp)
+		matches := make([]CodeMatch, 0, len(found.CodeResults))
+		for _, item := range found.CodeResults {
+			matches = append(matches, CodeMatch{
+				Repo:      item.GetRepository().GetFullName(),
+				Path:      item.GetPath(),
+				SHA:       item.GetSHA(),
+				URL:       item.GetHTMLURL(),
+				Fragments: textMatches(item.TextMatches),
+			})
+		}
+		return matches, resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Items, result.NextCursor = items, next
+	return result, nil
+}
+
+// SearchIssues busca issues y pull requests (is:pr, is:issue, repo:, label:, author:...)
+func SearchIssues(client *github.Client, ctx context.Context, query string, opts SearchOptions) (*IssueSearchResult, error) {
+	result := &IssueSearchResult{}
+	items, next, err := paginate(opts.PageOptions, func(page github.ListOptions) ([]IssueMatch, *github.Response, error) {
+		found, resp, err := search(ctx, func() (*github.IssuesSearchResult, *github.Response, error) {
+			return client.Search.Issues(ctx, query, opts.request(page))
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		result.SearchPage = searchPage(found.GetTotal(), found.GetIncompleteResults(), resp)
+		matches := make([]IssueMatch, 0, len(found.Issues))
+		for _, issue := range found.Issues {
+			matches = append(matches, IssueMatch{
+				IssueSummary: issueSummary(issue),
+				Repo:         repoFromURL(issue.GetRepositoryURL()),
+				PullRequest:  issue.IsPullRequest(),
+				Fragments:    textMatches(issue.TextMatches),
+			})
+		}
+		return matches, resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Items, result.NextCursor = items, next
+	return result, nil
+}
+
+// SearchCommits busca commits (author:, committer-date:, repo:, merge:...)
+func SearchCommits(client *github.Client, ctx context.Context, query string, opts SearchOptions) (*CommitSearchResult, error) {
+	result := &CommitSearchResult{}
+	items, next, err := paginate(opts.PageOptions, func(page github.ListOptions) ([]CommitMatch, *github.Response, error) {
+		found, resp, err := search(ctx, func() (*github.CommitsSearchResult, *github.Response, error) {
+			return client.Search.Commits(ctx, query, opts.request(page))
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		result.SearchPage = searchPage(found.GetTotal(), found.GetIncompleteResults(), resp)
+		matches := make([]CommitMatch, 0, len(found.Commits))
+		for _, item := range found.Commits {
+			commit := item.GetCommit()
+			matches = append(matches, CommitMatch{
+				Repo:    item.GetRepository().GetFullName(),
+				SHA:     item.GetSHA(),
+				Message: commit.GetMessage(),
+				Author:  commit.GetAuthor().GetName(),
+				Login:   item.GetAuthor().GetLogin(),
+				Date:    commit.GetAuthor().GetDate().Format(time.RFC3339),
+				URL:     item.GetHTMLURL(),
+			})
+		}
+		return matches, resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Items, result.NextCursor = items, next
+	return result, nil
+}
+
+// SearchRepositories busca repositorios (language:, stars:, topic:, org:...)
+func SearchRepositories(client *github.Client, ctx context.Context, query string, opts SearchOptions) (*RepoSearchResult, error) {
+	result := &RepoSearchResult{}
+	items, next, err := paginate(opts.PageOptions, func(page github.ListOptions) ([]RepoMatch, *github.Response, error) {
+		found, resp, err := search(ctx, func() (*github.RepositoriesSearchResult, *github.Response, error) {
+			return client.Search.Repositories(ctx, query, opts.request(page))
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		result.SearchPage = searchPage(found.GetTotal(), found.GetIncompleteResults(), resp)
+		matches := make([]RepoMatch, 0, len(found.Repositories))
+		for _, repo := range found.Repositories {
+			matches = append(matches, RepoMatch{
+				FullName:    repo.GetFullName(),
+				Description: repo.GetDescription(),
+				Private:     repo.GetPrivate(),
+				Archived:    repo.GetArchived(),
+				Language:    repo.GetLanguage(),
+				Stars:       repo.GetStargazersCount(),
+				Topics:      repo.Topics,
+				URL:         repo.GetHTMLURL(),
+				UpdatedAt:   repo.GetUpdatedAt().Format(time.RFC3339),
+				Fragments:   textMatches(repo.TextMatches),
+			})
+		}
+		return matches, resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Items, result.NextCursor = items, next
+	return result, nil
+}
+
+// SearchUsers busca usuarios y organizaciones (type:org, location:, followers:...)
+func SearchUsers(client *github.Client, ctx context.Context, query string, opts SearchOptions) (*UserSearchResult, error) {
+	result := &UserSearchResult{}
+	items, next, err := paginate(opts.PageOptions, func(page github.ListOptions) ([]UserMatch, *github.Response, error) {
+		found, resp, err := search(ctx, func() (*github.UsersSearchResult, *github.Response, error) {
+			return client.Search.Users(ctx, query, opts.request(page))
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		result.SearchPage = searchPage(found.GetTotal(), found.GetIncompleteResults(), resp)
+		matches := make([]UserMatch, 0, len(found.Users))
+		for _, user := range found.Users {
+			matches = append(matches, UserMatch{
+				Login:     user.GetLogin(),
+				Type:      user.GetType(),
+				URL:       user.GetHTMLURL(),
+				Fragments: textMatches(user.TextMatches),
+			})
+		}
+		return matches, resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Items, result.NextCursor = items, next
+	return result, nil
+}
+
+// search ejecuta una búsqueda respetando su cupo propio: si está agotado y se renueva
+// pronto, espera a la renovación y lo reintenta una vez
+func search[T any](ctx context.Context, call func() (T, *github.Response, error)) (T, *github.Response, error) {
+	result, resp, err := call()
+
+	var wait time.Duration
+	var rateErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	switch {
+	case errors.As(err, &rateErr):
+		wait = time.Until(rateErr.Rate.Reset.Time) + time.Second
+	case errors.As(err, &abuseErr):
+		wait = abuseErr.GetRetryAfter()
+	default:
+		return result, resp, err
+	}
+	if wait > maxSearchWait {
+		return result, resp, fmt.Errorf("search rate limit exceeded, retry after %s: %w", wait.Round(time.Second), err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return result, resp, ctx.Err()
+	case <-time.After(wait):
+	}
+	return call()
+}
+
+func (o SearchOptions) request(page github.ListOptions) *github.SearchOptions {
+	return &github.SearchOptions{Sort: o.Sort, Order: o.Order, TextMatch: true, ListOptions: page}
+}
+
+func searchPage(total int, incomplete bool, resp *github.Response) SearchPage {
+	return SearchPage{
+		Total:         total,
+		Incomplete:    incomplete,
+		RateRemaining: resp.Rate.Remaining,
+		RateReset:     resp.Rate.Reset.Format(time.RFC3339),
+	}
+}
+
+func textMatches(matches []*github.TextMatch) []TextMatch {
+	result := []TextMatch{}
+	for _, match := range matches {
+		terms := []string{}
+		for _, m := range match.Matches {
+			terms = append(terms, m.GetText())
+		}
+		result = append(result, TextMatch{Property: match.GetProperty(), Fragment: match.GetFragment(), Terms: terms})
+	}
+	return result
+}
+
+// repoFromURL extrae "owner/repo" de la URL de API de un repositorio
+func repoFromURL(apiURL string) string {
+	_, rest, ok := strings.Cut(apiURL, "/repos/")
+	if !ok {
+		return ""
+	}
+	return rest
+}
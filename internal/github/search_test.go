@@ -0,0 +1,113 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// searchServer simula una búsqueda de issues con n resultados (1..n) paginados con Link
+// y guarda la consulta y la página de cada petición
+type searchServer struct {
+	mu       sync.Mutex
+	n        int
+	requests []string // "q page per_page"
+}
+
+func (s *searchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/api/v3/search/issues" {
+		http.NotFound(w, r)
+		return
+	}
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	perPage, _ := strconv.Atoi(query.Get("per_page"))
+	s.mu.Lock()
+	s.requests = append(s.requests, fmt.Sprintf("%s %d %d", query.Get("q"), page, perPage))
+	s.mu.Unlock()
+
+	if page*perPage < s.n {
+		w.Header().Set("Link", fmt.Sprintf(`<%s?page=%d&per_page=%d>; rel="next"`, r.URL.Path, page+1, perPage))
+	}
+	w.Header().Set("X-RateLimit-Remaining", "29")
+	w.Header().Set("X-RateLimit-Reset", "1700000000")
+	items := ""
+	for i := (page-1)*perPage + 1; i <= min(page*perPage, s.n); i++ {
+		if items != "" {
+			items += ","
+		}
+		items += fmt.Sprintf(`{"number":%d,"title":"issue %d","state":"open","repository_url":"https://api.github.com/repos/o/r"}`, i, i)
+	}
+	fmt.Fprintf(w, `{"total_count":%d,"incomplete_results":false,"items":[%s]}`, s.n, items)
+}
+
+func TestSearchPagination(t *testing.T) {
+	server := &searchServer{n: 25}
+	client := newTestClient(t, server)
+	ctx := context.Background()
+	numbers := func(result *IssueSearchResult) []int {
+		list := []int{}
+		for _, item := range result.Items {
+			list = append(list, item.Number)
+		}
+		return list
+	}
+
+	// 12 resultados en páginas de 5: dos páginas completas y dos de la tercera
+	first, err := SearchIssues(client, ctx, "is:issue repo:o/r", SearchOptions{Order: "asc", PageOptions: PageOptions{PerPage: 5, MaxItems: 12}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := numbers(first); !reflect.DeepEqual(got, seq(1, 12)) {
+		t.Errorf("first call = %v, want 1..12", got)
+	}
+	if first.Total != 25 || first.RateRemaining != 29 || first.NextCursor == "" {
+		t.Errorf("first page = %+v, cursor %q", first.SearchPage, first.NextCursor)
+	}
+	if first.Items[0].Repo != "o/r" {
+		t.Errorf("repo = %q, want o/r", first.Items[0].Repo)
+	}
+
+	// El cursor continúa en el elemento 13, repitiendo la página 3 con su tamaño original
+	rest, err := SearchIssues(client, ctx, "is:issue repo:o/r", SearchOptions{PageOptions: PageOptions{Cursor: first.NextCursor, MaxItems: 100}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := numbers(rest); !reflect.DeepEqual(got, seq(13, 25)) {
+		t.Errorf("continuation = %v, want 13..25", got)
+	}
+	if rest.NextCursor != "" {
+		t.Errorf("last page cursor = %q, want none", rest.NextCursor)
+	}
+
+	want := []string{
+		"is:issue repo:o/r 1 5", "is:issue repo:o/r 2 5", "is:issue repo:o/r 3 5",
+		"is:issue repo:o/r 3 5", "is:issue repo:o/r 4 5", "is:issue repo:o/r 5 5",
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if !reflect.DeepEqual(server.requests, want) {
+		t.Errorf("requests = %q, want %q", server.requests, want)
+	}
+
+	if _, err := SearchIssues(client, ctx, "bug", SearchOptions{PageOptions: PageOptions{Cursor: "***"}}); err == nil {
+		t.Error("invalid cursor: no error")
+	}
+}
+
+func TestRepoFromURL(t *testing.T) {
+	for url, want := range map[string]string{
+		"https://api.github.com/repos/o/r":               "o/r",
+		"https://ghe.example.com/api/v3/repos/acme/site": "acme/site",
+		"https://api.github.com/users/o":                 "",
+		"":                                               "",
+	} {
+		if got := repoFromURL(url); got != want {
+			t.Errorf("repoFromURL(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
@@ -12,16 +12,94 @@ import (
 	"github.com/jotajotape/github-go-server-mcp/internal/types"
 )
 
-// SmartCreateFile: PRIORIZA Git local, fallback a GitHub API solo si es necesario
-func SmartCreateFile(gitConfig types.GitConfig, client *github.Client, args map[string]interface{}) (string, error) {
-	path, ok := args["path"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'path' requerido")
+// FileRequest son los parámetros de create_file/update_file.
+// Owner, Repo, SHA y Branch solo se usan si hay que recurrir a la GitHub API.
+type FileRequest struct {
+	Path    string
+	Content string
+	Message string
+	Owner   string
+	Repo    string
+	SHA     string
+	Branch  string
+}
+
+// Modos de operación de las herramientas híbridas
+const (
+	ModeGitLocal  = "git_local"
+	ModeGitHubAPI = "github_api"
+)
+
+// FileResult es el resultado de create_file/update_file
+type FileResult struct {
+	Path          string `json:"path"`
+	Action        string `json:"action"`                  // "created" o "updated"
+	Mode          string `json:"mode"`                    // git_local o github_api
+	CommitMessage string `json:"commitMessage,omitempty"` // Git local: mensaje sugerido para git_commit
+	Branch        string `json:"branch,omitempty"`        // GitHub API: rama del commit
+	SHA           string `json:"sha,omitempty"`           // GitHub API: SHA del nuevo blob
+	CommitSHA     string `json:"commitSha,omitempty"`     // GitHub API: SHA del commit
+	text          string
+}
+
+func (r *FileResult) String() string {
+	return r.text
+}
+
+// ContextResult es el contexto detectado por git_context
+type ContextResult struct {
+	Mode     string `json:"mode"`
+	RepoPath string `json:"repoPath,omitempty"`
+	Branch   string `json:"branch,omitempty"`
+	Remote   string `json:"remote,omitempty"`
+}
+
+func (r *ContextResult) String() string {
+	if r.Mode == ModeGitLocal {
+		return fmt.Sprintf(`🔧 MODO GIT LOCAL DETECTADO (OPTIMIZACIÓN DE TOKENS)
+📁 Repo: %s
+🌿 Rama: %s
+🔗 Remote: %s
+
+✅ RECOMENDACIÓN: Usar comandos git_* para operaciones sin costo de tokens
+- create_file/update_file: 0 tokens (Git local)
+- git_add + git_commit: 0 tokens
+- git_push: Solo si necesario sincronizar
+
+❌ EVITAR: github_* APIs a menos que sea estrictamente necesario`, 
+			r.RepoPath, r.Branch, r.Remote)
+	}
+
+	return `⚠️ MODO GITHUB API (COSTO TOKENS)
+❌ No se detectó Git local o repositorio Git
+📡 Usando GitHub API (consume tokens)
+
+💡 OPTIMIZACIÓN: Clona el repo localmente para reducir costos`
+}
+
+// localFileResult construye el resultado de una escritura con Git local
+func localFileResult(path, action, message, text string) *FileResult {
+	return &FileResult{Path: path, Action: action, Mode: ModeGitLocal, CommitMessage: message, text: text}
+}
+
+// apiFileResult construye el resultado de un commit hecho con la GitHub API
+func apiFileResult(commit *githubapi.FileCommit, text string) *FileResult {
+	return &FileResult{
+		Path:      commit.Path,
+		Action:    commit.Action,
+		Mode:      ModeGitHubAPI,
+		Branch:    commit.Branch,
+		SHA:       commit.SHA,
+		CommitSHA: commit.CommitSHA,
+		text:      text,
 	}
+}
 
-	content, ok := args["content"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'content' requerido")
+// SmartCreateFile: PRIORIZA Git local, fallback a GitHub API solo si es necesario
+func SmartCreateFile(ctx context.Context, gitConfig types.GitConfig, client *github.Client, req FileRequest) (*FileResult, error) {
+	path, content := req.Path, req.Content
+	if path == "" {
+		return nil, fmt.Errorf("parámetro 'path' requerido")
 	}
 
 	// 1. SIEMPRE intentar Git local primero (OPTIMIZACIÓN DE TOKENS)
@@ -29,31 +107,25 @@ func SmartCreateFile(gitConfig types.GitConfig, client *github.Client, args map[
 		result, err := git.CreateFile(gitConfig, path, content)
 		if err == nil {
 			message := fmt.Sprintf("Add %s", path)
-			if m, ok := args["message"].(string); ok {
-				message = m
+			if req.Message != "" {
+				message = req.Message
 			}
 
-			return fmt.Sprintf("✅ ARCHIVO CREADO CON GIT LOCAL (0 tokens API)\n%s\n\n🔧 Siguiente paso: git_add('%s') -> git_commit('%s')", 
-				result, path, message), nil
+			return localFileResult(path, "created", message, fmt.Sprintf("✅ ARCHIVO CREADO CON GIT LOCAL (0 tokens API)\n%s\n\n🔧 Siguiente paso: git_add('%s') -> git_commit('%s')", 
+				result, path, message)), nil
 		}
-		// Si falla Git local, continuar con API
-		return fmt.Sprintf("⚠️ Git local falló: %v\n⤵️ Intentando GitHub API...", err), fmt.Errorf("git_local_failed")
+		return nil, fmt.Errorf("⚠️ Git local falló: %v", err)
 	}
 
 	// 2. Solo si NO hay Git local, usar GitHub API
-	return createFileWithAPI(client, args)
+	return createFileWithAPI(ctx, client, req)
 }
 
 // SmartUpdateFile: PRIORIZA Git local, fallback a GitHub API solo si es necesario  
-func SmartUpdateFile(gitConfig types.GitConfig, client *github.Client, args map[string]interface{}) (string, error) {
-	path, ok := args["path"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'path' requerido")
-	}
-
-	content, ok := args["content"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'content' requerido")
+func SmartUpdateFile(ctx context.Context, gitConfig types.GitConfig, client *github.Client, req FileRequest) (*FileResult, error) {
+	path, content := req.Path, req.Content
+	if path == "" {
+		return nil, fmt.Errorf("parámetro 'path' requerido")
 	}
 
 	// 1. SIEMPRE intentar Git local primero (OPTIMIZACIÓN DE TOKENS)
@@ -64,217 +136,203 @@ func SmartUpdateFile(gitConfig types.GitConfig, client *github.Client, args map[
 			result, err := git.UpdateFile(gitConfig, path, content)
 			if err == nil {
 				message := fmt.Sprintf("Update %s", path)
-				if m, ok := args["message"].(string); ok {
-					message = m
+				if req.Message != "" {
+					message = req.Message
 				}
 
-				return fmt.Sprintf("✅ ARCHIVO ACTUALIZADO CON GIT LOCAL (0 tokens API)\n%s\n\n🔧 Siguiente paso: git_add('%s') -> git_commit('%s')", 
-					result, path, message), nil
+				return localFileResult(path, "updated", message, fmt.Sprintf("✅ ARCHIVO ACTUALIZADO CON GIT LOCAL (0 tokens API)\n%s\n\n🔧 Siguiente paso: git_add('%s') -> git_commit('%s')", 
+					result, path, message)), nil
 			}
 		}
-		return fmt.Sprintf("⚠️ Archivo no existe localmente o Git local falló\n⤵️ Intentando GitHub API..."), fmt.Errorf("git_local_failed")
+		return nil, fmt.Errorf("⚠️ Archivo no existe localmente o Git local falló: %s", path)
 	}
 
 	// 2. Solo si NO hay Git local, usar GitHub API
-	return updateFileWithAPI(client, args)
+	return updateFileWithAPI(ctx, client, req)
 }
 
 // AutoDetectContext: Detecta automáticamente si usar Git local o GitHub API
-func AutoDetectContext(gitConfig types.GitConfig) string {
+func AutoDetectContext(gitConfig types.GitConfig) *ContextResult {
 	if gitConfig.HasGit && gitConfig.IsGitRepo {
-		return fmt.Sprintf(`🔧 MODO GIT LOCAL DETECTADO (OPTIMIZACIÓN DE TOKENS)
-📁 Repo: %s
-🌿 Rama: %s
-🔗 Remote: %s
-
-✅ RECOMENDACIÓN: Usar comandos git_* para operaciones sin costo de tokens
-- create_file/update_file: 0 tokens (Git local)
-- git_add + git_commit: 0 tokens
-- git_push: Solo si necesario sincronizar
-
-❌ EVITAR: github_* APIs a menos que sea estrictamente necesario`, 
-			gitConfig.RepoPath, gitConfig.CurrentBranch, gitConfig.RemoteURL)
+		return &ContextResult{
+			Mode:     ModeGitLocal,
+			RepoPath: gitConfig.RepoPath,
+			Branch:   gitConfig.CurrentBranch,
+			Remote:   gitConfig.RemoteURL,
+		}
 	}
 
-	return `⚠️ MODO GITHUB API (COSTO TOKENS)
-❌ No se detectó Git local o repositorio Git
-📡 Usando GitHub API (consume tokens)
-
-💡 OPTIMIZACIÓN: Clona el repo localmente para reducir costos`
+	return &ContextResult{Mode: ModeGitHubAPI}
 }
 
 // createFileWithAPI: Función auxiliar para GitHub API
-func createFileWithAPI(client *github.Client, args map[string]interface{}) (string, error) {
-	owner, ok := args["owner"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'owner' requerido para GitHub API")
+func createFileWithAPI(ctx context.Context, client *github.Client, req FileRequest) (*FileResult, error) {
+	owner := req.Owner
+	if owner == "" {
+		return nil, fmt.Errorf("parámetro 'owner' requerido para GitHub API")
 	}
 
-	repo, ok := args["repo"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'repo' requerido para GitHub API")
+	repo := req.Repo
+	if repo == "" {
+		return nil, fmt.Errorf("parámetro 'repo' requerido para GitHub API")
 	}
 
-	path, _ := args["path"].(string)
-	content, _ := args["content"].(string)
-	message, ok := args["message"].(string)
-	if !ok {
+	path, content := req.Path, req.Content
+	message := req.Message
+	if message == "" {
 		message = fmt.Sprintf("Add %s", path)
 	}
 
 	branch := "main"
-	if b, ok := args["branch"].(string); ok {
-		branch = b
+	if req.Branch != "" {
+		branch = req.Branch
 	}
 
-	result, err := githubapi.CreateFile(client, context.Background(), owner, repo, path, content, message, branch)
+	commit, err := githubapi.CreateFile(client, ctx, owner, repo, path, content, message, branch)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return fmt.Sprintf("📡 ARCHIVO CREADO CON GITHUB API (tokens consumidos)\n%s", result), nil
+	return apiFileResult(commit, fmt.Sprintf("📡 ARCHIVO CREADO CON GITHUB API (tokens consumidos)\n%s", commit)), nil
 }
 
 // updateFileWithAPI: Función auxiliar para GitHub API
-func updateFileWithAPI(client *github.Client, args map[string]interface{}) (string, error) {
-	owner, ok := args["owner"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'owner' requerido para GitHub API")
+func updateFileWithAPI(ctx context.Context, client *github.Client, req FileRequest) (*FileResult, error) {
+	owner := req.Owner
+	if owner == "" {
+		return nil, fmt.Errorf("parámetro 'owner' requerido para GitHub API")
 	}
 
-	repo, ok := args["repo"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'repo' requerido para GitHub API")
+	repo := req.Repo
+	if repo == "" {
+		return nil, fmt.Errorf("parámetro 'repo' requerido para GitHub API")
 	}
 
-	sha, ok := args["sha"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'sha' requerido para GitHub API")
+	sha := req.SHA
+	if sha == "" {
+		return nil, fmt.Errorf("parámetro 'sha' requerido para GitHub API")
 	}
 
-	path, _ := args["path"].(string)
-	content, _ := args["content"].(string)
-	message, ok := args["message"].(string)
-	if !ok {
+	path, content := req.Path, req.Content
+	message := req.Message
+	if message == "" {
 		message = fmt.Sprintf("Update %s", path)
 	}
 
 	branch := "main"
-	if b, ok := args["branch"].(string); ok {
-		branch = b
+	if req.Branch != "" {
+		branch = req.Branch
 	}
 
-	result, err := githubapi.UpdateFile(client, context.Background(), owner, repo, path, content, message, sha, branch)
+	commit, err := githubapi.UpdateFile(client, ctx, owner, repo, path, content, message, sha, branch)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return fmt.Sprintf("📡 ARCHIVO ACTUALIZADO CON GITHUB API (tokens consumidos)\n%s", result), nil
+	return apiFileResult(commit, fmt.Sprintf("📡 ARCHIVO ACTUALIZADO CON GITHUB API (tokens consumidos)\n%s", commit)), nil
 }
 
 // CreateFile crea un archivo usando Git local si está disponible, sino GitHub API
-func CreateFile(gitConfig types.GitConfig, client *github.Client, args map[string]interface{}) (string, error) {
-	path, ok := args["path"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'path' requerido")
-	}
-
-	content, ok := args["content"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'content' requerido")
+func CreateFile(ctx context.Context, gitConfig types.GitConfig, client *github.Client, req FileRequest) (*FileResult, error) {
+	path, content := req.Path, req.Content
+	if path == "" {
+		return nil, fmt.Errorf("parámetro 'path' requerido")
 	}
 
 	// Si tenemos Git local, usar workflow Git
 	if gitConfig.HasGit && gitConfig.IsGitRepo {
 		result, err := git.CreateFile(gitConfig, path, content)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
 		message := fmt.Sprintf("Add %s", path)
-		if m, ok := args["message"].(string); ok {
-			message = m
+		if req.Message != "" {
+			message = req.Message
 		}
 
-		return fmt.Sprintf("%s\nSugerencia: git_add('%s') -> git_commit('%s')", result, path, message), nil
+		return localFileResult(path, "created", message, fmt.Sprintf("%s\nSugerencia: git_add('%s') -> git_commit('%s')", result, path, message)), nil
 	}
 
 	// Fallback a GitHub API
-	owner, ok := args["owner"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'owner' requerido para API")
+	owner := req.Owner
+	if owner == "" {
+		return nil, fmt.Errorf("parámetro 'owner' requerido para API")
 	}
 
-	repo, ok := args["repo"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'repo' requerido para API")
+	repo := req.Repo
+	if repo == "" {
+		return nil, fmt.Errorf("parámetro 'repo' requerido para API")
 	}
 
-	message, ok := args["message"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'message' requerido para API")
+	message := req.Message
+	if message == "" {
+		return nil, fmt.Errorf("parámetro 'message' requerido para API")
 	}
 
 	branch := "main"
-	if b, ok := args["branch"].(string); ok {
-		branch = b
+	if req.Branch != "" {
+		branch = req.Branch
 	}
 
-	return githubapi.CreateFile(client, context.Background(), owner, repo, path, content, message, branch)
+	commit, err := githubapi.CreateFile(client, ctx, owner, repo, path, content, message, branch)
+	if err != nil {
+		return nil, err
+	}
+	return apiFileResult(commit, commit.String()), nil
 }
 
 // UpdateFile actualiza un archivo usando Git local si está disponible, sino GitHub API
-func UpdateFile(gitConfig types.GitConfig, client *github.Client, args map[string]interface{}) (string, error) {
-	path, ok := args["path"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'path' requerido")
-	}
-
-	content, ok := args["content"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'content' requerido")
+func UpdateFile(ctx context.Context, gitConfig types.GitConfig, client *github.Client, req FileRequest) (*FileResult, error) {
+	path, content := req.Path, req.Content
+	if path == "" {
+		return nil, fmt.Errorf("parámetro 'path' requerido")
 	}
 
 	// Si tenemos Git local, usar workflow Git
 	if gitConfig.HasGit && gitConfig.IsGitRepo {
 		result, err := git.UpdateFile(gitConfig, path, content)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
 		message := fmt.Sprintf("Update %s", path)
-		if m, ok := args["message"].(string); ok {
-			message = m
+		if req.Message != "" {
+			message = req.Message
 		}
 
-		return fmt.Sprintf("%s\nSugerencia: git_add('%s') -> git_commit('%s')", result, path, message), nil
+		return localFileResult(path, "updated", message, fmt.Sprintf("%s\nSugerencia: git_add('%s') -> git_commit('%s')", result, path, message)), nil
 	}
 
 	// Fallback a GitHub API
-	owner, ok := args["owner"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'owner' requerido para API")
+	owner := req.Owner
+	if owner == "" {
+		return nil, fmt.Errorf("parámetro 'owner' requerido para API")
 	}
 
-	repo, ok := args["repo"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'repo' requerido para API")
+	repo := req.Repo
+	if repo == "" {
+		return nil, fmt.Errorf("parámetro 'repo' requerido para API")
 	}
 
-	message, ok := args["message"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'message' requerido para API")
+	message := req.Message
+	if message == "" {
+		return nil, fmt.Errorf("parámetro 'message' requerido para API")
 	}
 
-	sha, ok := args["sha"].(string)
-	if !ok {
-		return "", fmt.Errorf("parámetro 'sha' requerido para API")
+	sha := req.SHA
+	if sha == "" {
+		return nil, fmt.Errorf("parámetro 'sha' requerido para API")
 	}
 
 	branch := "main"
-	if b, ok := args["branch"].(string); ok {
-		branch = b
+	if req.Branch != "" {
+		branch = req.Branch
 	}
 
-	return githubapi.UpdateFile(client, context.Background(), owner, repo, path, content, message, sha, branch)
+	commit, err := githubapi.UpdateFile(client, ctx, owner, repo, path, content, message, sha, branch)
+	if err != nil {
+		return nil, err
+	}
+	return apiFileResult(commit, commit.String()), nil
 }
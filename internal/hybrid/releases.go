@@ -0,0 +1,191 @@
+package hybrid
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/jotajotape/github-go-server-mcp/internal/git"
+	githubapi "github.com/jotajotape/github-go-server-mcp/internal/github"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// maxAssetSize es el tamaño máximo de un asset de release en GitHub (2 GiB)
+var maxAssetSize int64 = 2 << 30
+
+// ReleaseTarget identifica un release por ID o por tag
+type ReleaseTarget struct {
+	Owner string
+	Repo  string
+	ID    int64
+	Tag   string
+}
+
+// TagReleaseRequest son los parámetros de github_tag_and_release
+type TagReleaseRequest struct {
+	Owner   string
+	Repo    string
+	Tag     string
+	Message string // mensaje del tag anotado (default: "Release <tag>")
+	Release githubapi.ReleaseChanges
+}
+
+// AssetDownload es el resultado de github_download_release_asset
+type AssetDownload struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name,omitempty"` // vacío si se descargó por ID
+	Path string `json:"path"`           // relativa al workspace
+	Size int64  `json:"size"`
+}
+
+func (r *AssetDownload) String() string {
+	name := r.Name
+	if name == "" {
+		name = fmt.Sprint(r.ID)
+	}
+	return fmt.Sprintf("✅ Asset %s descargado en %s (%d bytes)", name, r.Path, r.Size)
+}
+
+// TagReleaseResult es el resultado de github_tag_and_release
+type TagReleaseResult struct {
+	Tag        string                   `json:"tag"`
+	TagCreated bool                     `json:"tagCreated"` // false si el tag ya existía localmente
+	Remote     string                   `json:"remote"`
+	Release    *githubapi.ReleaseDetail `json:"release"`
+}
+
+func (r *TagReleaseResult) String() string {
+	action := "creado y enviado"
+	if !r.TagCreated {
+		action = "existente enviado"
+	}
+	return fmt.Sprintf("🏷️ Tag %s %s a %s\n🚀 Release %q: %s", r.Tag, action, r.Remote, r.Release.Name, r.Release.URL)
+}
+
+// UploadReleaseAsset sube un archivo del workspace como asset de un release
+func UploadReleaseAsset(ctx context.Context, gitConfig types.GitConfig, client *github.Client, target ReleaseTarget, filePath, name, label string) (*githubapi.ReleaseAsset, error) {
+	file, err := git.OpenWorkingFile(gitConfig, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo %s: %v", filePath, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo %s: %v", filePath, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s es un directorio", filePath)
+	}
+	if info.Size() > maxAssetSize {
+		return nil, fmt.Errorf("%s ocupa %d bytes; GitHub admite assets de hasta %d bytes", filePath, info.Size(), maxAssetSize)
+	}
+
+	if name == "" {
+		name = path.Base(filePath)
+	}
+	return githubapi.UploadReleaseAsset(client, ctx, target.Owner, target.Repo, target.ID, target.Tag, file, name, label)
+}
+
+// DownloadReleaseAsset descarga un asset (por ID o por nombre) en un archivo del workspace.
+// Sin filePath se usa el nombre del asset.
+func DownloadReleaseAsset(ctx context.Context, gitConfig types.GitConfig, client *github.Client, target ReleaseTarget, assetID int64, assetName, filePath string, overwrite bool) (*AssetDownload, error) {
+	if assetID == 0 {
+		asset, err := githubapi.FindReleaseAsset(client, ctx, target.Owner, target.Repo, target.ID, target.Tag, assetName)
+		if err != nil {
+			return nil, err
+		}
+		if int64(asset.Size) > maxAssetSize {
+			return nil, fmt.Errorf("el asset %s ocupa %d bytes; el máximo es %d bytes", assetName, asset.Size, maxAssetSize)
+		}
+		assetID = asset.ID
+	}
+	if filePath == "" {
+		if assetName == "" {
+			return nil, fmt.Errorf("indica la ruta de destino o el nombre del asset")
+		}
+		filePath = assetName
+	}
+
+	file, err := git.CreateWorkingFile(gitConfig, filePath, overwrite)
+	if err != nil {
+		return nil, err
+	}
+	size, err := githubapi.DownloadReleaseAsset(client, ctx, target.Owner, target.Repo, assetID, &limitedWriter{w: file, n: maxAssetSize})
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return nil, err
+	}
+	return &AssetDownload{ID: assetID, Name: assetName, Path: filePath, Size: size}, nil
+}
+
+// limitedWriter falla en cuanto se intentan escribir más de n bytes, para no llenar
+// el disco con una descarga que no termina
+type limitedWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.n {
+		return 0, fmt.Errorf("la descarga supera el máximo de %d bytes", maxAssetSize)
+	}
+	n, err := l.w.Write(p)
+	l.n -= int64(n)
+	return n, err
+}
+
+// TagAndRelease crea el tag en el repositorio local (si no existe), lo envía al remoto
+// y crea el release en GitHub a partir de él. El remoto debe ser owner/repo: si no,
+// el tag acabaría en un repositorio y el release en otro.
+func TagAndRelease(ctx context.Context, gitConfig types.GitConfig, client *github.Client, req TagReleaseRequest) (*TagReleaseResult, error) {
+	if !gitConfig.HasGit || !gitConfig.IsGitRepo {
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
+	}
+	if err := git.ValidateTagName(ctx, gitConfig, req.Tag); err != nil {
+		return nil, err
+	}
+
+	result := &TagReleaseResult{Tag: req.Tag, Remote: gitConfig.RemoteName}
+	if result.Remote == "" {
+		result.Remote = git.DefaultRemote
+	}
+	owner, repo, ok := git.RemoteRepository(gitConfig)
+	if !ok {
+		return nil, fmt.Errorf("el remoto '%s' no es un repositorio de GitHub: no se puede enviar el tag a %s/%s", result.Remote, req.Owner, req.Repo)
+	}
+	if !strings.EqualFold(owner, req.Owner) || !strings.EqualFold(repo, req.Repo) {
+		return nil, fmt.Errorf("el tag se envía al remoto '%s' (%s/%s), que no es %s/%s", result.Remote, owner, repo, req.Owner, req.Repo)
+	}
+
+	if !git.TagExists(ctx, gitConfig, req.Tag) {
+		message := req.Message
+		if message == "" {
+			message = "Release " + req.Tag
+		}
+		if _, err := git.TagOperations(ctx, gitConfig, "create", req.Tag, message); err != nil {
+			return nil, err
+		}
+		result.TagCreated = true
+	}
+	if _, err := git.TagOperations(ctx, gitConfig, "push", req.Tag, ""); err != nil {
+		return nil, err
+	}
+
+	fields := req.Release
+	fields.TagName = &req.Tag
+	fields.Target = nil // el tag ya existe en el remoto
+	release, err := githubapi.CreateRelease(client, ctx, req.Owner, req.Repo, fields)
+	if err != nil {
+		return nil, fmt.Errorf("tag %s enviado, pero no se pudo crear el release: %v", req.Tag, err)
+	}
+	result.Release = release
+	return result, nil
+}
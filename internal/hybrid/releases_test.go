@@ -0,0 +1,308 @@
+package hybrid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/jotajotape/github-go-server-mcp/internal/git"
+	githubapi "github.com/jotajotape/github-go-server-mcp/internal/github"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// assetContent es el contenido del asset 5 que sirve releaseServer
+const assetContent = "binary content"
+
+// releaseServer simula la API de releases de o/r: el release 1 (tag v1) con el asset 5,
+// subidas de assets y creación de releases. Guarda cada petición y el último cuerpo.
+type releaseServer struct {
+	mu       sync.Mutex
+	requests []string
+	body     []byte
+}
+
+func (s *releaseServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	s.body = body
+	s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/o/r/releases/tags/v1":
+		fmt.Fprintf(w, `{"id":1,"tag_name":"v1","assets":[{"id":5,"name":"app.zip","size":%d}]}`, len(assetContent))
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/o/r/releases/assets/5":
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte(assetContent))
+	case r.Method == http.MethodPost && r.URL.Path == "/api/uploads/repos/o/r/releases/1/assets":
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id":6,"name":%q,"size":%d}`, r.URL.Query().Get("name"), len(body))
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/o/r/releases":
+		var release struct {
+			TagName string `json:"tag_name"`
+		}
+		json.Unmarshal(body, &release)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id":2,"tag_name":%q,"name":%q,"html_url":"https://github.com/o/r/releases/tag/%s"}`, release.TagName, release.TagName, release.TagName)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// calls devuelve las peticiones recibidas y el cuerpo de la última
+func (s *releaseServer) calls() ([]string, []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...), s.body
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *github.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := githubapi.NewClient(githubapi.StaticToken("t"), githubapi.ClientOptions{BaseURL: server.URL + "/api/v3/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// newTestRepo crea un clon de o/r en github.com con un commit y el tag v1. Su remoto
+// origin es un repositorio bare local, que se devuelve para comprobar lo enviado.
+func newTestRepo(t *testing.T) (types.GitConfig, git.Runner) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git no está instalado")
+	}
+
+	ctx := context.Background()
+	dir, bare := t.TempDir(), t.TempDir()
+	run := func(r git.Runner, args ...string) {
+		t.Helper()
+		if output, err := r.CombinedOutput(args...); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+
+	remote := git.NewRunner(ctx, bare)
+	run(remote, "init", "-q", "--bare")
+	r := git.NewRunner(ctx, dir)
+	run(r, "init", "-q", "-b", "main")
+	run(r, "config", "user.email", "test@example.com")
+	run(r, "config", "user.name", "Test")
+	run(r, "remote", "add", "origin", bare)
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(r, "add", "a.txt")
+	run(r, "commit", "-q", "-m", "first")
+	run(r, "tag", "v1")
+
+	config := types.GitConfig{
+		HasGit:        true,
+		IsGitRepo:     true,
+		WorkspacePath: dir,
+		CurrentBranch: "main",
+		RemoteName:    "origin",
+		RemoteHost:    "github.com",
+		Owner:         "o",
+		Repo:          "r",
+	}
+	return config, remote
+}
+
+// withMaxAssetSize reduce el tamaño máximo de los assets durante el test
+func withMaxAssetSize(t *testing.T, size int64) {
+	previous := maxAssetSize
+	maxAssetSize = size
+	t.Cleanup(func() { maxAssetSize = previous })
+}
+
+func TestUploadReleaseAsset(t *testing.T) {
+	config, _ := newTestRepo(t)
+	dir := config.WorkspacePath
+	if err := os.MkdirAll(filepath.Join(dir, "dist"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "dist", "app.zip"), []byte(assetContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	server := &releaseServer{}
+	client := newTestClient(t, server)
+	target := ReleaseTarget{Owner: "o", Repo: "r", Tag: "v1"}
+	ctx := context.Background()
+
+	asset, err := UploadReleaseAsset(ctx, config, client, target, "dist/app.zip", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests, body := server.calls()
+	if asset.ID != 6 || asset.Name != "app.zip" || string(body) != assetContent {
+		t.Errorf("asset = %+v, uploaded %q", asset, body)
+	}
+	if want := "POST /api/uploads/repos/o/r/releases/1/assets"; len(requests) != 2 || requests[1] != want {
+		t.Errorf("requests = %q, want release lookup and %s", requests, want)
+	}
+
+	// Ni directorios, ni rutas fuera del workspace, ni archivos que superan el límite llegan a subirse
+	withMaxAssetSize(t, int64(len(assetContent))-1)
+	for _, path := range []string{"dist", "../outside.zip", "missing.zip", "dist/app.zip"} {
+		if _, err := UploadReleaseAsset(ctx, config, client, target, path, "", ""); err == nil {
+			t.Errorf("upload %s: no error", path)
+		}
+	}
+	if after, _ := server.calls(); len(after) != len(requests) {
+		t.Errorf("rejected uploads sent requests: %q", after[len(requests):])
+	}
+}
+
+func TestDownloadReleaseAsset(t *testing.T) {
+	config, _ := newTestRepo(t)
+	client := newTestClient(t, &releaseServer{})
+	target := ReleaseTarget{Owner: "o", Repo: "r", Tag: "v1"}
+	ctx := context.Background()
+	read := func(path string) string {
+		content, _ := os.ReadFile(filepath.Join(config.WorkspacePath, filepath.FromSlash(path)))
+		return string(content)
+	}
+
+	download, err := DownloadReleaseAsset(ctx, config, client, target, 0, "app.zip", "dist/app.zip", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if download.ID != 5 || download.Size != int64(len(assetContent)) || read("dist/app.zip") != assetContent {
+		t.Errorf("download = %+v, content %q", download, read("dist/app.zip"))
+	}
+
+	// Un archivo existente solo se reemplaza con overwrite
+	if err := os.WriteFile(filepath.Join(config.WorkspacePath, "a.txt"), []byte("local"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DownloadReleaseAsset(ctx, config, client, target, 5, "", "a.txt", false); err == nil || !strings.Contains(err.Error(), "ya existe") {
+		t.Errorf("existing file: err = %v", err)
+	}
+	if got := read("a.txt"); got != "local" {
+		t.Errorf("existing file overwritten: %q", got)
+	}
+	if _, err := DownloadReleaseAsset(ctx, config, client, target, 5, "", "a.txt", true); err != nil {
+		t.Fatal(err)
+	}
+	if got := read("a.txt"); got != assetContent {
+		t.Errorf("overwrite: content = %q", got)
+	}
+
+	if _, err := DownloadReleaseAsset(ctx, config, client, target, 5, "", "../outside.zip", false); err == nil {
+		t.Error("path outside the workspace: no error")
+	}
+
+	// Por nombre, el tamaño declarado se comprueba antes de crear el archivo; por ID, la
+	// descarga se corta al superar el límite y el archivo parcial se elimina
+	withMaxAssetSize(t, int64(len(assetContent))-1)
+	for _, tt := range []struct {
+		id   int64
+		name string
+		path string
+	}{{name: "app.zip", path: "big-by-name.zip"}, {id: 5, path: "big-by-id.zip"}} {
+		if _, err := DownloadReleaseAsset(ctx, config, client, target, tt.id, tt.name, tt.path, false); err == nil {
+			t.Errorf("%s over the limit: no error", tt.path)
+		}
+		if _, err := os.Stat(filepath.Join(config.WorkspacePath, tt.path)); !os.IsNotExist(err) {
+			t.Errorf("%s left behind: %v", tt.path, err)
+		}
+	}
+}
+
+func TestTagAndRelease(t *testing.T) {
+	ctx := context.Background()
+	name := "Version 2"
+
+	t.Run("new tag", func(t *testing.T) {
+		config, remote := newTestRepo(t)
+		server := &releaseServer{}
+		client := newTestClient(t, server)
+
+		result, err := TagAndRelease(ctx, config, client, TagReleaseRequest{Owner: "o", Repo: "r", Tag: "v2", Release: githubapi.ReleaseChanges{Name: &name}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.TagCreated || result.Remote != "origin" || result.Release.Tag != "v2" {
+			t.Errorf("result = %+v", result)
+		}
+
+		// El tag anotado está en el remoto y el release se crea sobre él, sin target
+		message, err := remote.Output("for-each-ref", "--format=%(objecttype) %(contents:subject)", "refs/tags/v2")
+		if err != nil || strings.TrimSpace(string(message)) != "tag Release v2" {
+			t.Errorf("remote tag v2 = %q, %v", message, err)
+		}
+		requests, body := server.calls()
+		var sent map[string]interface{}
+		json.Unmarshal(body, &sent)
+		if len(requests) != 1 || sent["tag_name"] != "v2" || sent["name"] != name || sent["target_commitish"] != nil {
+			t.Errorf("requests = %q, body %s", requests, body)
+		}
+	})
+
+	t.Run("existing tag", func(t *testing.T) {
+		config, remote := newTestRepo(t)
+		client := newTestClient(t, &releaseServer{})
+
+		result, err := TagAndRelease(ctx, config, client, TagReleaseRequest{Owner: "o", Repo: "r", Tag: "v1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.TagCreated {
+			t.Error("existing tag recreated")
+		}
+		if _, err := remote.Output("rev-parse", "--verify", "refs/tags/v1"); err != nil {
+			t.Errorf("v1 not pushed: %v", err)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		config, remote := newTestRepo(t)
+		other := config
+		other.RemoteHost = "gitlab.com"
+
+		tests := []struct {
+			name   string
+			config types.GitConfig
+			req    TagReleaseRequest
+		}{
+			{name: "other owner", config: config, req: TagReleaseRequest{Owner: "x", Repo: "r", Tag: "v3"}},
+			{name: "other repo", config: config, req: TagReleaseRequest{Owner: "o", Repo: "x", Tag: "v3"}},
+			{name: "remote not on GitHub", config: other, req: TagReleaseRequest{Owner: "o", Repo: "r", Tag: "v3"}},
+			{name: "option as tag", config: config, req: TagReleaseRequest{Owner: "o", Repo: "r", Tag: "--delete"}},
+			{name: "invalid tag", config: config, req: TagReleaseRequest{Owner: "o", Repo: "r", Tag: "v3..4"}},
+			{name: "empty tag", config: config, req: TagReleaseRequest{Owner: "o", Repo: "r"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				server := &releaseServer{}
+				if _, err := TagAndRelease(ctx, tt.config, newTestClient(t, server), tt.req); err == nil {
+					t.Fatal("no error")
+				}
+				if requests, _ := server.calls(); len(requests) != 0 {
+					t.Errorf("requests = %q", requests)
+				}
+			})
+		}
+
+		// Nada se creó ni se envió
+		if git.TagExists(ctx, config, "v3") {
+			t.Error("tag v3 created locally")
+		}
+		if refs, _ := remote.Output("for-each-ref", "refs/tags"); len(refs) != 0 {
+			t.Errorf("remote tags = %s", refs)
+		}
+	})
+}
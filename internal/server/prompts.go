@@ -0,0 +1,284 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/git"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// maxPromptDataSize limita el tamaño de los diffs y logs embebidos en un prompt
+const maxPromptDataSize = 60000
+
+// PromptHandler genera los mensajes de un prompt a partir de sus argumentos
+type PromptHandler func(ctx context.Context, s *types.MCPServer, args map[string]string) (types.PromptGetResult, error)
+
+type promptEntry struct {
+	prompt  types.Prompt
+	handler PromptHandler
+}
+
+var (
+	promptsMu sync.RWMutex
+	prompts   = map[string]promptEntry{}
+)
+
+// RegisterPrompt añade (o reemplaza) un prompt en el registro
+func RegisterPrompt(prompt types.Prompt, handler PromptHandler) {
+	promptsMu.Lock()
+	defer promptsMu.Unlock()
+	prompts[prompt.Name] = promptEntry{prompt: prompt, handler: handler}
+}
+
+// ListPrompts retorna los prompts registrados ordenados por nombre
+func ListPrompts() types.PromptsListResult {
+	promptsMu.RLock()
+	defer promptsMu.RUnlock()
+
+	list := make([]types.Prompt, 0, len(prompts))
+	for _, entry := range prompts {
+		list = append(list, entry.prompt)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return types.PromptsListResult{Prompts: list}
+}
+
+// GetPrompt genera los mensajes del prompt solicitado
+func GetPrompt(ctx context.Context, s *types.MCPServer, params map[string]interface{}) (types.PromptGetResult, error) {
+	name, _ := params["name"].(string)
+
+	promptsMu.RLock()
+	entry, ok := prompts[name]
+	promptsMu.RUnlock()
+	if !ok {
+		return types.PromptGetResult{}, &rpcError{Code: -32602, Message: fmt.Sprintf("Invalid params: unknown prompt %q", name)}
+	}
+
+	args := map[string]string{}
+	if raw, ok := params["arguments"].(map[string]interface{}); ok {
+		for key, value := range raw {
+			args[key] = fmt.Sprint(value)
+		}
+	}
+
+	var missing []string
+	for _, arg := range entry.prompt.Arguments {
+		if arg.Required && args[arg.Name] == "" {
+			missing = append(missing, arg.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return types.PromptGetResult{}, &rpcError{Code: -32602, Message: fmt.Sprintf("Invalid params: missing required arguments: %s", strings.Join(missing, ", "))}
+	}
+
+	return entry.handler(ctx, s, args)
+}
+
+// promptFile es el formato de los prompts personalizados (un archivo JSON por prompt)
+type promptFile struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Arguments   []types.PromptArgument `json:"arguments"`
+	Template    string                 `json:"template"`
+}
+
+// LoadPromptDir registra los prompts definidos en los archivos *.json de un directorio.
+// La plantilla usa text/template con los argumentos en .Args y funciones que consultan
+// el repositorio: stagedDiff, diff "ref", commits "base" "head", diffStat "base" "head", file "ruta".
+func LoadPromptDir(dir string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return 0, fmt.Errorf("error leyendo prompt %s: %v", file, err)
+		}
+
+		var def promptFile
+		if err := json.Unmarshal(data, &def); err != nil {
+			return 0, fmt.Errorf("error interpretando prompt %s: %v", file, err)
+		}
+		if def.Name == "" || def.Template == "" {
+			return 0, fmt.Errorf("prompt %s: name y template son obligatorios", file)
+		}
+
+		// Validar la plantilla al cargarla y no en la primera petición
+		if _, err := template.New(def.Name).Funcs(promptFuncs(context.Background(), types.GitConfig{})).Parse(def.Template); err != nil {
+			return 0, fmt.Errorf("prompt %s: plantilla inválida: %v", file, err)
+		}
+
+		RegisterPrompt(types.Prompt{
+			Name:        def.Name,
+			Description: def.Description,
+			Arguments:   def.Arguments,
+		}, templatePromptHandler(def))
+	}
+	return len(files), nil
+}
+
+// templatePromptHandler genera un prompt ejecutando la plantilla con datos en vivo del repositorio
+func templatePromptHandler(def promptFile) PromptHandler {
+	return func(ctx context.Context, s *types.MCPServer, args map[string]string) (types.PromptGetResult, error) {
+		tmpl, err := template.New(def.Name).Funcs(promptFuncs(ctx, s.GitSnapshot())).Parse(def.Template)
+		if err != nil {
+			return types.PromptGetResult{}, err
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, map[string]interface{}{"Args": args}); err != nil {
+			return types.PromptGetResult{}, fmt.Errorf("error generando prompt %s: %v", def.Name, err)
+		}
+		return userPrompt(def.Description, buf.String()), nil
+	}
+}
+
+// promptFuncs expone los datos del repositorio a las plantillas de prompts
+func promptFuncs(ctx context.Context, config types.GitConfig) template.FuncMap {
+	return template.FuncMap{
+		"stagedDiff": func() (string, error) {
+			diff, err := git.StagedDiff(ctx, config)
+			return truncatePromptData(diff), err
+		},
+		"diff": func(ref string) (string, error) {
+			diff, err := git.DiffAgainst(ctx, config, ref)
+			return truncatePromptData(diff), err
+		},
+		"commits": func(base, head string) (string, error) {
+			return git.CommitsBetween(ctx, config, base, head)
+		},
+		"diffStat": func(base, head string) (string, error) {
+			return git.DiffStat(ctx, config, base, head)
+		},
+		"file": func(path string) (string, error) {
+			data, err := git.ReadWorkingFile(config, path)
+			return truncatePromptData(string(data)), err
+		},
+	}
+}
+
+func init() {
+	RegisterPrompt(types.Prompt{
+		Name:        "commit_message",
+		Description: "Redacta un mensaje de commit a partir de los cambios en staging (git diff --cached)",
+		Arguments: []types.PromptArgument{
+			{Name: "style", Description: "Estilo del mensaje, p. ej. conventional (Conventional Commits)"},
+		},
+	}, commitMessagePrompt)
+
+	RegisterPrompt(types.Prompt{
+		Name:        "describe_pr",
+		Description: "Redacta título y descripción de un pull request a partir de los commits entre base y head",
+		Arguments: []types.PromptArgument{
+			{Name: "base", Description: "Rama base del PR (p. ej. origin/main)", Required: true},
+			{Name: "head", Description: "Rama o commit con los cambios (default: HEAD)"},
+		},
+	}, describePRPrompt)
+
+	RegisterPrompt(types.Prompt{
+		Name:        "review_changes",
+		Description: "Revisa los cambios locales contra el upstream de la rama actual",
+		Arguments: []types.PromptArgument{
+			{Name: "upstream", Description: "Referencia contra la que comparar (default: @{upstream})"},
+			{Name: "focus", Description: "Aspectos a revisar con más detalle (seguridad, rendimiento, tests...)"},
+		},
+	}, reviewChangesPrompt)
+}
+
+func commitMessagePrompt(ctx context.Context, s *types.MCPServer, args map[string]string) (types.PromptGetResult, error) {
+	diff, err := git.StagedDiff(ctx, s.GitSnapshot())
+	if err != nil {
+		return types.PromptGetResult{}, err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return types.PromptGetResult{}, fmt.Errorf("no hay cambios en staging: usa git_add primero")
+	}
+
+	style := "una línea de resumen en imperativo (máx. 72 caracteres), una línea en blanco y un cuerpo breve explicando el porqué"
+	if args["style"] == "conventional" {
+		style = "el formato Conventional Commits (tipo(ámbito): resumen) con un cuerpo breve explicando el porqué"
+	}
+
+	text := fmt.Sprintf("Escribe un mensaje de commit para los siguientes cambios en staging. Usa %s.\n\n```diff\n%s\n```",
+		style, truncatePromptData(diff))
+	return userPrompt("Mensaje de commit para los cambios en staging", text), nil
+}
+
+func describePRPrompt(ctx context.Context, s *types.MCPServer, args map[string]string) (types.PromptGetResult, error) {
+	config := s.GitSnapshot()
+	base, head := args["base"], args["head"]
+	if head == "" {
+		head = "HEAD"
+	}
+
+	commits, err := git.CommitsBetween(ctx, config, base, head)
+	if err != nil {
+		return types.PromptGetResult{}, err
+	}
+	if commits == "" {
+		return types.PromptGetResult{}, fmt.Errorf("no hay commits entre %s y %s", base, head)
+	}
+
+	stats, err := git.DiffStat(ctx, config, base, head)
+	if err != nil {
+		return types.PromptGetResult{}, err
+	}
+
+	text := fmt.Sprintf("Redacta el título y la descripción de un pull request de %s hacia %s. "+
+		"Resume el objetivo, enumera los cambios principales y menciona cualquier riesgo o paso de despliegue.\n\n"+
+		"Commits (hash|autor|fecha|asunto):\n%s\n\nArchivos modificados:\n%s",
+		head, base, truncatePromptData(commits), stats)
+	return userPrompt(fmt.Sprintf("Descripción del PR %s → %s", head, base), text), nil
+}
+
+func reviewChangesPrompt(ctx context.Context, s *types.MCPServer, args map[string]string) (types.PromptGetResult, error) {
+	upstream := args["upstream"]
+	diff, err := git.DiffAgainst(ctx, s.GitSnapshot(), upstream)
+	if err != nil {
+		return types.PromptGetResult{}, err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return types.PromptGetResult{}, fmt.Errorf("no hay cambios respecto al upstream")
+	}
+
+	if upstream == "" {
+		upstream = "@{upstream}"
+	}
+
+	focus := ""
+	if args["focus"] != "" {
+		focus = fmt.Sprintf(" Presta especial atención a: %s.", args["focus"])
+	}
+
+	text := fmt.Sprintf("Revisa los siguientes cambios respecto a %s como lo haría un revisor de código exigente. "+
+		"Señala errores, casos límite sin cubrir, problemas de diseño y tests que falten, indicando archivo y línea.%s\n\n```diff\n%s\n```",
+		upstream, focus, truncatePromptData(diff))
+	return userPrompt("Revisión de cambios locales", text), nil
+}
+
+func userPrompt(description, text string) types.PromptGetResult {
+	return types.PromptGetResult{
+		Description: description,
+		Messages: []types.PromptMessage{
+			{Role: "user", Content: types.Content{Type: "text", Text: text}},
+		},
+	}
+}
+
+func truncatePromptData(data string) string {
+	if len(data) <= maxPromptDataSize {
+		return data
+	}
+	return data[:maxPromptDataSize] + fmt.Sprintf("\n... [truncado: %d bytes omitidos]", len(data)-maxPromptDataSize)
+}
@@ -0,0 +1,200 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// ToolDef declara una herramienta MCP con argumentos y resultado tipados.
+// El JSON Schema de entrada se genera a partir del struct A (ver schema.go);
+// los argumentos se validan contra él y los ausentes reciben su valor por defecto.
+// El resultado R (struct o puntero a struct) define el outputSchema y se devuelve
+// como structuredContent; el texto sale de su String() o, si no tiene, de su JSON.
+type ToolDef[A, R any] struct {
+	Name        string
+	Description string
+	Annotations types.ToolAnnotations
+	Handler     func(ctx context.Context, s *types.MCPServer, args A) (R, error)
+}
+
+// Anotaciones habituales de las herramientas de solo lectura
+var (
+	readOnlyLocal  = types.ToolAnnotations{ReadOnlyHint: true, IdempotentHint: true}
+	readOnlyRemote = types.ToolAnnotations{ReadOnlyHint: true, IdempotentHint: true, OpenWorldHint: true}
+)
+
+// registeredTool es una herramienta ya registrada, con el handler desacoplado de los tipos de argumentos y resultado.
+// Los errores devueltos son errores de protocolo o el error del contexto si la herramienta se
+// abortó por él; el resto de fallos de la herramienta van en el resultado con IsError.
+type registeredTool struct {
+	tool types.Tool
+	call func(ctx context.Context, s *types.MCPServer, args map[string]interface{}) (types.ToolCallResult, error)
+}
+
+var (
+	toolsMu    sync.RWMutex
+	toolOrder  []string
+	toolsByKey = map[string]*registeredTool{}
+)
+
+func init() {
+	registerGitTools()
+	registerHybridTools()
+	registerGitHubTools()
+	registerPullRequestTools()
+	registerIssueTools()
+	registerCheckTools()
+	registerReleaseTools()
+	registerSearchTools()
+	registerActionsTools()
+	registerContentTools()
+	registerBranchTools()
+	registerRepoTools()
+}
+
+// RegisterTool añade una herramienta al registro. Registrar dos veces el mismo nombre reemplaza la definición.
+func RegisterTool[A, R any](def ToolDef[A, R]) {
+	argType := reflect.TypeOf((*A)(nil)).Elem()
+	if argType.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("tool %s: arguments must be a struct, got %s", def.Name, argType))
+	}
+
+	resultType := reflect.TypeOf((*R)(nil)).Elem()
+	if resultType.Kind() == reflect.Pointer {
+		resultType = resultType.Elem()
+	}
+	if resultType.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("tool %s: result must be a struct, got %s", def.Name, resultType))
+	}
+
+	schema := schemaFor(argType)
+	outputSchema := outputSchemaFor(resultType)
+	annotations := def.Annotations
+	entry := &registeredTool{
+		tool: types.Tool{
+			Name:         def.Name,
+			Description:  def.Description,
+			InputSchema:  schema,
+			OutputSchema: &outputSchema,
+			Annotations:  &annotations,
+		},
+		call: func(ctx context.Context, s *types.MCPServer, raw map[string]interface{}) (types.ToolCallResult, error) {
+			if problems := validateArgs(schema, raw); len(problems) > 0 {
+				return types.ToolCallResult{}, invalidParams(def.Name, problems)
+			}
+
+			withDefaults := make(map[string]interface{}, len(raw))
+			for key, value := range raw {
+				withDefaults[key] = value
+			}
+			applyDefaults(schema, withDefaults)
+
+			var args A
+			if err := decodeArgs(withDefaults, &args); err != nil {
+				return types.ToolCallResult{}, &rpcError{Code: -32602, Message: fmt.Sprintf("Invalid params for %s: %v", def.Name, err)}
+			}
+
+			result, err := def.Handler(ctx, s, args)
+			if ctxErr := ctx.Err(); err != nil && ctxErr != nil && errors.Is(err, ctxErr) {
+				// La herramienta se abortó por cancelación o timeout: CallTool lo traduce a su error JSON-RPC
+				return types.ToolCallResult{}, err
+			}
+			if err != nil {
+				return toolError(err), nil
+			}
+			return toolResult(result), nil
+		},
+	}
+
+	toolsMu.Lock()
+	defer toolsMu.Unlock()
+	if _, exists := toolsByKey[def.Name]; !exists {
+		toolOrder = append(toolOrder, def.Name)
+	}
+	toolsByKey[def.Name] = entry
+}
+
+// ToolNames devuelve los nombres de todas las herramientas registradas
+func ToolNames() []string {
+	toolsMu.RLock()
+	defer toolsMu.RUnlock()
+	return append([]string(nil), toolOrder...)
+}
+
+// lookupTool busca una herramienta registrada por nombre
+func lookupTool(name string) (*registeredTool, bool) {
+	toolsMu.RLock()
+	defer toolsMu.RUnlock()
+	entry, ok := toolsByKey[name]
+	return entry, ok
+}
+
+// registeredTools devuelve las herramientas en orden de registro
+func registeredTools() []*registeredTool {
+	toolsMu.RLock()
+	defer toolsMu.RUnlock()
+
+	list := make([]*registeredTool, 0, len(toolOrder))
+	for _, name := range toolOrder {
+		list = append(list, toolsByKey[name])
+	}
+	return list
+}
+
+// decodeArgs copia los argumentos JSON ya validados en el struct tipado
+func decodeArgs(raw map[string]interface{}, out interface{}) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// toolResult convierte el resultado tipado de una herramienta en contenido MCP
+func toolResult(result interface{}) types.ToolCallResult {
+	if v := reflect.ValueOf(result); !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return types.ToolCallResult{Content: []types.Content{}}
+	}
+
+	var text string
+	if stringer, ok := result.(fmt.Stringer); ok {
+		text = stringer.String()
+	} else {
+		output, _ := json.MarshalIndent(result, "", "  ")
+		text = string(output)
+	}
+
+	callResult := types.ToolCallResult{
+		Content:           []types.Content{{Type: "text", Text: text}},
+		StructuredContent: result,
+	}
+	if failed, ok := result.(failedResult); ok && failed.Failed() {
+		callResult.IsError = true
+	}
+	return callResult
+}
+
+// failedResult lo implementan los resultados que describen un rechazo esperable
+// (p. ej. un PR que no se puede fusionar): se devuelven con IsError pero conservan
+// sus datos estructurados, como el motivo del rechazo
+type failedResult interface {
+	Failed() bool
+}
+
+// toolError devuelve el fallo de una herramienta como resultado con IsError,
+// para que el modelo pueda verlo y reaccionar en lugar de recibir un error JSON-RPC
+func toolError(err error) types.ToolCallResult {
+	return types.ToolCallResult{
+		Content: []types.Content{{Type: "text", Text: err.Error()}},
+		IsError: true,
+	}
+}
+
+// noArgs es el struct de argumentos de las herramientas que no reciben parámetros
+type noArgs struct{}
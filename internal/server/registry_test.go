@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+func TestRegisteredToolSchemas(t *testing.T) {
+	tools := registeredTools()
+	if len(tools) == 0 {
+		t.Fatal("no tools registered")
+	}
+
+	for _, entry := range tools {
+		tool := entry.tool
+		if tool.Description == "" {
+			t.Errorf("%s: missing description", tool.Name)
+		}
+		for _, name := range tool.InputSchema.Required {
+			if _, ok := tool.InputSchema.Properties[name]; !ok {
+				t.Errorf("%s: required argument %q has no property", tool.Name, name)
+			}
+		}
+		for name, prop := range tool.InputSchema.Properties {
+			if prop.Default != nil {
+				if problems := validateArgs(types.ToolInputSchema{Properties: map[string]types.Property{name: prop}}, map[string]interface{}{name: jsonValue(t, prop.Default)}); len(problems) > 0 {
+					t.Errorf("%s: default of %q is invalid: %v", tool.Name, name, problems)
+				}
+			}
+		}
+		if _, err := json.Marshal(tool); err != nil {
+			t.Errorf("%s: %v", tool.Name, err)
+		}
+	}
+}
+
+// jsonValue convierte un valor Go al que produciría su JSON decodificado
+func jsonValue(t *testing.T, value interface{}) interface{} {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+type echoArgs struct {
+	Text  string `json:"text" required:"true"`
+	Times int    `json:"times" default:"2" min:"1"`
+}
+
+type echoResult struct {
+	Text string `json:"text"`
+}
+
+func (r echoResult) String() string { return r.Text }
+
+func TestRegisterToolCall(t *testing.T) {
+	RegisterTool(ToolDef[echoArgs, echoResult]{
+		Name:        "test_echo",
+		Description: "echo",
+		Handler: func(ctx context.Context, s *types.MCPServer, args echoArgs) (echoResult, error) {
+			if args.Text == "fail" {
+				return echoResult{}, errors.New("boom")
+			}
+			return echoResult{Text: fmt.Sprintf("%s x%d", args.Text, args.Times)}, nil
+		},
+	})
+	entry, ok := lookupTool("test_echo")
+	if !ok {
+		t.Fatal("test_echo not registered")
+	}
+
+	result, err := entry.call(context.Background(), nil, map[string]interface{}{"text": "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.IsError || result.Content[0].Text != "hi x2" {
+		t.Errorf("result = %+v, want text 'hi x2'", result)
+	}
+	if _, ok := result.StructuredContent.(echoResult); !ok {
+		t.Errorf("structuredContent = %T, want echoResult", result.StructuredContent)
+	}
+
+	result, err = entry.call(context.Background(), nil, map[string]interface{}{"text": "fail"})
+	if err != nil || !result.IsError || result.Content[0].Text != "boom" {
+		t.Errorf("handler error: result = %+v, err = %v", result, err)
+	}
+
+	_, err = entry.call(context.Background(), nil, map[string]interface{}{"times": float64(0)})
+	var rpcErr *rpcError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32602 {
+		t.Errorf("invalid params: err = %v, want -32602", err)
+	}
+}
+
+// Las herramientas que sobrescriben o mueven el árbol de trabajo no pueden anunciarse como inocuas
+func TestToolAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		destructive bool
+		idempotent  bool
+	}{
+		{name: "create_file", destructive: true, idempotent: true},
+		{name: "git_pull", destructive: true},
+		{name: "git_checkout"},
+		{name: "git_status", idempotent: true},
+	}
+	for _, tt := range tests {
+		entry, ok := lookupTool(tt.name)
+		if !ok {
+			t.Errorf("%s not registered", tt.name)
+			continue
+		}
+		annotations := entry.tool.Annotations
+		if annotations.DestructiveHint != tt.destructive || annotations.IdempotentHint != tt.idempotent {
+			t.Errorf("%s: destructive = %v, idempotent = %v; want %v, %v", tt.name, annotations.DestructiveHint, annotations.IdempotentHint, tt.destructive, tt.idempotent)
+		}
+		if annotations.ReadOnlyHint && annotations.DestructiveHint {
+			t.Errorf("%s: read-only and destructive", tt.name)
+		}
+	}
+}
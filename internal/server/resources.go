@@ -0,0 +1,318 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/git"
+	githubapi "github.com/jotajotape/github-go-server-mcp/internal/github"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+const (
+	// errCodeResourceNotFound es el código MCP para recursos inexistentes
+	errCodeResourceNotFound = -32002
+
+	// resourcesPageSize es el número de recursos devueltos por página en resources/list
+	resourcesPageSize = 200
+	// resourceWatchInterval es la frecuencia con la que se revisan los recursos suscritos
+	resourceWatchInterval = 3 * time.Second
+
+	// worktreeRef es la referencia especial que apunta al archivo en el working directory
+	worktreeRef = "WORKTREE"
+)
+
+// gitResource representa una URI git://{workspace}/{ref}/{path}
+type gitResource struct {
+	Workspace string
+	Ref       string
+	Path      string
+}
+
+// pullResource representa una URI github://{owner}/{repo}/pulls/{n}
+type pullResource struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// ListResourceTemplates retorna las plantillas de URI soportadas
+func ListResourceTemplates() types.ResourceTemplatesListResult {
+	return types.ResourceTemplatesListResult{
+		ResourceTemplates: []types.ResourceTemplate{
+			{
+				URITemplate: "git://{workspace}/{ref}/{path}",
+				Name:        "Archivo del repositorio Git",
+				Description: "Contenido de un archivo del workspace en una referencia (branch, tag, commit; escapa '/' como %2F). Usa WORKTREE como ref para leer el archivo sin confirmar.",
+			},
+			{
+				URITemplate: "github://{owner}/{repo}/pulls/{number}",
+				Name:        "Pull request de GitHub",
+				Description: "Datos de un pull request (GitHub API)",
+				MimeType:    "application/json",
+			},
+		},
+	}
+}
+
+// ListResources lista los archivos del workspace en HEAD como recursos git://
+func ListResources(ctx context.Context, s *types.MCPServer, params map[string]interface{}) (types.ResourcesListResult, error) {
+	result := types.ResourcesListResult{Resources: []types.Resource{}}
+
+	config := s.GitSnapshot()
+	if !config.HasGit || !config.IsGitRepo {
+		return result, nil
+	}
+
+	offset := 0
+	if cursor, _ := params["cursor"].(string); cursor != "" {
+		n, err := strconv.Atoi(cursor)
+		if err != nil || n < 0 {
+			return result, &rpcError{Code: -32602, Message: fmt.Sprintf("Invalid params: invalid cursor %q", cursor)}
+		}
+		offset = n
+	}
+
+	paths, err := git.ListFilePaths(ctx, config, "HEAD")
+	if err != nil {
+		return result, err
+	}
+
+	if offset > len(paths) {
+		offset = len(paths)
+	}
+	end := offset + resourcesPageSize
+	if end < len(paths) {
+		result.NextCursor = strconv.Itoa(end)
+	} else {
+		end = len(paths)
+	}
+
+	workspace := workspaceName(config)
+	for _, p := range paths[offset:end] {
+		result.Resources = append(result.Resources, types.Resource{
+			URI:      buildGitURI(workspace, "HEAD", p),
+			Name:     p,
+			MimeType: guessMimeType(p, nil),
+		})
+	}
+	return result, nil
+}
+
+// ReadResource lee el contenido de un recurso git:// o github://
+func ReadResource(ctx context.Context, s *types.MCPServer, params map[string]interface{}) (types.ResourceReadResult, error) {
+	uri, _ := params["uri"].(string)
+	if uri == "" {
+		return types.ResourceReadResult{}, &rpcError{Code: -32602, Message: "Invalid params: uri is required"}
+	}
+
+	switch {
+	case strings.HasPrefix(uri, "git://"):
+		res, err := parseGitURI(uri)
+		if err != nil {
+			return types.ResourceReadResult{}, err
+		}
+
+		data, err := readGitResource(ctx, s.GitSnapshot(), res)
+		if err != nil {
+			return types.ResourceReadResult{}, &rpcError{Code: errCodeResourceNotFound, Message: fmt.Sprintf("Resource not found: %s (%v)", uri, err)}
+		}
+		return types.ResourceReadResult{Contents: []types.ResourceContents{resourceContents(uri, res.Path, data)}}, nil
+
+	case strings.HasPrefix(uri, "github://"):
+		res, err := parsePullURI(uri)
+		if err != nil {
+			return types.ResourceReadResult{}, err
+		}
+
+		pr, err := githubapi.GetPullRequest(s.GithubClient, ctx, res.Owner, res.Repo, res.Number)
+		if err != nil {
+			return types.ResourceReadResult{}, &rpcError{Code: errCodeResourceNotFound, Message: fmt.Sprintf("Resource not found: %s (%v)", uri, err)}
+		}
+		text, _ := json.MarshalIndent(pr, "", "  ")
+		return types.ResourceReadResult{Contents: []types.ResourceContents{{URI: uri, MimeType: "application/json", Text: string(text)}}}, nil
+	}
+
+	return types.ResourceReadResult{}, &rpcError{Code: -32602, Message: fmt.Sprintf("Invalid params: unsupported resource URI %q", uri)}
+}
+
+// SubscribeResource registra una suscripción a cambios de un recurso git://
+func SubscribeResource(s *types.MCPServer, params map[string]interface{}) error {
+	uri, _ := params["uri"].(string)
+	if !strings.HasPrefix(uri, "git://") {
+		return &rpcError{Code: -32602, Message: fmt.Sprintf("Invalid params: only git:// resources support subscriptions (got %q)", uri)}
+	}
+	if _, err := parseGitURI(uri); err != nil {
+		return err
+	}
+
+	s.Subscribe(uri, func(ctx context.Context) {
+		watchResources(ctx, s)
+	})
+	return nil
+}
+
+// UnsubscribeResource elimina una suscripción
+func UnsubscribeResource(s *types.MCPServer, params map[string]interface{}) error {
+	uri, _ := params["uri"].(string)
+	if uri == "" {
+		return &rpcError{Code: -32602, Message: "Invalid params: uri is required"}
+	}
+	s.Unsubscribe(uri)
+	return nil
+}
+
+// watchResources revisa periódicamente los recursos suscritos y envía
+// notifications/resources/updated cuando cambia HEAD, la referencia o el working tree
+func watchResources(ctx context.Context, s *types.MCPServer) {
+	ticker := time.NewTicker(resourceWatchInterval)
+	defer ticker.Stop()
+
+	states := make(map[string]string)
+	for {
+		current := make(map[string]string)
+		for _, uri := range s.Subscriptions() {
+			state := resourceState(ctx, s.GitSnapshot(), uri)
+			if previous, seen := states[uri]; seen && previous != state {
+				s.Notify("notifications/resources/updated", map[string]interface{}{"uri": uri})
+			}
+			current[uri] = state
+		}
+		states = current
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// resourceState resume la versión actual de un recurso git:// para detectar cambios
+func resourceState(ctx context.Context, config types.GitConfig, uri string) string {
+	res, err := parseGitURI(uri)
+	if err != nil || res.Workspace != workspaceName(config) {
+		return ""
+	}
+
+	if res.Ref == worktreeRef {
+		info, err := git.StatWorkingFile(config, res.Path)
+		if err != nil {
+			return ""
+		}
+		return fmt.Sprintf("%d:%d", info.ModTime().UnixNano(), info.Size())
+	}
+
+	sha, err := git.BlobSHA(ctx, config, res.Path, res.Ref)
+	if err != nil {
+		return ""
+	}
+	return sha
+}
+
+func readGitResource(ctx context.Context, config types.GitConfig, res gitResource) ([]byte, error) {
+	if !config.HasGit || !config.IsGitRepo {
+		return nil, fmt.Errorf("Git no disponible o no es un repositorio Git")
+	}
+	if current := workspaceName(config); res.Workspace != current {
+		return nil, fmt.Errorf("el workspace '%s' no es el activo ('%s')", res.Workspace, current)
+	}
+
+	if res.Ref == worktreeRef {
+		return git.ReadWorkingFile(config, res.Path)
+	}
+	return git.ReadFile(ctx, config, res.Path, res.Ref)
+}
+
+func resourceContents(uri, filePath string, data []byte) types.ResourceContents {
+	contents := types.ResourceContents{URI: uri, MimeType: guessMimeType(filePath, data)}
+	if utf8.Valid(data) {
+		contents.Text = string(data)
+	} else {
+		contents.Blob = base64.StdEncoding.EncodeToString(data)
+	}
+	return contents
+}
+
+func guessMimeType(filePath string, data []byte) string {
+	if mimeType := mime.TypeByExtension(path.Ext(filePath)); mimeType != "" {
+		return mimeType
+	}
+	if data != nil && !utf8.Valid(data) {
+		return "application/octet-stream"
+	}
+	return "text/plain"
+}
+
+// workspaceName es el identificador del workspace en las URIs git://
+func workspaceName(config types.GitConfig) string {
+	return filepath.Base(git.GetEffectiveWorkingDir(config))
+}
+
+func buildGitURI(workspace, ref, filePath string) string {
+	segments := strings.Split(filePath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return fmt.Sprintf("git://%s/%s/%s", url.PathEscape(workspace), url.PathEscape(ref), strings.Join(segments, "/"))
+}
+
+func parseGitURI(uri string) (gitResource, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "git" {
+		return gitResource{}, &rpcError{Code: -32602, Message: fmt.Sprintf("Invalid params: invalid git resource URI %q", uri)}
+	}
+
+	workspace, err := url.PathUnescape(u.Host)
+	if err != nil {
+		return gitResource{}, &rpcError{Code: -32602, Message: fmt.Sprintf("Invalid params: invalid workspace in %q", uri)}
+	}
+
+	// La ref es el primer segmento (escapado), el resto es la ruta del archivo
+	escapedRef, escapedPath, ok := strings.Cut(strings.TrimPrefix(u.EscapedPath(), "/"), "/")
+	if !ok || escapedRef == "" || escapedPath == "" {
+		return gitResource{}, &rpcError{Code: -32602, Message: fmt.Sprintf("Invalid params: expected git://{workspace}/{ref}/{path}, got %q", uri)}
+	}
+
+	ref, err := url.PathUnescape(escapedRef)
+	if err != nil || strings.HasPrefix(ref, "-") {
+		return gitResource{}, &rpcError{Code: -32602, Message: fmt.Sprintf("Invalid params: invalid ref in %q", uri)}
+	}
+	filePath, err := url.PathUnescape(escapedPath)
+	if err != nil {
+		return gitResource{}, &rpcError{Code: -32602, Message: fmt.Sprintf("Invalid params: invalid path in %q", uri)}
+	}
+
+	return gitResource{Workspace: workspace, Ref: ref, Path: filePath}, nil
+}
+
+func parsePullURI(uri string) (pullResource, error) {
+	invalid := &rpcError{Code: -32602, Message: fmt.Sprintf("Invalid params: expected github://{owner}/{repo}/pulls/{number}, got %q", uri)}
+
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "github" || u.Host == "" {
+		return pullResource{}, invalid
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 3 || parts[1] != "pulls" {
+		return pullResource{}, invalid
+	}
+
+	number, err := strconv.Atoi(parts[2])
+	if err != nil || number <= 0 {
+		return pullResource{}, invalid
+	}
+
+	return pullResource{Owner: u.Host, Repo: parts[0], Number: number}, nil
+}
@@ -0,0 +1,43 @@
+package server
+
+import "testing"
+
+func TestParseGitURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		want    gitResource
+		wantErr bool
+	}{
+		{name: "simple", uri: "git://repo/HEAD/README.md", want: gitResource{Workspace: "repo", Ref: "HEAD", Path: "README.md"}},
+		{name: "nested path", uri: "git://repo/main/internal/git/runner.go", want: gitResource{Workspace: "repo", Ref: "main", Path: "internal/git/runner.go"}},
+		{name: "escaped ref", uri: "git://repo/feature%2Fx/a.go", want: gitResource{Workspace: "repo", Ref: "feature/x", Path: "a.go"}},
+		{name: "escaped path", uri: "git://repo/HEAD/docs/my%20file.md", want: gitResource{Workspace: "repo", Ref: "HEAD", Path: "docs/my file.md"}},
+		{name: "worktree", uri: "git://repo/" + worktreeRef + "/a.go", want: gitResource{Workspace: "repo", Ref: worktreeRef, Path: "a.go"}},
+		{name: "round trip", uri: buildGitURI("repo", "v1.0", "dir/a b.txt"), want: gitResource{Workspace: "repo", Ref: "v1.0", Path: "dir/a b.txt"}},
+		{name: "option as ref", uri: "git://repo/--output=%2Ftmp%2Fx/a.go", wantErr: true},
+		{name: "short option as ref", uri: "git://repo/-p/a.go", wantErr: true},
+		{name: "wrong scheme", uri: "file://repo/HEAD/a.go", wantErr: true},
+		{name: "missing path", uri: "git://repo/HEAD", wantErr: true},
+		{name: "missing ref", uri: "git://repo//a.go", wantErr: true},
+		{name: "bad escape", uri: "git://repo/HEAD/%zz", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseGitURI(tt.uri)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseGitURI(%q) = %+v, want error", tt.uri, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseGitURI(%q): %v", tt.uri, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseGitURI(%q) = %+v, want %+v", tt.uri, got, tt.want)
+			}
+		})
+	}
+}
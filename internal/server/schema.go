@@ -0,0 +1,349 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// Las herramientas declaran sus argumentos como structs. Cada campo exportado con
+// etiqueta json se convierte en una propiedad del JSON Schema:
+//
+//	Path      string   `json:"path" desc:"Ruta del archivo" required:"true"`
+//	Operation string   `json:"operation" desc:"Operación" enum:"list,push,pop" required:"true"`
+//	Limit     int      `json:"limit" desc:"Número de commits" default:"20" min:"1" max:"1000"`
+//	Owner     string   `json:"owner" desc:"Propietario" pattern:"^[A-Za-z0-9-]+$"`
+//	Files     []string `json:"files" desc:"Archivos a agregar"`
+//
+// Los slices generan "array" con Items; los structs anidados generan "object" con
+// sus propias Properties y los embebidos aportan sus campos al struct que los contiene.
+// En arrays, enum y pattern se aplican a cada elemento.
+//
+// En el outputSchema de los resultados, los slices, mapas y punteros sin omitempty
+// admiten además null, que es como encoding/json serializa sus valores nil.
+
+// schemaFor genera el JSON Schema de entrada a partir del struct de argumentos
+func schemaFor(t reflect.Type) types.ToolInputSchema {
+	return objectSchema(objectProperty(t, false))
+}
+
+// outputSchemaFor genera el outputSchema a partir del struct de resultado
+func outputSchemaFor(t reflect.Type) types.ToolInputSchema {
+	return objectSchema(objectProperty(t, true))
+}
+
+func objectSchema(object types.Property) types.ToolInputSchema {
+	return types.ToolInputSchema{
+		Type:       "object",
+		Properties: object.Properties,
+		Required:   object.Required,
+	}
+}
+
+// objectProperty genera el schema de un struct; output indica si describe un resultado
+func objectProperty(t reflect.Type, output bool) types.Property {
+	prop := types.Property{
+		Type:       "object",
+		Properties: map[string]types.Property{},
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		// Como en encoding/json, los structs embebidos sin nombre (aunque su tipo no
+		// sea exportado) aportan sus campos
+		if name == "" && f.Anonymous && f.Type.Kind() == reflect.Struct {
+			embedded := objectProperty(f.Type, output)
+			for embeddedName, embeddedProp := range embedded.Properties {
+				prop.Properties[embeddedName] = embeddedProp
+			}
+			prop.Required = append(prop.Required, embedded.Required...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		prop.Properties[name] = fieldProperty(f, output)
+		if f.Tag.Get("required") == "true" {
+			prop.Required = append(prop.Required, name)
+		}
+	}
+	return prop
+}
+
+// fieldProperty genera el schema de un campo a partir de su tipo y sus etiquetas
+func fieldProperty(f reflect.StructField, output bool) types.Property {
+	prop := typeProperty(f.Type, output)
+	prop.Description = f.Tag.Get("desc")
+	if output && !strings.Contains(f.Tag.Get("json"), ",omitempty") {
+		switch f.Type.Kind() {
+		case reflect.Slice, reflect.Map, reflect.Pointer:
+			prop.Nullable = true
+		}
+	}
+
+	// En arrays las restricciones de valor se aplican a los elementos
+	target := &prop
+	if prop.Items != nil {
+		target = prop.Items
+	}
+
+	if enum := f.Tag.Get("enum"); enum != "" {
+		for _, value := range strings.Split(enum, ",") {
+			target.Enum = append(target.Enum, parseTagValue(target.Type, strings.TrimSpace(value)))
+		}
+	}
+	if pattern := f.Tag.Get("pattern"); pattern != "" {
+		compilePattern(pattern) // un patrón inválido debe fallar al registrar la herramienta
+		target.Pattern = pattern
+	}
+	if min := f.Tag.Get("min"); min != "" {
+		target.Minimum = parseBound(min)
+	}
+	if max := f.Tag.Get("max"); max != "" {
+		target.Maximum = parseBound(max)
+	}
+	if def, ok := f.Tag.Lookup("default"); ok {
+		prop.Default = parseTagValue(prop.Type, def)
+	}
+	return prop
+}
+
+// typeProperty traduce un tipo Go al schema equivalente
+func typeProperty(t reflect.Type, output bool) types.Property {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return types.Property{Type: "string"}
+	case reflect.Bool:
+		return types.Property{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return types.Property{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return types.Property{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		items := typeProperty(t.Elem(), output)
+		return types.Property{Type: "array", Items: &items}
+	case reflect.Struct:
+		return objectProperty(t, output)
+	default:
+		return types.Property{Type: "object"}
+	}
+}
+
+// parseTagValue convierte el texto de una etiqueta (enum, default) al tipo JSON de la propiedad
+func parseTagValue(schemaType, value string) interface{} {
+	switch schemaType {
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	case "array":
+		var items []interface{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	return value
+}
+
+func parseBound(value string) *float64 {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		panic(fmt.Sprintf("invalid schema bound %q: %v", value, err))
+	}
+	return &n
+}
+
+// validateArgs comprueba los argumentos recibidos contra el schema de la herramienta
+// y devuelve un problema por cada campo desconocido, ausente o con valor incorrecto
+func validateArgs(schema types.ToolInputSchema, args map[string]interface{}) map[string]string {
+	problems := map[string]string{}
+	validateObject("", schema.Properties, schema.Required, args, problems)
+	return problems
+}
+
+func validateObject(prefix string, props map[string]types.Property, required []string, args map[string]interface{}, problems map[string]string) {
+	for name, value := range args {
+		prop, ok := props[name]
+		if !ok {
+			problems[prefix+name] = "unknown argument"
+			continue
+		}
+		if value == nil {
+			continue
+		}
+		validateValue(prefix+name, prop, value, problems)
+	}
+
+	for _, name := range required {
+		if value, ok := args[name]; !ok || value == nil {
+			problems[prefix+name] = "is required"
+		}
+	}
+}
+
+func validateValue(path string, prop types.Property, value interface{}, problems map[string]string) {
+	if !matchesType(prop.Type, value) {
+		problems[path] = fmt.Sprintf("must be %s, got %s", prop.Type, describeJSONValue(value))
+		return
+	}
+
+	if len(prop.Enum) > 0 && !inEnum(prop.Enum, value) {
+		problems[path] = fmt.Sprintf("must be one of %s", formatEnum(prop.Enum))
+		return
+	}
+
+	switch v := value.(type) {
+	case float64:
+		if prop.Minimum != nil && v < *prop.Minimum {
+			problems[path] = fmt.Sprintf("must be >= %v", *prop.Minimum)
+		} else if prop.Maximum != nil && v > *prop.Maximum {
+			problems[path] = fmt.Sprintf("must be <= %v", *prop.Maximum)
+		}
+	case string:
+		if prop.Pattern != "" && !compilePattern(prop.Pattern).MatchString(v) {
+			problems[path] = fmt.Sprintf("must match pattern %s", prop.Pattern)
+		}
+	case []interface{}:
+		if prop.Items != nil {
+			for i, item := range v {
+				validateValue(fmt.Sprintf("%s[%d]", path, i), *prop.Items, item, problems)
+			}
+		}
+	case map[string]interface{}:
+		if prop.Properties != nil {
+			validateObject(path+".", prop.Properties, prop.Required, v, problems)
+		}
+	}
+}
+
+// applyDefaults rellena los argumentos ausentes que declaran un valor por defecto
+func applyDefaults(schema types.ToolInputSchema, args map[string]interface{}) {
+	for name, prop := range schema.Properties {
+		if value, ok := args[name]; (!ok || value == nil) && prop.Default != nil {
+			args[name] = prop.Default
+		}
+	}
+}
+
+func matchesType(schemaType string, value interface{}) bool {
+	switch schemaType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	}
+	return true
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatEnum(enum []interface{}) string {
+	values := make([]string, len(enum))
+	for i, value := range enum {
+		values[i] = fmt.Sprint(value)
+	}
+	return strings.Join(values, ", ")
+}
+
+func describeJSONValue(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+var patternCache sync.Map // map[string]*regexp.Regexp
+
+func compilePattern(pattern string) *regexp.Regexp {
+	if re, ok := patternCache.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile(pattern)
+	patternCache.Store(pattern, re)
+	return re
+}
+
+// invalidParams construye el error -32602 con la lista de campos problemáticos
+func invalidParams(tool string, problems map[string]string) *rpcError {
+	fields := make([]string, 0, len(problems))
+	for name := range problems {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+
+	details := make([]string, 0, len(fields))
+	data := make([]map[string]string, 0, len(fields))
+	for _, name := range fields {
+		details = append(details, fmt.Sprintf("'%s' %s", name, problems[name]))
+		data = append(data, map[string]string{"field": name, "problem": problems[name]})
+	}
+
+	return &rpcError{
+		Code:    -32602,
+		Message: fmt.Sprintf("Invalid params for %s: %s", tool, strings.Join(details, "; ")),
+		Data:    map[string]interface{}{"fields": data},
+	}
+}
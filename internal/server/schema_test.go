@@ -0,0 +1,153 @@
+package server
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type outputFixture struct {
+	Name      string            `json:"name"`
+	Items     []string          `json:"items"`
+	Optional  []string          `json:"optional,omitempty"`
+	Flag      *bool             `json:"flag"`
+	Labels    map[string]bool   `json:"labels"`
+	Nested    *outputNested     `json:"nested,omitempty"`
+	Children  []outputNested    `json:"children"`
+	Unchanged map[string]string `json:"unchanged,omitempty"`
+}
+
+type outputNested struct {
+	Values []int `json:"values"`
+}
+
+func TestOutputSchemaNullable(t *testing.T) {
+	schema := outputSchemaFor(reflect.TypeOf(outputFixture{}))
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Properties map[string]struct {
+			Type  interface{} `json:"type"`
+			Items *struct {
+				Properties map[string]struct {
+					Type interface{} `json:"type"`
+				} `json:"properties"`
+			} `json:"items"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"name":      "string",
+		"items":     []interface{}{"array", "null"},
+		"optional":  "array",
+		"flag":      []interface{}{"boolean", "null"},
+		"labels":    []interface{}{"object", "null"},
+		"nested":    "object",
+		"children":  []interface{}{"array", "null"},
+		"unchanged": "object",
+	}
+	for name, wantType := range want {
+		if got := decoded.Properties[name].Type; !reflect.DeepEqual(got, wantType) {
+			t.Errorf("%s: type = %v, want %v", name, got, wantType)
+		}
+	}
+	if got := decoded.Properties["children"].Items.Properties["values"].Type; !reflect.DeepEqual(got, []interface{}{"array", "null"}) {
+		t.Errorf("children[].values: type = %v, want [array null]", got)
+	}
+}
+
+func TestInputSchemaNotNullable(t *testing.T) {
+	schema := schemaFor(reflect.TypeOf(struct {
+		Files []string `json:"files"`
+		Title *string  `json:"title"`
+	}{}))
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"object","properties":{"files":{"type":"array","items":{"type":"string"}},"title":{"type":"string"}}}`
+	if string(data) != want {
+		t.Errorf("input schema = %s, want %s", data, want)
+	}
+}
+
+type validationFixture struct {
+	Path    string   `json:"path" required:"true"`
+	Mode    string   `json:"mode" enum:"soft,hard" default:"soft"`
+	Limit   int      `json:"limit" default:"20" min:"1" max:"100"`
+	Force   bool     `json:"force" default:"false"`
+	Owner   string   `json:"owner" pattern:"^[a-z]+$"`
+	Files   []string `json:"files" pattern:"^[^-]"`
+	Kinds   []string `json:"kinds" enum:"a,b"`
+	Comment struct {
+		Line int    `json:"line" required:"true" min:"1"`
+		Side string `json:"side" enum:"LEFT,RIGHT"`
+	} `json:"comment"`
+	embeddedFixture
+}
+
+type embeddedFixture struct {
+	Repo string `json:"repo" required:"true"`
+}
+
+func TestValidateArgs(t *testing.T) {
+	schema := schemaFor(reflect.TypeOf(validationFixture{}))
+
+	tests := []struct {
+		name string
+		args string
+		want map[string]string
+	}{
+		{name: "valid", args: `{"path":"a","repo":"r","mode":"hard","limit":5,"files":["a"],"kinds":["b"],"comment":{"line":2,"side":"LEFT"}}`, want: map[string]string{}},
+		{name: "missing required", args: `{"limit":5}`, want: map[string]string{"path": "is required", "repo": "is required"}},
+		{name: "null required", args: `{"path":null,"repo":"r"}`, want: map[string]string{"path": "is required"}},
+		{name: "null optional", args: `{"path":"a","repo":"r","limit":null}`, want: map[string]string{}},
+		{name: "unknown argument", args: `{"path":"a","repo":"r","extra":1}`, want: map[string]string{"extra": "unknown argument"}},
+		{name: "wrong type", args: `{"path":1,"repo":"r","force":"yes"}`, want: map[string]string{"path": "must be string, got number", "force": "must be boolean, got string"}},
+		{name: "not an integer", args: `{"path":"a","repo":"r","limit":1.5}`, want: map[string]string{"limit": "must be integer, got number"}},
+		{name: "enum", args: `{"path":"a","repo":"r","mode":"medium"}`, want: map[string]string{"mode": "must be one of soft, hard"}},
+		{name: "bounds", args: `{"path":"a","repo":"r","limit":0}`, want: map[string]string{"limit": "must be >= 1"}},
+		{name: "upper bound", args: `{"path":"a","repo":"r","limit":101}`, want: map[string]string{"limit": "must be <= 100"}},
+		{name: "pattern", args: `{"path":"a","repo":"r","owner":"Acme"}`, want: map[string]string{"owner": "must match pattern ^[a-z]+$"}},
+		{name: "array items", args: `{"path":"a","repo":"r","files":["ok","-x"],"kinds":["a","c"]}`, want: map[string]string{"files[1]": "must match pattern ^[^-]", "kinds[1]": "must be one of a, b"}},
+		{name: "nested object", args: `{"path":"a","repo":"r","comment":{"side":"UP"}}`, want: map[string]string{"comment.line": "is required", "comment.side": "must be one of LEFT, RIGHT"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+				t.Fatal(err)
+			}
+			if got := validateArgs(schema, args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateArgs(%s) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyDefaults(t *testing.T) {
+	schema := schemaFor(reflect.TypeOf(validationFixture{}))
+
+	args := map[string]interface{}{"path": "a", "limit": float64(5), "mode": nil}
+	applyDefaults(schema, args)
+
+	want := map[string]interface{}{"path": "a", "limit": float64(5), "mode": "soft", "force": false}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("applyDefaults = %v, want %v", args, want)
+	}
+
+	// Los valores por defecto llegan al struct con su tipo
+	var decoded validationFixture
+	if err := decodeArgs(map[string]interface{}{"limit": schema.Properties["limit"].Default}, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Limit != 20 {
+		t.Errorf("default limit = %d, want 20", decoded.Limit)
+	}
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+type waitArgs struct {
+	Millis     int  `json:"millis"`
+	IgnoreCtx  bool `json:"ignore_ctx"` // terminar aunque venza el plazo, como un push ya enviado
+	WrapCtxErr bool `json:"wrap_ctx_err"`
+}
+
+type waitResult struct {
+	Waited int `json:"waited"`
+}
+
+// waitStarted recibe un aviso cada vez que empieza una llamada a test_wait
+var waitStarted = make(chan struct{}, 16)
+
+func init() {
+	RegisterTool(ToolDef[waitArgs, waitResult]{
+		Name:        "test_wait",
+		Description: "Espera el tiempo indicado",
+		Handler: func(ctx context.Context, s *types.MCPServer, args waitArgs) (waitResult, error) {
+			waitStarted <- struct{}{}
+			delay := time.Duration(args.Millis) * time.Millisecond
+			if args.IgnoreCtx {
+				time.Sleep(delay)
+				return waitResult{Waited: args.Millis}, nil
+			}
+			select {
+			case <-time.After(delay):
+				return waitResult{Waited: args.Millis}, nil
+			case <-ctx.Done():
+				if args.WrapCtxErr {
+					return waitResult{}, fmt.Errorf("git push: %w", ctx.Err())
+				}
+				return waitResult{}, ctx.Err()
+			}
+		},
+	})
+}
+
+func callWait(args map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"name": "test_wait", "arguments": args}
+}
+
+func TestCallToolTimeout(t *testing.T) {
+	s := &types.MCPServer{DefaultTimeout: time.Minute, ToolTimeouts: map[string]time.Duration{"test_wait": 50 * time.Millisecond}}
+
+	tests := []struct {
+		name     string
+		args     map[string]interface{}
+		wantCode int // 0: la herramienta devuelve su resultado
+	}{
+		{name: "within timeout", args: map[string]interface{}{"millis": float64(5)}},
+		{name: "aborted by timeout", args: map[string]interface{}{"millis": float64(5000)}, wantCode: errCodeRequestTimeout},
+		{name: "wrapped context error", args: map[string]interface{}{"millis": float64(5000), "wrap_ctx_err": true}, wantCode: errCodeRequestTimeout},
+		{name: "finished after the deadline", args: map[string]interface{}{"millis": float64(100), "ignore_ctx": true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CallTool(context.Background(), s, callWait(tt.args))
+			<-waitStarted
+			if tt.wantCode != 0 {
+				rerr, ok := err.(*rpcError)
+				if !ok || rerr.Code != tt.wantCode {
+					t.Fatalf("err = %v, want code %d", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil || result.IsError {
+				t.Fatalf("result = %+v, err = %v", result, err)
+			}
+			if _, ok := result.StructuredContent.(waitResult); !ok {
+				t.Errorf("structuredContent = %T, want waitResult", result.StructuredContent)
+			}
+		})
+	}
+}
+
+func TestHandleRequestCancelled(t *testing.T) {
+	s := &types.MCPServer{}
+	done := make(chan *types.JSONRPCResponse)
+	go func() {
+		done <- HandleRequest(context.Background(), s, types.JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      float64(7),
+			Method:  "tools/call",
+			Params:  callWait(map[string]interface{}{"millis": float64(5000)}),
+		})
+	}()
+	<-waitStarted
+
+	notification := types.JSONRPCRequest{
+		JSONRPC: "2.0",
+		Method:  "notifications/cancelled",
+		Params:  map[string]interface{}{"requestId": float64(7), "reason": "test"},
+	}
+	if resp := HandleRequest(context.Background(), s, notification); resp != nil {
+		t.Errorf("notification answered: %+v", resp)
+	}
+
+	select {
+	case resp := <-done:
+		if resp.Error == nil || resp.Error.Code != errCodeRequestCancelled {
+			t.Errorf("response = %+v, want error %d", resp, errCodeRequestCancelled)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("request not cancelled")
+	}
+
+	// Cancelar una petición que ya terminó no tiene efecto
+	if s.CancelRequest(float64(7)) {
+		t.Error("finished request still tracked")
+	}
+}
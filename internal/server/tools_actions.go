@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	githubapi "github.com/jotajotape/github-go-server-mcp/internal/github"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// Argumentos de las herramientas de GitHub Actions
+type listWorkflowsArgs struct {
+	repoArgs
+	pageArgs
+}
+
+type listRunsArgs struct {
+	repoArgs
+	Workflow string `json:"workflow" desc:"ID o archivo del workflow (p. ej. ci.yml); vacío = todos"`
+	Branch   string `json:"branch" desc:"Rama"`
+	Event    string `json:"event" desc:"Evento que lanzó la ejecución (push, pull_request, workflow_dispatch...)"`
+	Status   string `json:"status" desc:"Estado o conclusión" enum:"queued,in_progress,completed,waiting,requested,pending,success,failure,cancelled,skipped,timed_out,action_required,neutral,stale"`
+	HeadSHA  string `json:"head_sha" desc:"SHA del commit" pattern:"^[0-9a-f]{40}$"`
+	pageArgs
+}
+
+type runIDArgs struct {
+	repoArgs
+	RunID int64 `json:"run_id" desc:"ID de la ejecución" required:"true" min:"1"`
+}
+
+type runLogsArgs struct {
+	repoArgs
+	RunID int64  `json:"run_id" desc:"ID de la ejecución (opcional si se indica job_id)" min:"1"`
+	JobID int64  `json:"job_id" desc:"Solo este job (default: los jobs fallidos)" min:"1"`
+	Step  int    `json:"step" desc:"Solo este step, por número (default: los steps fallidos)" min:"1"`
+	Grep  string `json:"grep" desc:"Expresión regular: solo las líneas que coinciden (p. ej. (?i)error|FAIL)"`
+	Tail  int    `json:"tail" desc:"Últimas N líneas de cada fragmento (0 = todas)" default:"200" min:"0"`
+}
+
+type rerunArgs struct {
+	runIDArgs
+	All bool `json:"all" desc:"Repetir todos los jobs, no solo los fallidos"`
+}
+
+type dispatchWorkflowArgs struct {
+	repoArgs
+	Workflow string                 `json:"workflow" desc:"ID o archivo del workflow (p. ej. deploy.yml)" required:"true"`
+	Ref      string                 `json:"ref" desc:"Rama o tag (default: base_branch del perfil o la rama por defecto)"`
+	Inputs   map[string]interface{} `json:"inputs" desc:"Inputs del workflow_dispatch (máximo 10)"`
+}
+
+func registerActionsTools() {
+	RegisterTool(ToolDef[listWorkflowsArgs, *githubapi.WorkflowList]{
+		Name:        "github_list_workflows",
+		Description: "Lista los workflows de GitHub Actions del repositorio",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args listWorkflowsArgs) (*githubapi.WorkflowList, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.ListWorkflows(s.GithubClient, ctx, owner, repo, args.options())
+		},
+	})
+	RegisterTool(ToolDef[listRunsArgs, *githubapi.RunList]{
+		Name:        "github_list_runs",
+		Description: "Lista ejecuciones de workflows filtradas por workflow, rama, evento o estado (GitHub Actions)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args listRunsArgs) (*githubapi.RunList, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.ListWorkflowRuns(s.GithubClient, ctx, owner, repo, githubapi.RunFilter{
+				Workflow:    args.Workflow,
+				Branch:      args.Branch,
+				Event:       args.Event,
+				Status:      args.Status,
+				HeadSHA:     args.HeadSHA,
+				PageOptions: args.options(),
+			})
+		},
+	})
+	RegisterTool(ToolDef[runIDArgs, *githubapi.RunDetail]{
+		Name:        "github_get_run",
+		Description: "Obtiene una ejecución con sus jobs y steps (GitHub Actions)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args runIDArgs) (*githubapi.RunDetail, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.GetWorkflowRun(s.GithubClient, ctx, owner, repo, args.RunID)
+		},
+	})
+	RegisterTool(ToolDef[runLogsArgs, *githubapi.RunLogs]{
+		Name:        "github_get_run_logs",
+		Description: "🔴 Lee los logs de una ejecución: por defecto solo los steps fallidos, con grep y tail (GitHub Actions)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args runLogsArgs) (*githubapi.RunLogs, error) {
+			if args.RunID == 0 && args.JobID == 0 {
+				return nil, fmt.Errorf("indica 'run_id' o 'job_id'")
+			}
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.GetRunLogs(s.GithubClient, ctx, owner, repo, args.RunID, githubapi.LogOptions{
+				JobID: args.JobID,
+				Step:  args.Step,
+				Grep:  args.Grep,
+				Tail:  args.Tail,
+			})
+		},
+	})
+	RegisterTool(ToolDef[rerunArgs, *githubapi.RunAction]{
+		Name:        "github_rerun_run",
+		Description: "Vuelve a ejecutar los jobs fallidos de una ejecución, o todos con all (GitHub Actions)",
+		Annotations: types.ToolAnnotations{OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args rerunArgs) (*githubapi.RunAction, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.RerunWorkflowRun(s.GithubClient, ctx, owner, repo, args.RunID, args.All)
+		},
+	})
+	RegisterTool(ToolDef[runIDArgs, *githubapi.RunAction]{
+		Name:        "github_cancel_run",
+		Description: "Cancela una ejecución en curso (GitHub Actions)",
+		Annotations: types.ToolAnnotations{DestructiveHint: true, IdempotentHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args runIDArgs) (*githubapi.RunAction, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.CancelWorkflowRun(s.GithubClient, ctx, owner, repo, args.RunID)
+		},
+	})
+	RegisterTool(ToolDef[dispatchWorkflowArgs, *githubapi.WorkflowDispatch]{
+		Name:        "github_dispatch_workflow",
+		Description: "Lanza un workflow con workflow_dispatch e inputs (GitHub Actions)",
+		Annotations: types.ToolAnnotations{OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args dispatchWorkflowArgs) (*githubapi.WorkflowDispatch, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			ref := args.Ref
+			if ref == "" {
+				ref = s.Defaults.BaseBranch
+			}
+			return githubapi.DispatchWorkflow(s.GithubClient, ctx, owner, repo, args.Workflow, ref, args.Inputs)
+		},
+	})
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	githubapi "github.com/jotajotape/github-go-server-mcp/internal/github"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// Argumentos de las herramientas de ramas de la GitHub API
+type listBranchesArgs struct {
+	repoArgs
+	Protected bool `json:"protected" desc:"Solo las ramas protegidas"`
+	pageArgs
+}
+
+type createBranchArgs struct {
+	repoArgs
+	Branch string `json:"branch" desc:"Nombre de la rama nueva" required:"true"`
+	From   string `json:"from" desc:"Rama o SHA de partida (default: la rama por defecto del repositorio)"`
+}
+
+type branchArgs struct {
+	repoArgs
+	Branch string `json:"branch" desc:"Nombre de la rama" required:"true"`
+}
+
+type compareArgs struct {
+	repoArgs
+	Base    string `json:"base" desc:"Ref base: rama, tag o SHA (default: base_branch del perfil)"`
+	Head    string `json:"head" desc:"Ref a comparar con la base: rama, tag o SHA (también 'owner:rama' de un fork)" required:"true"`
+	Patches bool   `json:"patches" desc:"Incluir los parches de cada archivo"`
+}
+
+type updateBranchProtectionArgs struct {
+	branchArgs
+	RequireReviews                *bool    `json:"require_reviews" desc:"Exigir revisión aprobada para fusionar (false la quita)"`
+	RequiredApprovals             *int     `json:"required_approvals" desc:"Aprobaciones necesarias" min:"0" max:"6"`
+	DismissStaleReviews           *bool    `json:"dismiss_stale_reviews" desc:"Descartar aprobaciones al subir commits nuevos"`
+	RequireCodeOwnerReviews       *bool    `json:"require_code_owner_reviews" desc:"Exigir la revisión de los CODEOWNERS"`
+	RequireLastPushApproval       *bool    `json:"require_last_push_approval" desc:"Quien hizo el último push no puede aprobar"`
+	RequireStatusChecks           *bool    `json:"require_status_checks" desc:"Exigir checks para fusionar (false los quita)"`
+	StatusChecks                  []string `json:"status_checks" desc:"Nombres de los checks obligatorios (sustituye la lista actual)"`
+	StrictStatusChecks            *bool    `json:"strict_status_checks" desc:"La rama debe estar al día con la base antes de fusionar"`
+	EnforceAdmins                 *bool    `json:"enforce_admins" desc:"Aplicar la protección también a los administradores"`
+	RequireLinearHistory          *bool    `json:"require_linear_history" desc:"Prohibir commits de merge"`
+	AllowForcePushes              *bool    `json:"allow_force_pushes" desc:"Permitir force push"`
+	AllowDeletions                *bool    `json:"allow_deletions" desc:"Permitir borrar la rama"`
+	RequireConversationResolution *bool    `json:"require_conversation_resolution" desc:"Exigir conversaciones resueltas antes de fusionar"`
+	LockBranch                    *bool    `json:"lock_branch" desc:"Rama de solo lectura"`
+	RulesetID                     int64    `json:"ruleset_id" desc:"ID de un ruleset (ver 'rules' en github_get_branch_protection) cuyo modo cambiar" min:"1"`
+	RulesetEnforcement            string   `json:"ruleset_enforcement" desc:"Nuevo modo del ruleset" enum:"active,evaluate,disabled"`
+}
+
+func registerBranchTools() {
+	RegisterTool(ToolDef[listBranchesArgs, *githubapi.BranchList]{
+		Name:        "github_list_branches",
+		Description: "Lista las ramas de un repositorio con su SHA y si están protegidas (GitHub API)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args listBranchesArgs) (*githubapi.BranchList, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.ListBranches(s.GithubClient, ctx, owner, repo, args.Protected, args.options())
+		},
+	})
+	RegisterTool(ToolDef[createBranchArgs, *githubapi.BranchRef]{
+		Name:        "github_create_branch",
+		Description: "Crea una rama remota desde otra rama o un SHA, sin clon local (GitHub API)",
+		Annotations: types.ToolAnnotations{OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args createBranchArgs) (*githubapi.BranchRef, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.CreateBranch(s.GithubClient, ctx, owner, repo, args.Branch, args.From)
+		},
+	})
+	RegisterTool(ToolDef[branchArgs, *githubapi.BranchRef]{
+		Name:        "github_delete_branch",
+		Description: "Borra una rama remota (no la rama por defecto) (GitHub API)",
+		Annotations: types.ToolAnnotations{DestructiveHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args branchArgs) (*githubapi.BranchRef, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.DeleteBranch(s.GithubClient, ctx, owner, repo, args.Branch)
+		},
+	})
+	RegisterTool(ToolDef[compareArgs, *githubapi.Comparison]{
+		Name:        "github_compare",
+		Description: "Compara dos refs: commits por delante/detrás y archivos cambiados (GitHub API)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args compareArgs) (*githubapi.Comparison, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			base := args.Base
+			if base == "" {
+				base = s.Defaults.BaseBranch
+			}
+			if base == "" {
+				return nil, fmt.Errorf("parámetro 'base' requerido (o configura base_branch en el perfil)")
+			}
+			return githubapi.Compare(s.GithubClient, ctx, owner, repo, base, args.Head, args.Patches)
+		},
+	})
+	RegisterTool(ToolDef[branchArgs, *githubapi.BranchProtection]{
+		Name:        "github_get_branch_protection",
+		Description: "Protección de una rama: revisiones y checks obligatorios, restricciones y reglas de rulesets (GitHub API)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args branchArgs) (*githubapi.BranchProtection, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.GetBranchProtection(s.GithubClient, ctx, owner, repo, args.Branch)
+		},
+	})
+	RegisterTool(ToolDef[updateBranchProtectionArgs, *githubapi.BranchProtection]{
+		Name:        "github_update_branch_protection",
+		Description: "Modifica la protección de una rama (solo los campos indicados) o el modo de un ruleset (GitHub API)",
+		Annotations: types.ToolAnnotations{DestructiveHint: true, IdempotentHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args updateBranchProtectionArgs) (*githubapi.BranchProtection, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			if (args.RulesetID == 0) != (args.RulesetEnforcement == "") {
+				return nil, fmt.Errorf("indica 'ruleset_id' y 'ruleset_enforcement' juntos")
+			}
+
+			changes := githubapi.BranchProtectionChanges{
+				RequireReviews:                args.RequireReviews,
+				RequiredApprovals:             args.RequiredApprovals,
+				DismissStaleReviews:           args.DismissStaleReviews,
+				RequireCodeOwnerReviews:       args.RequireCodeOwnerReviews,
+				RequireLastPushApproval:       args.RequireLastPushApproval,
+				RequireStatusChecks:           args.RequireStatusChecks,
+				StrictStatusChecks:            args.StrictStatusChecks,
+				EnforceAdmins:                 args.EnforceAdmins,
+				RequireLinearHistory:          args.RequireLinearHistory,
+				AllowForcePushes:              args.AllowForcePushes,
+				AllowDeletions:                args.AllowDeletions,
+				RequireConversationResolution: args.RequireConversationResolution,
+				LockBranch:                    args.LockBranch,
+				RulesetID:                     args.RulesetID,
+				RulesetEnforcement:            args.RulesetEnforcement,
+			}
+			if args.StatusChecks != nil {
+				changes.StatusChecks = &args.StatusChecks
+			}
+			return githubapi.UpdateBranchProtection(s.GithubClient, ctx, owner, repo, args.Branch, changes)
+		},
+	})
+}
@@ -0,0 +1,203 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/git"
+	githubapi "github.com/jotajotape/github-go-server-mcp/internal/github"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// Argumentos de las herramientas de checks y commit statuses
+type getChecksArgs struct {
+	repoArgs
+	Ref         string `json:"ref" desc:"SHA, rama o tag (default: HEAD del repositorio local si es un clon del repositorio; si no, la rama por defecto)"`
+	Annotations bool   `json:"annotations" desc:"Incluir las anotaciones (archivo/línea) de los check runs" default:"true"`
+}
+
+type checkAnnotationArgs struct {
+	Path        string `json:"path" desc:"Archivo, relativo a la raíz del repositorio o ruta absoluta dentro del workspace" required:"true"`
+	StartLine   int    `json:"start_line" desc:"Primera línea" required:"true" min:"1"`
+	EndLine     int    `json:"end_line" desc:"Última línea (default: start_line)" min:"1"`
+	StartColumn int    `json:"start_column" desc:"Primera columna (solo anotaciones de una línea)" min:"1"`
+	EndColumn   int    `json:"end_column" desc:"Última columna" min:"1"`
+	Level       string `json:"level" desc:"Gravedad" enum:"notice,warning,failure" default:"failure"`
+	Title       string `json:"title" desc:"Título corto (p. ej. la regla del linter)"`
+	Message     string `json:"message" desc:"Mensaje" required:"true"`
+}
+
+type createCheckRunArgs struct {
+	repoArgs
+	Name        string                `json:"name" desc:"Nombre del check (p. ej. golangci-lint)" required:"true"`
+	HeadSHA     string                `json:"head_sha" desc:"SHA del commit (default: HEAD del repositorio local si es un clon del repositorio)" pattern:"^[0-9a-f]{40}$"`
+	Status      string                `json:"status" desc:"Estado (default: completed si hay conclusion, si no in_progress)" enum:"queued,in_progress,completed"`
+	Conclusion  string                `json:"conclusion" desc:"Conclusión (obligatoria con status completed)" enum:"success,failure,neutral,cancelled,skipped,timed_out,action_required"`
+	Title       string                `json:"title" desc:"Título de la salida (default: name)"`
+	Summary     string                `json:"summary" desc:"Resumen de la salida (Markdown)"`
+	Text        string                `json:"text" desc:"Detalle de la salida (Markdown)"`
+	DetailsURL  string                `json:"details_url" desc:"URL con el detalle completo"`
+	ExternalID  string                `json:"external_id" desc:"Identificador propio del check"`
+	Annotations []checkAnnotationArgs `json:"annotations" desc:"Anotaciones en archivos y líneas (se envían en lotes de 50)"`
+}
+
+type createStatusArgs struct {
+	repoArgs
+	SHA         string `json:"sha" desc:"SHA del commit (default: HEAD del repositorio local si es un clon del repositorio)" pattern:"^[0-9a-f]{40}$"`
+	State       string `json:"state" desc:"Estado" enum:"success,failure,error,pending" required:"true"`
+	Context     string `json:"context" desc:"Identificador del status (p. ej. lint/golangci)" default:"local"`
+	Description string `json:"description" desc:"Descripción corta"`
+	TargetURL   string `json:"target_url" desc:"URL con el detalle"`
+}
+
+func registerCheckTools() {
+	RegisterTool(ToolDef[getChecksArgs, *githubapi.ChecksReport]{
+		Name:        "github_get_checks",
+		Description: "✅ Checks de una ref: check runs con anotaciones, check suites y commit statuses, con resumen (GitHub API)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args getChecksArgs) (*githubapi.ChecksReport, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			// Sin ref: el HEAD local si el workspace es un clon de owner/repo; si no, la rama por defecto
+			ref := args.Ref
+			dir, local := localWorkspace(s, owner, repo)
+			if ref == "" && local {
+				if ref, err = localHead(ctx, s, owner, repo, "", "ref"); err != nil {
+					return nil, err
+				}
+			}
+			report, err := githubapi.GetChecks(s.GithubClient, ctx, owner, repo, ref, args.Annotations)
+			if err != nil {
+				return nil, err
+			}
+			if local {
+				for i := range report.CheckRuns {
+					for j := range report.CheckRuns[i].Annotations {
+						annotation := &report.CheckRuns[i].Annotations[j]
+						local := filepath.Join(dir, filepath.FromSlash(annotation.Path))
+						if _, err := os.Stat(local); err == nil {
+							annotation.LocalPath = local
+						}
+					}
+				}
+			}
+			return report, nil
+		},
+	})
+	RegisterTool(ToolDef[createCheckRunArgs, *githubapi.CheckRunInfo]{
+		Name:        "github_create_check_run",
+		Description: "Publica un check run con anotaciones (p. ej. resultados de un linter local); requiere autenticación como GitHub App",
+		Annotations: types.ToolAnnotations{OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args createCheckRunArgs) (*githubapi.CheckRunInfo, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			sha, err := localHead(ctx, s, owner, repo, args.HeadSHA, "head_sha")
+			if err != nil {
+				return nil, err
+			}
+			if args.Status == "completed" && args.Conclusion == "" {
+				return nil, fmt.Errorf("'conclusion' es obligatoria con status completed")
+			}
+
+			dir, _ := localWorkspace(s, owner, repo)
+			annotations := make([]githubapi.CheckAnnotation, 0, len(args.Annotations))
+			for _, a := range args.Annotations {
+				path, err := repoRelativePath(dir, a.Path)
+				if err != nil {
+					return nil, err
+				}
+				if a.EndLine != 0 && a.EndLine < a.StartLine {
+					return nil, fmt.Errorf("anotación en %s: end_line (%d) no puede ser menor que start_line (%d)", path, a.EndLine, a.StartLine)
+				}
+				annotations = append(annotations, githubapi.CheckAnnotation{
+					Path:        path,
+					StartLine:   a.StartLine,
+					EndLine:     a.EndLine,
+					StartColumn: a.StartColumn,
+					EndColumn:   a.EndColumn,
+					Level:       a.Level,
+					Title:       a.Title,
+					Message:     a.Message,
+				})
+			}
+
+			return githubapi.CreateCheckRun(s.GithubClient, ctx, owner, repo, githubapi.CheckRunRequest{
+				Name:        args.Name,
+				HeadSHA:     sha,
+				Status:      args.Status,
+				Conclusion:  args.Conclusion,
+				Title:       args.Title,
+				Summary:     args.Summary,
+				Text:        args.Text,
+				DetailsURL:  args.DetailsURL,
+				ExternalID:  args.ExternalID,
+				Annotations: annotations,
+			})
+		},
+	})
+	RegisterTool(ToolDef[createStatusArgs, *githubapi.CommitStatusInfo]{
+		Name:        "github_create_status",
+		Description: "Publica un commit status (success, failure, error o pending) en un commit (GitHub API)",
+		Annotations: types.ToolAnnotations{IdempotentHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args createStatusArgs) (*githubapi.CommitStatusInfo, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			sha, err := localHead(ctx, s, owner, repo, args.SHA, "sha")
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.CreateStatus(s.GithubClient, ctx, owner, repo, sha, args.State, args.Context, args.Description, args.TargetURL)
+		},
+	})
+}
+
+// localHead devuelve ref o, si está vacía, el SHA del HEAD del repositorio local.
+// El HEAD local solo vale si el workspace es un clon de owner/repo: si no, es un commit de otro repositorio.
+func localHead(ctx context.Context, s *types.MCPServer, owner, repo, ref, param string) (string, error) {
+	if ref != "" {
+		return ref, nil
+	}
+	if _, ok := localWorkspace(s, owner, repo); !ok {
+		return "", fmt.Errorf("parámetro '%s' requerido: el workspace local no es un clon de %s/%s", param, owner, repo)
+	}
+	head, err := git.GetLastCommitSHA(ctx, s.GitSnapshot())
+	if err != nil {
+		return "", fmt.Errorf("parámetro '%s' requerido (no se pudo leer el HEAD local: %v)", param, err)
+	}
+	return head.SHA, nil
+}
+
+// localWorkspace devuelve el directorio local si es un clon de owner/repo
+func localWorkspace(s *types.MCPServer, owner, repo string) (string, bool) {
+	config := s.GitSnapshot()
+	localOwner, localRepo, ok := git.RemoteRepository(config)
+	if !ok || !config.IsGitRepo || !strings.EqualFold(localOwner, owner) || !strings.EqualFold(localRepo, repo) {
+		return "", false
+	}
+	return git.GetEffectiveWorkingDir(config), true
+}
+
+// repoRelativePath convierte una ruta del workspace local en una ruta relativa a la raíz
+// del repositorio, como la esperan las anotaciones
+func repoRelativePath(dir, path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		return strings.TrimPrefix(filepath.ToSlash(filepath.Clean(path)), "./"), nil
+	}
+	if dir == "" {
+		return "", fmt.Errorf("ruta absoluta %s: el workspace local no es un clon del repositorio", path)
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("la ruta %s está fuera del workspace %s", path, dir)
+	}
+	return filepath.ToSlash(rel), nil
+}
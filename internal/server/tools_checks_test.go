@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+func TestLocalHead(t *testing.T) {
+	// Workspace clonado de me/local, sin Git real: el HEAD local no se puede leer
+	s := &types.MCPServer{GitConfig: types.GitConfig{IsGitRepo: true, RemoteHost: "github.com", Owner: "me", Repo: "local", WorkspacePath: t.TempDir()}}
+
+	tests := []struct {
+		name    string
+		owner   string
+		repo    string
+		ref     string
+		want    string
+		wantErr string
+	}{
+		{name: "explicit ref", owner: "other", repo: "repo", ref: "v2", want: "v2"},
+		{name: "other repository", owner: "other", repo: "repo", wantErr: "no es un clon de other/repo"},
+		{name: "same owner, other repo", owner: "me", repo: "repo", wantErr: "no es un clon de me/repo"},
+		{name: "workspace repository", owner: "Me", repo: "Local", wantErr: "HEAD local"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := localHead(context.Background(), s, tt.owner, tt.repo, tt.ref, "sha")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("localHead = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	githubapi "github.com/jotajotape/github-go-server-mcp/internal/github"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// Argumentos de las herramientas de contenido remoto de los repositorios
+type getFileArgs struct {
+	repoArgs
+	Path string `json:"path" desc:"Ruta del archivo" required:"true"`
+	Ref  string `json:"ref" desc:"Rama, tag o SHA (default: la rama por defecto del repositorio)"`
+}
+
+type listDirectoryArgs struct {
+	repoArgs
+	Path string `json:"path" desc:"Ruta del directorio (default: la raíz)"`
+	Ref  string `json:"ref" desc:"Rama, tag o SHA (default: la rama por defecto del repositorio)"`
+}
+
+type getTreeArgs struct {
+	repoArgs
+	Ref       string `json:"ref" desc:"Rama, tag o SHA (default: la rama por defecto del repositorio)"`
+	Path      string `json:"path" desc:"Limitar el árbol a un directorio"`
+	Recursive bool   `json:"recursive" desc:"Incluir los subdirectorios" default:"true"`
+}
+
+type deleteFileArgs struct {
+	repoArgs
+	Path    string `json:"path" desc:"Ruta del archivo" required:"true"`
+	Message string `json:"message" desc:"Mensaje del commit (default: 'Delete <path>')"`
+	SHA     string `json:"sha" desc:"SHA del blob a borrar (default: el del archivo en la rama); falla si el archivo ha cambiado" pattern:"^[0-9a-f]{40}$"`
+	Branch  string `json:"branch" desc:"Rama (default: base_branch del perfil o la rama por defecto del repositorio)"`
+}
+
+type fileChangeArgs struct {
+	Action   string `json:"action" desc:"Operación sobre el archivo" enum:"create,update,delete,rename" required:"true"`
+	Path     string `json:"path" desc:"Ruta del archivo (en rename, la ruta nueva)" required:"true"`
+	FromPath string `json:"from_path" desc:"Ruta original del archivo (solo rename)"`
+	Content  string `json:"content" desc:"Contenido (create y update; en rename, opcional para cambiarlo a la vez)"`
+	Base64   bool   `json:"base64" desc:"content viene codificado en base64 (archivos binarios)"`
+}
+
+type commitFilesArgs struct {
+	repoArgs
+	Branch          string           `json:"branch" desc:"Rama (default: base_branch del perfil o la rama por defecto del repositorio)"`
+	Message         string           `json:"message" desc:"Mensaje del commit" required:"true"`
+	Changes         []fileChangeArgs `json:"changes" desc:"Operaciones del commit" required:"true"`
+	ExpectedHeadSHA string           `json:"expected_head_sha" desc:"SHA esperado de la cabeza de la rama: no se hace el commit si ha avanzado" pattern:"^[0-9a-f]{40}$"`
+}
+
+func registerContentTools() {
+	RegisterTool(ToolDef[getFileArgs, *githubapi.RemoteFile]{
+		Name:        "github_get_file",
+		Description: "Lee un archivo del repositorio remoto con el SHA de su blob (el 'sha' de update_file); binarios en base64",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args getFileArgs) (*githubapi.RemoteFile, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.GetFile(s.GithubClient, ctx, owner, repo, args.Path, args.Ref)
+		},
+	})
+	RegisterTool(ToolDef[listDirectoryArgs, *githubapi.DirectoryListing]{
+		Name:        "github_list_directory",
+		Description: "Lista un directorio del repositorio remoto (archivos, subdirectorios, tamaños y SHA)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args listDirectoryArgs) (*githubapi.DirectoryListing, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.ListDirectory(s.GithubClient, ctx, owner, repo, args.Path, args.Ref)
+		},
+	})
+	RegisterTool(ToolDef[getTreeArgs, *githubapi.TreeListing]{
+		Name:        "github_get_tree",
+		Description: "Árbol de archivos del repositorio remoto en una ref, recursivo por defecto (Git Data API)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args getTreeArgs) (*githubapi.TreeListing, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.GetTree(s.GithubClient, ctx, owner, repo, args.Ref, args.Path, args.Recursive)
+		},
+	})
+	RegisterTool(ToolDef[deleteFileArgs, *githubapi.FileCommit]{
+		Name:        "github_delete_file",
+		Description: "Borra un archivo del repositorio remoto con un commit (GitHub API)",
+		Annotations: types.ToolAnnotations{DestructiveHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args deleteFileArgs) (*githubapi.FileCommit, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			message := args.Message
+			if message == "" {
+				message = fmt.Sprintf("Delete %s", args.Path)
+			}
+			branch := args.Branch
+			if branch == "" {
+				branch = s.Defaults.BaseBranch
+			}
+			return githubapi.DeleteFile(s.GithubClient, ctx, owner, repo, args.Path, message, args.SHA, branch)
+		},
+	})
+	RegisterTool(ToolDef[commitFilesArgs, *githubapi.FilesCommit]{
+		Name:        "github_commit_files",
+		Description: "Crea, modifica, borra o renombra varios archivos en un único commit remoto (Git Data API), sin clon local",
+		Annotations: types.ToolAnnotations{DestructiveHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args commitFilesArgs) (*githubapi.FilesCommit, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			branch := args.Branch
+			if branch == "" {
+				branch = s.Defaults.BaseBranch
+			}
+
+			changes := make([]githubapi.FileChange, 0, len(args.Changes))
+			for _, change := range args.Changes {
+				changes = append(changes, githubapi.FileChange{
+					Action:   change.Action,
+					Path:     change.Path,
+					FromPath: change.FromPath,
+					Content:  change.Content,
+					Base64:   change.Base64,
+				})
+			}
+			return githubapi.CommitFiles(s.GithubClient, ctx, owner, repo, githubapi.CommitFilesRequest{
+				Branch:          branch,
+				Message:         args.Message,
+				Changes:         changes,
+				ExpectedHeadSHA: args.ExpectedHeadSHA,
+			})
+		},
+	})
+}
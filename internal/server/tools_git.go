@@ -0,0 +1,277 @@
+package server
+
+import (
+	"context"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/git"
+	"github.com/jotajotape/github-go-server-mcp/internal/hybrid"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// Argumentos de las herramientas Git
+type setWorkspaceArgs struct {
+	Path   string `json:"path" desc:"Ruta del directorio del repositorio Git" required:"true"`
+	Remote string `json:"remote,omitempty" desc:"Remoto del que se deducen owner/repo para las herramientas github_* (p. ej. upstream en un fork; default: el actual, origin)"`
+}
+
+type filePathArgs struct {
+	Path string `json:"path" desc:"Ruta del archivo" required:"true"`
+}
+
+type fileContentArgs struct {
+	Path string `json:"path" desc:"Ruta del archivo" required:"true"`
+	Ref  string `json:"ref" desc:"Referencia Git (branch, commit, tag)" default:"HEAD"`
+}
+
+type changedFilesArgs struct {
+	Staged bool `json:"staged" desc:"Mostrar archivos en staging (true) o working directory (false)"`
+}
+
+type validateRepoArgs struct {
+	Path string `json:"path" desc:"Ruta del directorio a validar" required:"true"`
+}
+
+type listFilesArgs struct {
+	Ref string `json:"ref" desc:"Referencia Git (branch, commit, tag)" default:"HEAD"`
+}
+
+type addArgs struct {
+	Files []string `json:"files" desc:"Archivos a agregar (\".\" para todos)" required:"true"`
+}
+
+type commitArgs struct {
+	Message string `json:"message" desc:"Mensaje del commit" required:"true"`
+}
+
+type pushArgs struct {
+	Branch string `json:"branch" desc:"Rama a subir (opcional, usa actual)"`
+}
+
+type pullArgs struct {
+	Branch string `json:"branch" desc:"Rama a bajar (opcional, usa actual)"`
+}
+
+type checkoutArgs struct {
+	Branch string `json:"branch" desc:"Nombre de la rama" required:"true"`
+	Create bool   `json:"create" desc:"Crear nueva rama"`
+}
+
+type logAnalysisArgs struct {
+	Limit int `json:"limit" desc:"Número de commits a mostrar" default:"20" min:"1" max:"1000"`
+}
+
+type diffFilesArgs struct {
+	Staged bool `json:"staged" desc:"Mostrar archivos en staging" default:"false"`
+}
+
+type branchListArgs struct {
+	Remote bool `json:"remote" desc:"Incluir ramas remotas" default:"false"`
+}
+
+type stashArgs struct {
+	Operation string `json:"operation" desc:"Operación de stash" enum:"list,push,pop,apply,drop,clear" required:"true"`
+	Name      string `json:"name" desc:"Nombre del stash (opcional)"`
+}
+
+type remoteArgs struct {
+	Operation string `json:"operation" desc:"Operación sobre remotos" enum:"list,add,remove,show,fetch" required:"true"`
+	Name      string `json:"name" desc:"Nombre del remoto"`
+	URL       string `json:"url" desc:"URL del remoto (para add)"`
+}
+
+type tagArgs struct {
+	Operation string `json:"operation" desc:"Operación sobre tags" enum:"list,create,delete,push,show" required:"true"`
+	TagName   string `json:"tag_name" desc:"Nombre del tag"`
+	Message   string `json:"message" desc:"Mensaje del tag (para create)"`
+}
+
+type cleanArgs struct {
+	Operation string `json:"operation" desc:"Tipo de limpieza" enum:"untracked,untracked_dirs,ignored,all" required:"true"`
+	DryRun    bool   `json:"dry_run" desc:"Vista previa sin ejecutar" default:"true"`
+}
+
+func registerGitTools() {
+	// Herramientas de información
+	RegisterTool(ToolDef[noArgs, *git.StatusResult]{
+		Name:        "git_status",
+		Description: "Muestra el estado del repositorio Git local y configuración",
+		Annotations: readOnlyLocal,
+		Handler: func(ctx context.Context, s *types.MCPServer, _ noArgs) (*git.StatusResult, error) {
+			return git.Status(ctx, s.GitSnapshot())
+		},
+	})
+	RegisterTool(ToolDef[setWorkspaceArgs, *git.WorkspaceResult]{
+		Name:        "git_set_workspace",
+		Description: "🔧 Configura el directorio de trabajo para operaciones Git",
+		Annotations: types.ToolAnnotations{IdempotentHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args setWorkspaceArgs) (*git.WorkspaceResult, error) {
+			var result *git.WorkspaceResult
+			err := s.UpdateGitConfig(func(config *types.GitConfig) (err error) {
+				result, err = git.SetWorkspace(ctx, config, args.Path, args.Remote)
+				return err
+			})
+			return result, err
+		},
+	})
+	RegisterTool(ToolDef[filePathArgs, *git.FileSHAResult]{
+		Name:        "git_get_file_sha",
+		Description: "🔑 Obtiene el SHA de un archivo específico desde Git",
+		Annotations: readOnlyLocal,
+		Handler: func(ctx context.Context, s *types.MCPServer, args filePathArgs) (*git.FileSHAResult, error) {
+			return git.GetFileSHA(ctx, s.GitSnapshot(), args.Path)
+		},
+	})
+	RegisterTool(ToolDef[noArgs, *git.CommitSHAResult]{
+		Name:        "git_get_last_commit",
+		Description: "🔑 Obtiene el SHA del último commit",
+		Annotations: readOnlyLocal,
+		Handler: func(ctx context.Context, s *types.MCPServer, _ noArgs) (*git.CommitSHAResult, error) {
+			return git.GetLastCommitSHA(ctx, s.GitSnapshot())
+		},
+	})
+	RegisterTool(ToolDef[fileContentArgs, *git.FileContentResult]{
+		Name:        "git_get_file_content",
+		Description: "📄 Obtiene el contenido de un archivo desde Git",
+		Annotations: readOnlyLocal,
+		Handler: func(ctx context.Context, s *types.MCPServer, args fileContentArgs) (*git.FileContentResult, error) {
+			return git.GetFileContent(ctx, s.GitSnapshot(), args.Path, args.Ref)
+		},
+	})
+	RegisterTool(ToolDef[changedFilesArgs, *git.ChangedFilesResult]{
+		Name:        "git_get_changed_files",
+		Description: "📋 Lista archivos modificados en working directory o staging area",
+		Annotations: readOnlyLocal,
+		Handler: func(ctx context.Context, s *types.MCPServer, args changedFilesArgs) (*git.ChangedFilesResult, error) {
+			return git.GetChangedFiles(ctx, s.GitSnapshot(), args.Staged)
+		},
+	})
+	RegisterTool(ToolDef[validateRepoArgs, *git.RepositoryInfo]{
+		Name:        "git_validate_repo",
+		Description: "✅ Valida si un directorio es un repositorio Git válido",
+		Annotations: readOnlyLocal,
+		Handler: func(ctx context.Context, s *types.MCPServer, args validateRepoArgs) (*git.RepositoryInfo, error) {
+			return git.ValidateRepository(ctx, args.Path)
+		},
+	})
+	RegisterTool(ToolDef[listFilesArgs, *git.FileListResult]{
+		Name:        "git_list_files",
+		Description: "📄 Lista todos los archivos en el repositorio Git",
+		Annotations: readOnlyLocal,
+		Handler: func(ctx context.Context, s *types.MCPServer, args listFilesArgs) (*git.FileListResult, error) {
+			return git.ListFiles(ctx, s.GitSnapshot(), args.Ref)
+		},
+	})
+
+	// Herramientas Git locales básicas
+	RegisterTool(ToolDef[addArgs, *git.AddResult]{
+		Name:        "git_add",
+		Description: "Agrega archivos al staging area (requiere Git local)",
+		Annotations: types.ToolAnnotations{IdempotentHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args addArgs) (*git.AddResult, error) {
+			return git.Add(ctx, s.GitSnapshot(), args.Files)
+		},
+	})
+	RegisterTool(ToolDef[commitArgs, *git.CommitResult]{
+		Name:        "git_commit",
+		Description: "Hace commit de los cambios en staging (requiere Git local)",
+		Annotations: types.ToolAnnotations{},
+		Handler: func(ctx context.Context, s *types.MCPServer, args commitArgs) (*git.CommitResult, error) {
+			return git.Commit(ctx, s.GitSnapshot(), args.Message)
+		},
+	})
+	RegisterTool(ToolDef[pushArgs, *git.PushResult]{
+		Name:        "git_push",
+		Description: "Sube cambios al repositorio remoto (requiere Git local)",
+		Annotations: types.ToolAnnotations{DestructiveHint: true, IdempotentHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args pushArgs) (*git.PushResult, error) {
+			return git.Push(ctx, s.GitSnapshot(), args.Branch)
+		},
+	})
+	RegisterTool(ToolDef[pullArgs, *git.PullResult]{
+		Name:        "git_pull",
+		Description: "Baja cambios del repositorio remoto (requiere Git local)",
+		Annotations: types.ToolAnnotations{DestructiveHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args pullArgs) (*git.PullResult, error) {
+			return git.Pull(ctx, s.GitSnapshot(), args.Branch)
+		},
+	})
+	RegisterTool(ToolDef[checkoutArgs, *git.CheckoutResult]{
+		Name:        "git_checkout",
+		Description: "Cambia de rama o crea nueva rama (requiere Git local)",
+		Annotations: types.ToolAnnotations{},
+		Handler: func(ctx context.Context, s *types.MCPServer, args checkoutArgs) (*git.CheckoutResult, error) {
+			var result *git.CheckoutResult
+			err := s.UpdateGitConfig(func(config *types.GitConfig) (err error) {
+				result, err = git.Checkout(ctx, config, args.Branch, args.Create)
+				return err
+			})
+			return result, err
+		},
+	})
+
+	// Herramientas Git avanzadas
+	RegisterTool(ToolDef[logAnalysisArgs, *git.LogAnalysisResult]{
+		Name:        "git_log_analysis",
+		Description: "Análisis completo del historial de commits",
+		Annotations: readOnlyLocal,
+		Handler: func(ctx context.Context, s *types.MCPServer, args logAnalysisArgs) (*git.LogAnalysisResult, error) {
+			return git.LogAnalysis(ctx, s.GitSnapshot(), args.Limit)
+		},
+	})
+	RegisterTool(ToolDef[diffFilesArgs, *git.DiffFilesResult]{
+		Name:        "git_diff_files",
+		Description: "Muestra archivos modificados con estadísticas",
+		Annotations: readOnlyLocal,
+		Handler: func(ctx context.Context, s *types.MCPServer, args diffFilesArgs) (*git.DiffFilesResult, error) {
+			return git.DiffFiles(ctx, s.GitSnapshot(), args.Staged)
+		},
+	})
+	RegisterTool(ToolDef[branchListArgs, *git.BranchListResult]{
+		Name:        "git_branch_list",
+		Description: "Lista todas las ramas con información detallada",
+		Annotations: readOnlyLocal,
+		Handler: func(ctx context.Context, s *types.MCPServer, args branchListArgs) (*git.BranchListResult, error) {
+			return git.BranchList(ctx, s.GitSnapshot(), args.Remote)
+		},
+	})
+	RegisterTool(ToolDef[stashArgs, *git.OperationResult]{
+		Name:        "git_stash",
+		Description: "Operaciones de stash (guardar cambios temporalmente)",
+		Annotations: types.ToolAnnotations{DestructiveHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args stashArgs) (*git.OperationResult, error) {
+			return git.StashOperations(ctx, s.GitSnapshot(), args.Operation, args.Name)
+		},
+	})
+	RegisterTool(ToolDef[remoteArgs, *git.OperationResult]{
+		Name:        "git_remote",
+		Description: "Gestión de repositorios remotos",
+		Annotations: types.ToolAnnotations{DestructiveHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args remoteArgs) (*git.OperationResult, error) {
+			return git.RemoteOperations(ctx, s.GitSnapshot(), args.Operation, args.Name, args.URL)
+		},
+	})
+	RegisterTool(ToolDef[tagArgs, *git.OperationResult]{
+		Name:        "git_tag",
+		Description: "Gestión de tags/etiquetas",
+		Annotations: types.ToolAnnotations{DestructiveHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args tagArgs) (*git.OperationResult, error) {
+			return git.TagOperations(ctx, s.GitSnapshot(), args.Operation, args.TagName, args.Message)
+		},
+	})
+	RegisterTool(ToolDef[cleanArgs, *git.CleanResult]{
+		Name:        "git_clean",
+		Description: "Limpieza de archivos sin seguimiento",
+		Annotations: types.ToolAnnotations{DestructiveHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args cleanArgs) (*git.CleanResult, error) {
+			return git.CleanOperations(ctx, s.GitSnapshot(), args.Operation, args.DryRun)
+		},
+	})
+	RegisterTool(ToolDef[noArgs, *hybrid.ContextResult]{
+		Name:        "git_context",
+		Description: "🔧 Auto-detecta contexto Git para optimizar tokens (Git local vs GitHub API)",
+		Annotations: readOnlyLocal,
+		Handler: func(ctx context.Context, s *types.MCPServer, _ noArgs) (*hybrid.ContextResult, error) {
+			return hybrid.AutoDetectContext(s.GitSnapshot()), nil
+		},
+	})
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/git"
+	githubapi "github.com/jotajotape/github-go-server-mcp/internal/github"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// Argumentos de las herramientas de la GitHub API
+type listReposArgs struct {
+	Type string `json:"type" desc:"Tipo de repositorios" enum:"all,owner,public,private,member" default:"all"`
+	pageArgs
+}
+
+type createRepoArgs struct {
+	Name               string `json:"name" desc:"Nombre del repositorio" required:"true"`
+	Description        string `json:"description" desc:"Descripción del repositorio"`
+	Private            bool   `json:"private" desc:"Repositorio privado"`
+	Org                string `json:"org" desc:"Organización en la que crearlo (default: la cuenta del usuario)" pattern:"^[A-Za-z0-9-]+$"`
+	Template           string `json:"template" desc:"Repositorio plantilla 'owner/repo' del que copiar los archivos" pattern:"^[A-Za-z0-9-]+/[A-Za-z0-9._-]+$"`
+	IncludeAllBranches bool   `json:"include_all_branches" desc:"Copiar todas las ramas de la plantilla, no solo la rama por defecto"`
+}
+
+// repoArgs identifica el repositorio de las herramientas github_*; se embebe en sus argumentos
+type repoArgs struct {
+	Owner string `json:"owner" desc:"Propietario del repositorio (default: el del remoto del workspace o el del perfil)" pattern:"^[A-Za-z0-9-]+$"`
+	Repo  string `json:"repo" desc:"Nombre del repositorio (default: el del remoto del workspace o el del perfil)" pattern:"^[A-Za-z0-9._-]+$"`
+}
+
+// resolve completa owner y repo con los valores por defecto (ver resolveRepo)
+func (a repoArgs) resolve(s *types.MCPServer) (string, string, error) {
+	return resolveRepo(s, a.Owner, a.Repo)
+}
+
+// pageArgs son los parámetros de paginación de los listados de la GitHub API
+type pageArgs struct {
+	Page     int    `json:"page" desc:"Página de resultados" default:"1" min:"1"`
+	PerPage  int    `json:"per_page" desc:"Resultados por página de la API" default:"30" min:"1" max:"100"`
+	MaxItems int    `json:"max_items" desc:"Máximo de resultados a reunir siguiendo las páginas siguientes (default: una página)" min:"1" max:"1000"`
+	Cursor   string `json:"cursor" desc:"nextCursor de un listado anterior para continuar donde se cortó (sustituye a page y per_page)"`
+}
+
+func (a pageArgs) options() githubapi.PageOptions {
+	return githubapi.PageOptions{Page: a.Page, PerPage: a.PerPage, MaxItems: a.MaxItems, Cursor: a.Cursor}
+}
+
+// prNumberArgs identifica un pull request
+type prNumberArgs struct {
+	repoArgs
+	Number int `json:"number" desc:"Número del pull request" required:"true" min:"1"`
+}
+
+type listPRsArgs struct {
+	repoArgs
+	State string `json:"state" desc:"Estado de los PRs" enum:"open,closed,all" default:"open"`
+	pageArgs
+}
+
+type createPRArgs struct {
+	repoArgs
+	Title string `json:"title" desc:"Título del PR" required:"true"`
+	Body  string `json:"body" desc:"Descripción del PR"`
+	Head  string `json:"head" desc:"Rama origen" required:"true"`
+	Base  string `json:"base" desc:"Rama destino (default: base_branch del perfil)"`
+}
+
+func registerGitHubTools() {
+	RegisterTool(ToolDef[listReposArgs, *githubapi.RepositoryList]{
+		Name:        "github_list_repos",
+		Description: "Lista repositorios del usuario (GitHub API)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args listReposArgs) (*githubapi.RepositoryList, error) {
+			return githubapi.ListRepositories(s.GithubClient, ctx, args.Type, args.options())
+		},
+	})
+	RegisterTool(ToolDef[createRepoArgs, *githubapi.RepositoryCreated]{
+		Name:        "github_create_repo",
+		Description: "Crea un nuevo repositorio, vacío o desde una plantilla, en la cuenta del usuario o en una organización (GitHub API)",
+		Annotations: types.ToolAnnotations{OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args createRepoArgs) (*githubapi.RepositoryCreated, error) {
+			if args.Template == "" {
+				if args.IncludeAllBranches {
+					return nil, fmt.Errorf("'include_all_branches' solo se usa con 'template'")
+				}
+				return githubapi.CreateRepository(s.GithubClient, ctx, args.Org, args.Name, args.Description, args.Private)
+			}
+			templateOwner, templateRepo, _ := strings.Cut(args.Template, "/")
+			return githubapi.CreateRepositoryFromTemplate(s.GithubClient, ctx, githubapi.RepositoryTemplate{
+				TemplateOwner:      templateOwner,
+				TemplateRepo:       templateRepo,
+				Owner:              args.Org,
+				Name:               args.Name,
+				Description:        args.Description,
+				Private:            args.Private,
+				IncludeAllBranches: args.IncludeAllBranches,
+			})
+		},
+	})
+	RegisterTool(ToolDef[listPRsArgs, *githubapi.PullRequestList]{
+		Name:        "github_list_prs",
+		Description: "Lista pull requests (GitHub API)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args listPRsArgs) (*githubapi.PullRequestList, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.ListPullRequests(s.GithubClient, ctx, owner, repo, args.State, args.options())
+		},
+	})
+	RegisterTool(ToolDef[createPRArgs, *githubapi.PullRequestCreated]{
+		Name:        "github_create_pr",
+		Description: "Crea pull request (GitHub API)",
+		Annotations: types.ToolAnnotations{OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args createPRArgs) (*githubapi.PullRequestCreated, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			base := args.Base
+			if base == "" {
+				base = s.Defaults.BaseBranch
+			}
+			if base == "" {
+				return nil, fmt.Errorf("parámetro 'base' requerido (o configura base_branch en el perfil)")
+			}
+			return githubapi.CreatePullRequest(s.GithubClient, ctx, owner, repo, args.Title, args.Body, args.Head, base)
+		},
+	})
+	RegisterTool(ToolDef[noArgs, *githubapi.RateLimitReport]{
+		Name:        "github_rate_limit",
+		Description: "Cupo restante de la GitHub API por recurso (core, search, graphql...); la consulta no consume cupo",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, _ noArgs) (*githubapi.RateLimitReport, error) {
+			return githubapi.RateLimits(s.GithubClient, ctx)
+		},
+	})
+}
+
+// resolveRepo completa owner y repo no indicados con los del workspace o el perfil (ver defaultRepo).
+// Si solo se indica repo, el owner se toma del perfil.
+func resolveRepo(s *types.MCPServer, owner, repo string) (string, string, error) {
+	if owner == "" && repo == "" {
+		owner, repo = defaultRepo(s)
+	}
+	if owner == "" {
+		owner = s.Defaults.Owner
+	}
+	if owner == "" || repo == "" {
+		return "", "", fmt.Errorf("parámetros 'owner' y 'repo' requeridos (o configura un workspace con remoto de GitHub u owner/repo en el perfil)")
+	}
+	return owner, repo, nil
+}
+
+// defaultRepo devuelve el repositorio por defecto: el del remoto del workspace configurado,
+// el owner/repo del perfil o, en último lugar, el del remoto del directorio actual
+func defaultRepo(s *types.MCPServer) (string, string) {
+	config := s.GitSnapshot()
+	remoteOwner, remoteRepo, ok := git.RemoteRepository(config)
+
+	switch {
+	case ok && config.WorkspacePath != "":
+		return remoteOwner, remoteRepo
+	case s.Defaults.Owner != "" && s.Defaults.Repo != "":
+		return s.Defaults.Owner, s.Defaults.Repo
+	case ok:
+		return remoteOwner, remoteRepo
+	}
+	return "", ""
+}
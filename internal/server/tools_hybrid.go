@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/hybrid"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// Argumentos de las herramientas híbridas
+type createFileArgs struct {
+	Path    string `json:"path" desc:"Ruta del archivo" required:"true"`
+	Content string `json:"content" desc:"Contenido del archivo" required:"true"`
+	Message string `json:"message" desc:"Mensaje del commit (opcional para Git local)"`
+	Owner   string `json:"owner" desc:"Propietario (SOLO si falla Git local). Default: el del remoto del workspace o el del perfil"`
+	Repo    string `json:"repo" desc:"Repositorio (SOLO si falla Git local). Default: el del remoto del workspace o el del perfil"`
+	Branch  string `json:"branch" desc:"Rama destino (SOLO si falla Git local). Default: base_branch del perfil o main"`
+}
+
+type updateFileArgs struct {
+	Path    string `json:"path" desc:"Ruta del archivo" required:"true"`
+	Content string `json:"content" desc:"Nuevo contenido" required:"true"`
+	Message string `json:"message" desc:"Mensaje del commit (opcional para Git local)"`
+	Owner   string `json:"owner" desc:"Propietario (SOLO si falla Git local). Default: el del remoto del workspace o el del perfil"`
+	Repo    string `json:"repo" desc:"Repositorio (SOLO si falla Git local). Default: el del remoto del workspace o el del perfil"`
+	SHA     string `json:"sha" desc:"SHA del archivo (SOLO si falla Git local)"`
+	Branch  string `json:"branch" desc:"Rama destino (SOLO si falla Git local). Default: base_branch del perfil o main"`
+}
+
+func registerHybridTools() {
+	RegisterTool(ToolDef[createFileArgs, *hybrid.FileResult]{
+		Name:        "create_file",
+		Description: "✅ Crea archivo PRIORIZANDO Git local (0 tokens) sobre GitHub API",
+		Annotations: types.ToolAnnotations{DestructiveHint: true, IdempotentHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args createFileArgs) (*hybrid.FileResult, error) {
+			return hybrid.SmartCreateFile(ctx, s.GitSnapshot(), s.GithubClient, withDefaults(s, hybrid.FileRequest{
+				Path:    args.Path,
+				Content: args.Content,
+				Message: args.Message,
+				Owner:   args.Owner,
+				Repo:    args.Repo,
+				Branch:  args.Branch,
+			}))
+		},
+	})
+	RegisterTool(ToolDef[updateFileArgs, *hybrid.FileResult]{
+		Name:        "update_file",
+		Description: "✅ Actualiza archivo PRIORIZANDO Git local (0 tokens) sobre GitHub API",
+		Annotations: types.ToolAnnotations{DestructiveHint: true, IdempotentHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args updateFileArgs) (*hybrid.FileResult, error) {
+			return hybrid.SmartUpdateFile(ctx, s.GitSnapshot(), s.GithubClient, withDefaults(s, hybrid.FileRequest{
+				Path:    args.Path,
+				Content: args.Content,
+				Message: args.Message,
+				Owner:   args.Owner,
+				Repo:    args.Repo,
+				SHA:     args.SHA,
+				Branch:  args.Branch,
+			}))
+		},
+	})
+}
+
+// withDefaults completa owner, repo y rama de la petición con los del workspace o el perfil
+func withDefaults(s *types.MCPServer, req hybrid.FileRequest) hybrid.FileRequest {
+	if req.Owner == "" && req.Repo == "" {
+		req.Owner, req.Repo = defaultRepo(s)
+	}
+	if req.Owner == "" {
+		req.Owner = s.Defaults.Owner
+	}
+	if req.Branch == "" {
+		req.Branch = s.Defaults.BaseBranch
+	}
+	return req
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+
+	githubapi "github.com/jotajotape/github-go-server-mcp/internal/github"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// Argumentos de las herramientas de issues
+type listIssuesArgs struct {
+	repoArgs
+	State     string   `json:"state" desc:"Estado de los issues" enum:"open,closed,all" default:"open"`
+	Labels    []string `json:"labels" desc:"Solo issues con todas estas etiquetas"`
+	Assignee  string   `json:"assignee" desc:"Login del asignado, 'none' (sin asignar) o '*' (cualquiera)"`
+	Milestone string   `json:"milestone" desc:"Número del milestone, 'none' o '*'"`
+	Since     string   `json:"since" desc:"Solo issues actualizados desde esta fecha (RFC 3339, p. ej. 2024-01-31T00:00:00Z)"`
+	pageArgs
+}
+
+type issueNumberArgs struct {
+	repoArgs
+	Number int `json:"number" desc:"Número del issue" required:"true" min:"1"`
+}
+
+type getIssueArgs struct {
+	issueNumberArgs
+	Comments bool `json:"comments" desc:"Incluir los comentarios" default:"true"`
+}
+
+type createIssueArgs struct {
+	repoArgs
+	Title     string   `json:"title" desc:"Título del issue" required:"true"`
+	Body      string   `json:"body" desc:"Descripción del issue (Markdown)"`
+	Labels    []string `json:"labels" desc:"Etiquetas"`
+	Assignees []string `json:"assignees" desc:"Logins de los asignados"`
+	Milestone int      `json:"milestone" desc:"Número del milestone" min:"1"`
+}
+
+// updateIssueArgs usa punteros: solo se modifican los campos presentes
+type updateIssueArgs struct {
+	issueNumberArgs
+	Title       *string   `json:"title" desc:"Nuevo título"`
+	Body        *string   `json:"body" desc:"Nueva descripción"`
+	State       *string   `json:"state" desc:"Nuevo estado" enum:"open,closed"`
+	StateReason *string   `json:"state_reason" desc:"Motivo del cambio de estado" enum:"completed,not_planned,reopened"`
+	Labels      *[]string `json:"labels" desc:"Etiquetas (reemplaza las actuales; [] las quita todas)"`
+	Assignees   *[]string `json:"assignees" desc:"Asignados (reemplaza los actuales; [] los quita todos)"`
+	Milestone   *int      `json:"milestone" desc:"Número del milestone (0 lo quita)" min:"0"`
+}
+
+type commentIssueArgs struct {
+	issueNumberArgs
+	Body string `json:"body" desc:"Texto del comentario (Markdown)" required:"true"`
+}
+
+type lockIssueArgs struct {
+	issueNumberArgs
+	Reason string `json:"reason" desc:"Motivo del bloqueo" enum:"off-topic,too heated,resolved,spam"`
+	Unlock bool   `json:"unlock" desc:"Desbloquear en lugar de bloquear"`
+}
+
+func registerIssueTools() {
+	RegisterTool(ToolDef[listIssuesArgs, *githubapi.IssueList]{
+		Name:        "github_list_issues",
+		Description: "Lista issues de un repositorio con filtros (GitHub API)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args listIssuesArgs) (*githubapi.IssueList, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.ListIssues(s.GithubClient, ctx, owner, repo, githubapi.IssueFilter{
+				State:       args.State,
+				Labels:      args.Labels,
+				Assignee:    args.Assignee,
+				Milestone:   args.Milestone,
+				Since:       args.Since,
+				PageOptions: args.options(),
+			})
+		},
+	})
+	RegisterTool(ToolDef[getIssueArgs, *githubapi.IssueDetail]{
+		Name:        "github_get_issue",
+		Description: "Obtiene un issue con su descripción y comentarios (GitHub API)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args getIssueArgs) (*githubapi.IssueDetail, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.GetIssue(s.GithubClient, ctx, owner, repo, args.Number, args.Comments)
+		},
+	})
+	RegisterTool(ToolDef[createIssueArgs, *githubapi.IssueDetail]{
+		Name:        "github_create_issue",
+		Description: "Crea un issue (GitHub API)",
+		Annotations: types.ToolAnnotations{OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args createIssueArgs) (*githubapi.IssueDetail, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			fields := githubapi.IssueChanges{Title: &args.Title}
+			if args.Body != "" {
+				fields.Body = &args.Body
+			}
+			if len(args.Labels) > 0 {
+				fields.Labels = &args.Labels
+			}
+			if len(args.Assignees) > 0 {
+				fields.Assignees = &args.Assignees
+			}
+			if args.Milestone != 0 {
+				fields.Milestone = &args.Milestone
+			}
+			return githubapi.CreateIssue(s.GithubClient, ctx, owner, repo, fields)
+		},
+	})
+	RegisterTool(ToolDef[updateIssueArgs, *githubapi.IssueDetail]{
+		Name:        "github_update_issue",
+		Description: "Modifica un issue: título, descripción, estado, etiquetas, asignados o milestone (GitHub API)",
+		Annotations: types.ToolAnnotations{DestructiveHint: true, IdempotentHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args updateIssueArgs) (*githubapi.IssueDetail, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.UpdateIssue(s.GithubClient, ctx, owner, repo, args.Number, githubapi.IssueChanges{
+				Title:       args.Title,
+				Body:        args.Body,
+				State:       args.State,
+				StateReason: args.StateReason,
+				Labels:      args.Labels,
+				Assignees:   args.Assignees,
+				Milestone:   args.Milestone,
+			})
+		},
+	})
+	RegisterTool(ToolDef[commentIssueArgs, *githubapi.IssueCommentCreated]{
+		Name:        "github_comment_issue",
+		Description: "Añade un comentario a un issue o pull request (GitHub API)",
+		Annotations: types.ToolAnnotations{OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args commentIssueArgs) (*githubapi.IssueCommentCreated, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.CommentIssue(s.GithubClient, ctx, owner, repo, args.Number, args.Body)
+		},
+	})
+	RegisterTool(ToolDef[lockIssueArgs, *githubapi.IssueLock]{
+		Name:        "github_lock_issue",
+		Description: "Bloquea (o desbloquea) la conversación de un issue (GitHub API)",
+		Annotations: types.ToolAnnotations{IdempotentHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args lockIssueArgs) (*githubapi.IssueLock, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.LockIssue(s.GithubClient, ctx, owner, repo, args.Number, !args.Unlock, args.Reason)
+		},
+	})
+}
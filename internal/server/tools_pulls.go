@@ -0,0 +1,231 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	githubapi "github.com/jotajotape/github-go-server-mcp/internal/github"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// Argumentos de las herramientas de revisión de pull requests
+type prFilesArgs struct {
+	prNumberArgs
+	pageArgs
+}
+
+type reviewCommentArgs struct {
+	Path      string `json:"path" desc:"Ruta del archivo en el PR" required:"true"`
+	Line      int    `json:"line" desc:"Línea del diff a comentar (última línea si es multilínea)" required:"true" min:"1"`
+	Side      string `json:"side" desc:"Lado del diff: RIGHT (versión nueva) o LEFT (versión anterior)" enum:"LEFT,RIGHT" default:"RIGHT"`
+	StartLine int    `json:"start_line" desc:"Primera línea de un comentario multilínea (menor que line)" min:"1"`
+	StartSide string `json:"start_side" desc:"Lado de start_line (default: side)" enum:"LEFT,RIGHT"`
+	Body      string `json:"body" desc:"Texto del comentario (Markdown)" required:"true"`
+}
+
+type createReviewArgs struct {
+	prNumberArgs
+	Event    string              `json:"event" desc:"Resultado de la revisión (sin event la revisión queda pendiente)" enum:"APPROVE,REQUEST_CHANGES,COMMENT"`
+	Body     string              `json:"body" desc:"Comentario general (obligatorio con REQUEST_CHANGES y COMMENT)"`
+	CommitID string              `json:"commit_id" desc:"SHA completo del commit revisado (default: el último del PR)" pattern:"^[0-9a-f]{40}$"`
+	Comments []reviewCommentArgs `json:"comments" desc:"Comentarios en línea anclados a archivo/línea/lado"`
+}
+
+type replyReviewCommentArgs struct {
+	prNumberArgs
+	CommentID int64  `json:"comment_id" desc:"ID del comentario de revisión al que se responde" required:"true" min:"1"`
+	Body      string `json:"body" desc:"Texto de la respuesta (Markdown)" required:"true"`
+}
+
+type requestReviewersArgs struct {
+	prNumberArgs
+	Reviewers []string `json:"reviewers" desc:"Logins de los revisores"`
+	Teams     []string `json:"teams" desc:"Slugs de los equipos revisores (repos de organización)"`
+}
+
+type mergePRArgs struct {
+	prNumberArgs
+	Method        string `json:"method" desc:"Método de fusión" enum:"merge,squash,rebase" default:"merge"`
+	CommitTitle   string `json:"commit_title" desc:"Título del commit de fusión (merge/squash)"`
+	CommitMessage string `json:"commit_message" desc:"Mensaje del commit de fusión (merge/squash)"`
+	SHA           string `json:"sha" desc:"SHA esperado del head del PR: no se fusiona si ha cambiado" pattern:"^[0-9a-f]{40}$"`
+}
+
+type updatePRBranchArgs struct {
+	prNumberArgs
+	ExpectedHeadSHA string `json:"expected_head_sha" desc:"SHA esperado del head del PR: no se actualiza si ha cambiado" pattern:"^[0-9a-f]{40}$"`
+}
+
+func registerPullRequestTools() {
+	RegisterTool(ToolDef[prNumberArgs, *githubapi.PullRequestDetail]{
+		Name:        "github_get_pr",
+		Description: "Obtiene un pull request con su mergeabilidad, resumen de checks y revisores pendientes (GitHub API)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args prNumberArgs) (*githubapi.PullRequestDetail, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.GetPullRequest(s.GithubClient, ctx, owner, repo, args.Number)
+		},
+	})
+	RegisterTool(ToolDef[prFilesArgs, *githubapi.PullRequestFileList]{
+		Name:        "github_pr_files",
+		Description: "Lista los archivos modificados por un pull request con sus parches (GitHub API)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args prFilesArgs) (*githubapi.PullRequestFileList, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.ListPullRequestFiles(s.GithubClient, ctx, owner, repo, args.Number, args.options())
+		},
+	})
+	RegisterTool(ToolDef[prNumberArgs, *githubapi.PullRequestDiff]{
+		Name:        "github_pr_diff",
+		Description: "Obtiene el diff unificado completo de un pull request (GitHub API)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args prNumberArgs) (*githubapi.PullRequestDiff, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.GetPullRequestDiff(s.GithubClient, ctx, owner, repo, args.Number)
+		},
+	})
+	RegisterTool(ToolDef[createReviewArgs, *githubapi.ReviewCreated]{
+		Name:        "github_create_review",
+		Description: "Revisa un pull request: aprueba, pide cambios o comenta, con comentarios en línea (GitHub API)",
+		Annotations: types.ToolAnnotations{OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args createReviewArgs) (*githubapi.ReviewCreated, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			review := githubapi.ReviewRequest{Event: args.Event, Body: args.Body, CommitID: args.CommitID}
+			for _, comment := range args.Comments {
+				if comment.StartLine != 0 && comment.StartLine >= comment.Line {
+					return nil, fmt.Errorf("comentario en %s: start_line (%d) debe ser menor que line (%d)", comment.Path, comment.StartLine, comment.Line)
+				}
+				review.Comments = append(review.Comments, githubapi.ReviewCommentDraft{
+					Path:      comment.Path,
+					Line:      comment.Line,
+					Side:      comment.Side,
+					StartLine: comment.StartLine,
+					StartSide: comment.StartSide,
+					Body:      comment.Body,
+				})
+			}
+			return githubapi.CreateReview(s.GithubClient, ctx, owner, repo, args.Number, review)
+		},
+	})
+	RegisterTool(ToolDef[prFilesArgs, *githubapi.ReviewCommentList]{
+		Name:        "github_list_review_comments",
+		Description: "Lista los comentarios en línea de las revisiones de un pull request (GitHub API)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args prFilesArgs) (*githubapi.ReviewCommentList, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.ListReviewComments(s.GithubClient, ctx, owner, repo, args.Number, args.options())
+		},
+	})
+	RegisterTool(ToolDef[replyReviewCommentArgs, *githubapi.ReviewComment]{
+		Name:        "github_reply_to_review_comment",
+		Description: "Responde en el hilo de un comentario de revisión (GitHub API)",
+		Annotations: types.ToolAnnotations{OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args replyReviewCommentArgs) (*githubapi.ReviewComment, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.ReplyToReviewComment(s.GithubClient, ctx, owner, repo, args.Number, args.CommentID, args.Body)
+		},
+	})
+	RegisterTool(ToolDef[requestReviewersArgs, *githubapi.ReviewersRequested]{
+		Name:        "github_request_reviewers",
+		Description: "Solicita revisión de un pull request a usuarios o equipos (GitHub API)",
+		Annotations: types.ToolAnnotations{IdempotentHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args requestReviewersArgs) (*githubapi.ReviewersRequested, error) {
+			if len(args.Reviewers) == 0 && len(args.Teams) == 0 {
+				return nil, fmt.Errorf("indica al menos un revisor en 'reviewers' o un equipo en 'teams'")
+			}
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.RequestReviewers(s.GithubClient, ctx, owner, repo, args.Number, args.Reviewers, args.Teams)
+		},
+	})
+	RegisterTool(ToolDef[mergePRArgs, *githubapi.MergeResult]{
+		Name:        "github_merge_pr",
+		Description: "Fusiona un pull request (merge, squash o rebase). Si no se puede, 'reason' indica el motivo: conflicts, checks_pending, checks_failing, review_required, head_modified... (GitHub API)",
+		Annotations: types.ToolAnnotations{DestructiveHint: true, IdempotentHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args mergePRArgs) (*githubapi.MergeResult, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.MergePullRequest(s.GithubClient, ctx, owner, repo, args.Number, githubapi.MergeOptions{
+				Method:        args.Method,
+				CommitTitle:   args.CommitTitle,
+				CommitMessage: args.CommitMessage,
+				SHA:           args.SHA,
+			})
+		},
+	})
+	RegisterTool(ToolDef[updatePRBranchArgs, *githubapi.BranchUpdateResult]{
+		Name:        "github_update_pr_branch",
+		Description: "Actualiza la rama de un pull request con los cambios de su rama base (GitHub API)",
+		Annotations: types.ToolAnnotations{OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args updatePRBranchArgs) (*githubapi.BranchUpdateResult, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.UpdatePullRequestBranch(s.GithubClient, ctx, owner, repo, args.Number, args.ExpectedHeadSHA)
+		},
+	})
+	RegisterTool(ToolDef[mergePRArgs, *githubapi.AutoMergeResult]{
+		Name:        "github_enable_auto_merge",
+		Description: "Activa la fusión automática: el PR se fusiona cuando se cumplan las reglas de protección (GitHub GraphQL API)",
+		Annotations: types.ToolAnnotations{IdempotentHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args mergePRArgs) (*githubapi.AutoMergeResult, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.EnableAutoMerge(s.GithubClient, ctx, owner, repo, args.Number, githubapi.MergeOptions{
+				Method:        args.Method,
+				CommitTitle:   args.CommitTitle,
+				CommitMessage: args.CommitMessage,
+				SHA:           args.SHA,
+			})
+		},
+	})
+	RegisterTool(ToolDef[prNumberArgs, *githubapi.PullRequestState]{
+		Name:        "github_close_pr",
+		Description: "Cierra un pull request sin fusionarlo (GitHub API)",
+		Annotations: types.ToolAnnotations{IdempotentHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args prNumberArgs) (*githubapi.PullRequestState, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.SetPullRequestState(s.GithubClient, ctx, owner, repo, args.Number, "closed")
+		},
+	})
+	RegisterTool(ToolDef[prNumberArgs, *githubapi.PullRequestState]{
+		Name:        "github_reopen_pr",
+		Description: "Reabre un pull request cerrado (GitHub API)",
+		Annotations: types.ToolAnnotations{IdempotentHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args prNumberArgs) (*githubapi.PullRequestState, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.SetPullRequestState(s.GithubClient, ctx, owner, repo, args.Number, "open")
+		},
+	})
+}
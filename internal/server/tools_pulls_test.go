@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCreateReviewValidation(t *testing.T) {
+	entry, ok := lookupTool("github_create_review")
+	if !ok {
+		t.Fatal("github_create_review not registered")
+	}
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		invalid bool // rechazado por el esquema (-32602) en vez de por el handler
+	}{
+		{name: "start_line equals line", args: map[string]interface{}{"start_line": float64(5), "line": float64(5)}},
+		{name: "start_line after line", args: map[string]interface{}{"start_line": float64(6), "line": float64(5)}},
+		{name: "short commit_id", args: map[string]interface{}{"line": float64(5), "commit_id": "abc1234"}, invalid: true},
+		{name: "uppercase commit_id", args: map[string]interface{}{"line": float64(5), "commit_id": "ABCDEF0123456789ABCDEF0123456789ABCDEF01"}, invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment := map[string]interface{}{"path": "a.go", "body": "nit"}
+			args := map[string]interface{}{"owner": "o", "repo": "r", "number": float64(1), "event": "COMMENT", "body": "review"}
+			for key, value := range tt.args {
+				if key == "commit_id" {
+					args[key] = value
+				} else {
+					comment[key] = value
+				}
+			}
+			args["comments"] = []interface{}{comment}
+
+			result, err := entry.call(context.Background(), nil, args)
+			if tt.invalid {
+				var rpcErr *rpcError
+				if !errors.As(err, &rpcErr) || rpcErr.Code != -32602 {
+					t.Errorf("err = %v, want -32602", err)
+				}
+				return
+			}
+			if err != nil || !result.IsError {
+				t.Errorf("result = %+v, err = %v, want a tool error", result, err)
+			}
+		})
+	}
+}
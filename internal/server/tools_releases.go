@@ -0,0 +1,218 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	githubapi "github.com/jotajotape/github-go-server-mcp/internal/github"
+	"github.com/jotajotape/github-go-server-mcp/internal/hybrid"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// Argumentos de las herramientas de releases
+type listReleasesArgs struct {
+	repoArgs
+	pageArgs
+}
+
+// releaseRefArgs identifica un release por ID o por tag (los borradores, mejor por ID)
+type releaseRefArgs struct {
+	repoArgs
+	ReleaseID int64  `json:"release_id" desc:"ID del release" min:"1"`
+	Tag       string `json:"tag" desc:"Tag del release"`
+}
+
+func (a releaseRefArgs) target(s *types.MCPServer) (hybrid.ReleaseTarget, error) {
+	owner, repo, err := a.resolve(s)
+	return hybrid.ReleaseTarget{Owner: owner, Repo: repo, ID: a.ReleaseID, Tag: a.Tag}, err
+}
+
+type createReleaseArgs struct {
+	repoArgs
+	Tag                  string `json:"tag" desc:"Tag del release (se crea desde target si no existe)" required:"true"`
+	Target               string `json:"target" desc:"Rama o SHA desde el que crear el tag (default: rama por defecto)"`
+	Name                 string `json:"name" desc:"Título del release (default: el tag)"`
+	Body                 string `json:"body" desc:"Notas del release (Markdown)"`
+	Draft                bool   `json:"draft" desc:"Crear como borrador"`
+	Prerelease           bool   `json:"prerelease" desc:"Marcar como prerelease"`
+	GenerateReleaseNotes bool   `json:"generate_release_notes" desc:"Generar las notas a partir de los PRs fusionados (se añaden tras body)"`
+	MakeLatest           string `json:"make_latest" desc:"Marcar como último release" enum:"true,false,legacy"`
+}
+
+func (a createReleaseArgs) changes() githubapi.ReleaseChanges {
+	changes := githubapi.ReleaseChanges{
+		Draft:                &a.Draft,
+		Prerelease:           &a.Prerelease,
+		GenerateReleaseNotes: &a.GenerateReleaseNotes,
+	}
+	if a.Target != "" {
+		changes.Target = &a.Target
+	}
+	if a.Name != "" {
+		changes.Name = &a.Name
+	}
+	if a.Body != "" {
+		changes.Body = &a.Body
+	}
+	if a.MakeLatest != "" {
+		changes.MakeLatest = &a.MakeLatest
+	}
+	return changes
+}
+
+// updateReleaseArgs usa punteros: solo se modifican los campos presentes
+type updateReleaseArgs struct {
+	releaseRefArgs
+	NewTag     *string `json:"new_tag" desc:"Nuevo tag"`
+	Name       *string `json:"name" desc:"Nuevo título"`
+	Body       *string `json:"body" desc:"Nuevas notas (Markdown)"`
+	Draft      *bool   `json:"draft" desc:"Borrador (false publica el release)"`
+	Prerelease *bool   `json:"prerelease" desc:"Prerelease"`
+	MakeLatest *string `json:"make_latest" desc:"Marcar como último release" enum:"true,false,legacy"`
+}
+
+type uploadAssetArgs struct {
+	releaseRefArgs
+	Path  string `json:"path" desc:"Archivo del workspace a subir" required:"true"`
+	Name  string `json:"name" desc:"Nombre del asset (default: nombre del archivo)"`
+	Label string `json:"label" desc:"Texto que se muestra en lugar del nombre"`
+}
+
+type downloadAssetArgs struct {
+	releaseRefArgs
+	AssetID   int64  `json:"asset_id" desc:"ID del asset" min:"1"`
+	AssetName string `json:"asset_name" desc:"Nombre del asset (en el release indicado o el último)"`
+	Path      string `json:"path" desc:"Destino en el workspace (default: el nombre del asset)"`
+	Overwrite bool   `json:"overwrite" desc:"Reemplazar el archivo si ya existe"`
+}
+
+type tagAndReleaseArgs struct {
+	repoArgs
+	Tag                  string `json:"tag" desc:"Tag a crear en el HEAD local (si no existe) y enviar al remoto" required:"true"`
+	Message              string `json:"message" desc:"Mensaje del tag anotado (default: 'Release <tag>')"`
+	Name                 string `json:"name" desc:"Título del release (default: el tag)"`
+	Body                 string `json:"body" desc:"Notas del release (Markdown)"`
+	Draft                bool   `json:"draft" desc:"Crear como borrador"`
+	Prerelease           bool   `json:"prerelease" desc:"Marcar como prerelease"`
+	GenerateReleaseNotes bool   `json:"generate_release_notes" desc:"Generar las notas a partir de los PRs fusionados" default:"true"`
+	MakeLatest           string `json:"make_latest" desc:"Marcar como último release" enum:"true,false,legacy"`
+}
+
+func registerReleaseTools() {
+	RegisterTool(ToolDef[listReleasesArgs, *githubapi.ReleaseList]{
+		Name:        "github_list_releases",
+		Description: "Lista los releases de un repositorio (GitHub API)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args listReleasesArgs) (*githubapi.ReleaseList, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.ListReleases(s.GithubClient, ctx, owner, repo, args.options())
+		},
+	})
+	RegisterTool(ToolDef[releaseRefArgs, *githubapi.ReleaseDetail]{
+		Name:        "github_get_release",
+		Description: "Obtiene un release con sus assets, por ID, por tag o el último publicado (GitHub API)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args releaseRefArgs) (*githubapi.ReleaseDetail, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.GetRelease(s.GithubClient, ctx, owner, repo, args.ReleaseID, args.Tag)
+		},
+	})
+	RegisterTool(ToolDef[createReleaseArgs, *githubapi.ReleaseDetail]{
+		Name:        "github_create_release",
+		Description: "Crea un release (borrador, prerelease, notas generadas) (GitHub API)",
+		Annotations: types.ToolAnnotations{OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args createReleaseArgs) (*githubapi.ReleaseDetail, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			fields := args.changes()
+			fields.TagName = &args.Tag
+			return githubapi.CreateRelease(s.GithubClient, ctx, owner, repo, fields)
+		},
+	})
+	RegisterTool(ToolDef[updateReleaseArgs, *githubapi.ReleaseDetail]{
+		Name:        "github_update_release",
+		Description: "Modifica un release: tag, título, notas, borrador/publicado o prerelease (GitHub API)",
+		Annotations: types.ToolAnnotations{DestructiveHint: true, IdempotentHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args updateReleaseArgs) (*githubapi.ReleaseDetail, error) {
+			if args.ReleaseID == 0 && args.Tag == "" {
+				return nil, fmt.Errorf("indica 'release_id' o 'tag'")
+			}
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.UpdateRelease(s.GithubClient, ctx, owner, repo, args.ReleaseID, args.Tag, githubapi.ReleaseChanges{
+				TagName:    args.NewTag,
+				Name:       args.Name,
+				Body:       args.Body,
+				Draft:      args.Draft,
+				Prerelease: args.Prerelease,
+				MakeLatest: args.MakeLatest,
+			})
+		},
+	})
+	RegisterTool(ToolDef[uploadAssetArgs, *githubapi.ReleaseAsset]{
+		Name:        "github_upload_release_asset",
+		Description: "📦 Sube un archivo del workspace local como asset de un release (GitHub API)",
+		Annotations: types.ToolAnnotations{OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args uploadAssetArgs) (*githubapi.ReleaseAsset, error) {
+			if args.ReleaseID == 0 && args.Tag == "" {
+				return nil, fmt.Errorf("indica 'release_id' o 'tag'")
+			}
+			target, err := args.target(s)
+			if err != nil {
+				return nil, err
+			}
+			return hybrid.UploadReleaseAsset(ctx, s.GitSnapshot(), s.GithubClient, target, args.Path, args.Name, args.Label)
+		},
+	})
+	RegisterTool(ToolDef[downloadAssetArgs, *hybrid.AssetDownload]{
+		Name:        "github_download_release_asset",
+		Description: "📥 Descarga un asset de un release en el workspace local (GitHub API)",
+		Annotations: types.ToolAnnotations{OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args downloadAssetArgs) (*hybrid.AssetDownload, error) {
+			if args.AssetID == 0 && args.AssetName == "" {
+				return nil, fmt.Errorf("indica 'asset_id' o 'asset_name'")
+			}
+			target, err := args.target(s)
+			if err != nil {
+				return nil, err
+			}
+			return hybrid.DownloadReleaseAsset(ctx, s.GitSnapshot(), s.GithubClient, target, args.AssetID, args.AssetName, args.Path, args.Overwrite)
+		},
+	})
+	RegisterTool(ToolDef[tagAndReleaseArgs, *hybrid.TagReleaseResult]{
+		Name:        "github_tag_and_release",
+		Description: "🚀 Crea el tag en el HEAD local, lo envía al remoto y crea el release en un solo paso (el remoto local debe ser owner/repo)",
+		Annotations: types.ToolAnnotations{OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args tagAndReleaseArgs) (*hybrid.TagReleaseResult, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			release := createReleaseArgs{
+				Name:                 args.Name,
+				Body:                 args.Body,
+				Draft:                args.Draft,
+				Prerelease:           args.Prerelease,
+				GenerateReleaseNotes: args.GenerateReleaseNotes,
+				MakeLatest:           args.MakeLatest,
+			}
+			return hybrid.TagAndRelease(ctx, s.GitSnapshot(), s.GithubClient, hybrid.TagReleaseRequest{
+				Owner:   owner,
+				Repo:    repo,
+				Tag:     args.Tag,
+				Message: args.Message,
+				Release: release.changes(),
+			})
+		},
+	})
+}
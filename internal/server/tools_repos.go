@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	githubapi "github.com/jotajotape/github-go-server-mcp/internal/github"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// Argumentos de las herramientas de administración de repositorios
+type updateRepoArgs struct {
+	repoArgs
+	Description         *string `json:"description" desc:"Descripción del repositorio"`
+	Homepage            *string `json:"homepage" desc:"URL de la web del proyecto"`
+	Visibility          *string `json:"visibility" desc:"Visibilidad (internal solo en organizaciones de Enterprise)" enum:"public,private,internal"`
+	DefaultBranch       *string `json:"default_branch" desc:"Rama por defecto (debe existir)"`
+	Archived            *bool   `json:"archived" desc:"Archivar (solo lectura) o desarchivar el repositorio"`
+	IsTemplate          *bool   `json:"is_template" desc:"Marcar el repositorio como plantilla"`
+	HasIssues           *bool   `json:"has_issues" desc:"Activar issues"`
+	HasWiki             *bool   `json:"has_wiki" desc:"Activar la wiki"`
+	HasProjects         *bool   `json:"has_projects" desc:"Activar projects"`
+	HasDiscussions      *bool   `json:"has_discussions" desc:"Activar discussions"`
+	AllowMergeCommit    *bool   `json:"allow_merge_commit" desc:"Permitir fusionar PRs con commit de merge"`
+	AllowSquashMerge    *bool   `json:"allow_squash_merge" desc:"Permitir fusionar PRs con squash"`
+	AllowRebaseMerge    *bool   `json:"allow_rebase_merge" desc:"Permitir fusionar PRs con rebase"`
+	AllowAutoMerge      *bool   `json:"allow_auto_merge" desc:"Permitir la fusión automática de PRs"`
+	AllowUpdateBranch   *bool   `json:"allow_update_branch" desc:"Sugerir actualizar la rama de un PR desactualizado"`
+	DeleteBranchOnMerge *bool   `json:"delete_branch_on_merge" desc:"Borrar la rama del PR al fusionarlo"`
+}
+
+type forkRepoArgs struct {
+	repoArgs
+	Org               string `json:"org" desc:"Organización en la que crear el fork (default: la cuenta del usuario)" pattern:"^[A-Za-z0-9-]+$"`
+	Name              string `json:"name" desc:"Nombre del fork (default: el del repositorio original)" pattern:"^[A-Za-z0-9._-]+$"`
+	DefaultBranchOnly bool   `json:"default_branch_only" desc:"Copiar solo la rama por defecto"`
+}
+
+// deleteRepoArgs no usa los valores por defecto de repoArgs: el repositorio a borrar se
+// indica siempre de forma explícita
+type deleteRepoArgs struct {
+	Owner   string `json:"owner" desc:"Propietario del repositorio" required:"true" pattern:"^[A-Za-z0-9-]+$"`
+	Repo    string `json:"repo" desc:"Nombre del repositorio" required:"true" pattern:"^[A-Za-z0-9._-]+$"`
+	Confirm string `json:"confirm" desc:"Nombre completo 'owner/repo' del repositorio a borrar, como confirmación" required:"true"`
+}
+
+type setTopicsArgs struct {
+	repoArgs
+	Topics []string `json:"topics" desc:"Topics del repositorio (sustituyen a los actuales; vacío los quita todos)" required:"true"`
+}
+
+type transferRepoArgs struct {
+	repoArgs
+	NewOwner string  `json:"new_owner" desc:"Usuario u organización que recibe el repositorio" required:"true" pattern:"^[A-Za-z0-9-]+$"`
+	NewName  string  `json:"new_name" desc:"Nuevo nombre del repositorio (default: el actual)" pattern:"^[A-Za-z0-9._-]+$"`
+	TeamIDs  []int64 `json:"team_ids" desc:"IDs de los equipos de la organización destino con acceso al repositorio"`
+}
+
+func registerRepoTools() {
+	RegisterTool(ToolDef[repoArgs, *githubapi.RepositoryDetail]{
+		Name:        "github_get_repo",
+		Description: "Metadatos de un repositorio: visibilidad, rama por defecto, topics, ajustes de fusión y permisos del usuario (GitHub API)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args repoArgs) (*githubapi.RepositoryDetail, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.GetRepository(s.GithubClient, ctx, owner, repo)
+		},
+	})
+	RegisterTool(ToolDef[updateRepoArgs, *githubapi.RepositoryDetail]{
+		Name:        "github_update_repo",
+		Description: "Modifica la configuración de un repositorio (solo los campos indicados): descripción, visibilidad, rama por defecto, ajustes de fusión, archivado (GitHub API)",
+		Annotations: types.ToolAnnotations{IdempotentHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args updateRepoArgs) (*githubapi.RepositoryDetail, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.UpdateRepository(s.GithubClient, ctx, owner, repo, githubapi.RepositoryChanges{
+				Description:         args.Description,
+				Homepage:            args.Homepage,
+				Visibility:          args.Visibility,
+				DefaultBranch:       args.DefaultBranch,
+				Archived:            args.Archived,
+				IsTemplate:          args.IsTemplate,
+				HasIssues:           args.HasIssues,
+				HasWiki:             args.HasWiki,
+				HasProjects:         args.HasProjects,
+				HasDiscussions:      args.HasDiscussions,
+				AllowMergeCommit:    args.AllowMergeCommit,
+				AllowSquashMerge:    args.AllowSquashMerge,
+				AllowRebaseMerge:    args.AllowRebaseMerge,
+				AllowAutoMerge:      args.AllowAutoMerge,
+				AllowUpdateBranch:   args.AllowUpdateBranch,
+				DeleteBranchOnMerge: args.DeleteBranchOnMerge,
+			})
+		},
+	})
+	RegisterTool(ToolDef[forkRepoArgs, *githubapi.RepositoryCreated]{
+		Name:        "github_fork_repo",
+		Description: "Crea un fork de un repositorio en la cuenta del usuario o en una organización (GitHub API)",
+		Annotations: types.ToolAnnotations{OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args forkRepoArgs) (*githubapi.RepositoryCreated, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.ForkRepository(s.GithubClient, ctx, owner, repo, args.Org, args.Name, args.DefaultBranchOnly)
+		},
+	})
+	RegisterTool(ToolDef[deleteRepoArgs, *githubapi.RepositoryDeleted]{
+		Name:        "github_delete_repo",
+		Description: "Borra un repositorio de forma irreversible; owner y repo son obligatorios y 'confirm' debe repetir su nombre completo 'owner/repo' (GitHub API)",
+		Annotations: types.ToolAnnotations{DestructiveHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args deleteRepoArgs) (*githubapi.RepositoryDeleted, error) {
+			// El error no repite el valor esperado: confirm debe salir de quien pide el borrado
+			if !strings.EqualFold(args.Confirm, args.Owner+"/"+args.Repo) {
+				return nil, fmt.Errorf("confirmación incorrecta: confirm no coincide con owner/repo; no se ha borrado nada")
+			}
+			return githubapi.DeleteRepository(s.GithubClient, ctx, args.Owner, args.Repo)
+		},
+	})
+	RegisterTool(ToolDef[setTopicsArgs, *githubapi.RepositoryTopics]{
+		Name:        "github_set_topics",
+		Description: "Sustituye los topics de un repositorio (GitHub API)",
+		Annotations: types.ToolAnnotations{IdempotentHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args setTopicsArgs) (*githubapi.RepositoryTopics, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.SetTopics(s.GithubClient, ctx, owner, repo, args.Topics)
+		},
+	})
+	RegisterTool(ToolDef[transferRepoArgs, *githubapi.RepositoryTransfer]{
+		Name:        "github_transfer_repo",
+		Description: "Transfiere un repositorio a otro usuario u organización, opcionalmente con otro nombre (GitHub API)",
+		Annotations: types.ToolAnnotations{DestructiveHint: true, OpenWorldHint: true},
+		Handler: func(ctx context.Context, s *types.MCPServer, args transferRepoArgs) (*githubapi.RepositoryTransfer, error) {
+			owner, repo, err := args.resolve(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.TransferRepository(s.GithubClient, ctx, owner, repo, args.NewOwner, args.NewName, args.TeamIDs)
+		},
+	})
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDeleteRepoConfirmation(t *testing.T) {
+	entry, ok := lookupTool("github_delete_repo")
+	if !ok {
+		t.Fatal("github_delete_repo not registered")
+	}
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		invalid bool // rechazado por el esquema (-32602) en vez de por el handler
+	}{
+		{name: "without owner and repo", args: map[string]interface{}{"confirm": "acme/site"}, invalid: true},
+		{name: "without repo", args: map[string]interface{}{"owner": "acme", "confirm": "acme/site"}, invalid: true},
+		{name: "without confirm", args: map[string]interface{}{"owner": "acme", "repo": "site"}, invalid: true},
+		{name: "wrong confirm", args: map[string]interface{}{"owner": "acme", "repo": "site", "confirm": "acme/other"}},
+		{name: "partial confirm", args: map[string]interface{}{"owner": "acme", "repo": "site", "confirm": "site"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := entry.call(context.Background(), nil, tt.args)
+			if tt.invalid {
+				var rpcErr *rpcError
+				if !errors.As(err, &rpcErr) || rpcErr.Code != -32602 {
+					t.Errorf("err = %v, want -32602", err)
+				}
+				return
+			}
+			if err != nil || !result.IsError {
+				t.Fatalf("result = %+v, err = %v, want a tool error", result, err)
+			}
+			// El mensaje no revela el valor que se espera en confirm
+			if text := result.Content[0].Text; strings.Contains(text, "acme/site") {
+				t.Errorf("error echoes the expected confirm value: %s", text)
+			}
+		})
+	}
+}
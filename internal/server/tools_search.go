@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	githubapi "github.com/jotajotape/github-go-server-mcp/internal/github"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// Argumentos de las herramientas de búsqueda
+type searchQueryArgs struct {
+	Query string `json:"query" desc:"Consulta con la sintaxis de búsqueda de GitHub (p. ej. 'func main language:go')" required:"true"`
+	Order string `json:"order" desc:"Orden" enum:"asc,desc" default:"desc"`
+	pageArgs
+}
+
+func (a searchQueryArgs) options(sort string) githubapi.SearchOptions {
+	return githubapi.SearchOptions{Sort: sort, Order: a.Order, PageOptions: a.pageArgs.options()}
+}
+
+// searchScopeArgs permite limitar la búsqueda al repositorio del workspace o del perfil
+type searchScopeArgs struct {
+	searchQueryArgs
+	InRepo bool   `json:"in_repo" desc:"Limitar la búsqueda al repositorio (owner/repo o los del workspace/perfil)"`
+	Owner  string `json:"owner" desc:"Propietario, con in_repo"`
+	Repo   string `json:"repo" desc:"Repositorio, con in_repo"`
+}
+
+// query añade el calificador repo: si la búsqueda se limita al repositorio
+func (a searchScopeArgs) query(s *types.MCPServer) (string, error) {
+	if !a.InRepo {
+		return a.Query, nil
+	}
+	owner, repo, err := resolveRepo(s, a.Owner, a.Repo)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(a.Query) + " " + searchQualifier("repo", owner+"/"+repo), nil
+}
+
+// searchQualifier forma el calificador name:value. Los valores con espacios, paréntesis o
+// ':' van entre comillas para que no se lean como otros términos u operadores; las comillas
+// del valor se quitan porque la sintaxis de búsqueda no permite escaparlas.
+func searchQualifier(name, value string) string {
+	value = strings.ReplaceAll(value, `"`, "")
+	if strings.ContainsAny(value, " \t\r\n():") {
+		return name + `:"` + value + `"`
+	}
+	return name + ":" + value
+}
+
+type searchIssuesArgs struct {
+	searchScopeArgs
+	Sort string `json:"sort" desc:"Ordenar por (default: relevancia)" enum:"comments,reactions,interactions,created,updated"`
+}
+
+type searchCommitsArgs struct {
+	searchScopeArgs
+	Sort string `json:"sort" desc:"Ordenar por (default: relevancia)" enum:"author-date,committer-date"`
+}
+
+type searchReposArgs struct {
+	searchQueryArgs
+	Sort string `json:"sort" desc:"Ordenar por (default: relevancia)" enum:"stars,forks,help-wanted-issues,updated"`
+}
+
+type searchUsersArgs struct {
+	searchQueryArgs
+	Sort string `json:"sort" desc:"Ordenar por (default: relevancia)" enum:"followers,repositories,joined"`
+}
+
+func registerSearchTools() {
+	RegisterTool(ToolDef[searchScopeArgs, *githubapi.CodeSearchResult]{
+		Name:        "github_search_code",
+		Description: "🔎 Busca código en GitHub con fragmentos coincidentes (cupo propio: 10 búsquedas/minuto)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args searchScopeArgs) (*githubapi.CodeSearchResult, error) {
+			query, err := args.query(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.SearchCode(s.GithubClient, ctx, query, args.options(""))
+		},
+	})
+	RegisterTool(ToolDef[searchIssuesArgs, *githubapi.IssueSearchResult]{
+		Name:        "github_search_issues",
+		Description: "Busca issues y pull requests con la sintaxis completa (is:pr, is:open, label:, author:, review:...)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args searchIssuesArgs) (*githubapi.IssueSearchResult, error) {
+			query, err := args.query(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.SearchIssues(s.GithubClient, ctx, query, args.options(args.Sort))
+		},
+	})
+	RegisterTool(ToolDef[searchCommitsArgs, *githubapi.CommitSearchResult]{
+		Name:        "github_search_commits",
+		Description: "Busca commits por mensaje, autor o fecha (author:, committer-date:, merge:...)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args searchCommitsArgs) (*githubapi.CommitSearchResult, error) {
+			query, err := args.query(s)
+			if err != nil {
+				return nil, err
+			}
+			return githubapi.SearchCommits(s.GithubClient, ctx, query, args.options(args.Sort))
+		},
+	})
+	RegisterTool(ToolDef[searchReposArgs, *githubapi.RepoSearchResult]{
+		Name:        "github_search_repos",
+		Description: "Busca repositorios (language:, stars:, topic:, org:...)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args searchReposArgs) (*githubapi.RepoSearchResult, error) {
+			return githubapi.SearchRepositories(s.GithubClient, ctx, args.Query, args.options(args.Sort))
+		},
+	})
+	RegisterTool(ToolDef[searchUsersArgs, *githubapi.UserSearchResult]{
+		Name:        "github_search_users",
+		Description: "Busca usuarios y organizaciones (type:org, location:, followers:...)",
+		Annotations: readOnlyRemote,
+		Handler: func(ctx context.Context, s *types.MCPServer, args searchUsersArgs) (*githubapi.UserSearchResult, error) {
+			return githubapi.SearchUsers(s.GithubClient, ctx, args.Query, args.options(args.Sort))
+		},
+	})
+}
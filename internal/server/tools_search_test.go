@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	githubapi "github.com/jotajotape/github-go-server-mcp/internal/github"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+func TestSearchScopeQuery(t *testing.T) {
+	s := &types.MCPServer{Defaults: types.RepoDefaults{Owner: "me", Repo: "site"}}
+
+	tests := []struct {
+		name    string
+		args    searchScopeArgs
+		want    string
+		wantErr bool
+	}{
+		{name: "whole GitHub", args: searchScopeArgs{searchQueryArgs: searchQueryArgs{Query: "func main language:go"}}, want: "func main language:go"},
+		{name: "default repository", args: searchScopeArgs{searchQueryArgs: searchQueryArgs{Query: "is:pr is:open"}, InRepo: true}, want: "is:pr is:open repo:me/site"},
+		{name: "explicit repository", args: searchScopeArgs{searchQueryArgs: searchQueryArgs{Query: " bug "}, InRepo: true, Owner: "acme", Repo: "api"}, want: "bug repo:acme/api"},
+		{name: "owner with space", args: searchScopeArgs{searchQueryArgs: searchQueryArgs{Query: "bug"}, InRepo: true, Owner: "acme OR", Repo: "api"}, want: `bug repo:"acme OR/api"`},
+		{name: "quotes removed", args: searchScopeArgs{searchQueryArgs: searchQueryArgs{Query: "bug"}, InRepo: true, Owner: `acme" is:closed "`, Repo: "api"}, want: `bug repo:"acme is:closed /api"`},
+		{name: "parenthesis", args: searchScopeArgs{searchQueryArgs: searchQueryArgs{Query: "bug"}, InRepo: true, Owner: "acme)", Repo: "api"}, want: `bug repo:"acme)/api"`},
+		{name: "no repository", args: searchScopeArgs{searchQueryArgs: searchQueryArgs{Query: "bug"}, InRepo: true, Owner: "acme"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := s
+			if tt.wantErr {
+				server = &types.MCPServer{}
+			}
+			got, err := tt.args.query(server)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("query = %q, want error", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("query = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+// searchServer responde a las búsquedas con una página vacía y guarda la última petición
+type searchServer struct {
+	mu     sync.Mutex
+	path   string
+	query  url.Values
+	accept string
+}
+
+func (s *searchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.path, s.query, s.accept = r.URL.Path, r.URL.Query(), r.Header.Get("Accept")
+	s.mu.Unlock()
+	w.Write([]byte(`{"total_count":0,"incomplete_results":false,"items":[]}`))
+}
+
+// Cada herramienta de búsqueda llega a su endpoint con la consulta, el orden y la página
+func TestSearchTools(t *testing.T) {
+	handler := &searchServer{}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := githubapi.NewClient(githubapi.StaticToken("t"), githubapi.ClientOptions{BaseURL: server.URL + "/api/v3/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &types.MCPServer{GithubClient: client, Defaults: types.RepoDefaults{Owner: "me", Repo: "site"}}
+
+	tests := []struct {
+		tool      string
+		args      map[string]interface{}
+		wantPath  string
+		wantQuery map[string]string
+	}{
+		{
+			tool:      "github_search_code",
+			args:      map[string]interface{}{"query": "TODO language:go", "in_repo": true},
+			wantPath:  "/api/v3/search/code",
+			wantQuery: map[string]string{"q": "TODO language:go repo:me/site", "sort": "", "order": "desc", "page": "1", "per_page": "30"},
+		},
+		{
+			tool:      "github_search_issues",
+			args:      map[string]interface{}{"query": "is:pr review:required", "in_repo": true, "owner": "acme", "repo": "api", "sort": "updated", "order": "asc"},
+			wantPath:  "/api/v3/search/issues",
+			wantQuery: map[string]string{"q": "is:pr review:required repo:acme/api", "sort": "updated", "order": "asc"},
+		},
+		{
+			tool:      "github_search_commits",
+			args:      map[string]interface{}{"query": "fix author:ana", "sort": "author-date", "page": float64(3), "per_page": float64(10)},
+			wantPath:  "/api/v3/search/commits",
+			wantQuery: map[string]string{"q": "fix author:ana", "sort": "author-date", "page": "3", "per_page": "10"},
+		},
+		{
+			tool:      "github_search_repos",
+			args:      map[string]interface{}{"query": "topic:mcp stars:>10", "sort": "stars"},
+			wantPath:  "/api/v3/search/repositories",
+			wantQuery: map[string]string{"q": "topic:mcp stars:>10", "sort": "stars"},
+		},
+		{
+			tool:      "github_search_users",
+			args:      map[string]interface{}{"query": "type:org location:Madrid", "sort": "followers"},
+			wantPath:  "/api/v3/search/users",
+			wantQuery: map[string]string{"q": "type:org location:Madrid", "sort": "followers"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			entry, ok := lookupTool(tt.tool)
+			if !ok {
+				t.Fatalf("%s not registered", tt.tool)
+			}
+			result, err := entry.call(context.Background(), s, tt.args)
+			if err != nil || result.IsError {
+				t.Fatalf("result = %+v, err = %v", result, err)
+			}
+
+			handler.mu.Lock()
+			defer handler.mu.Unlock()
+			if handler.path != tt.wantPath {
+				t.Errorf("path = %s, want %s", handler.path, tt.wantPath)
+			}
+			for name, want := range tt.wantQuery {
+				if got := handler.query.Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+			// Los fragmentos coincidentes se piden siempre
+			if !strings.Contains(handler.accept, "text-match") {
+				t.Errorf("Accept = %q, want text-match", handler.accept)
+			}
+		})
+	}
+}
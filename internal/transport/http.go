@@ -0,0 +1,497 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/server"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// HTTPEndpoint es la ruta única del transporte Streamable HTTP de MCP
+const HTTPEndpoint = "/mcp"
+
+const (
+	sessionHeader       = "Mcp-Session-Id"
+	sessionIdleTimeout  = time.Hour
+	sseKeepAlive        = 25 * time.Second
+	sessionEventsBuffer = 64
+)
+
+// session es una conexión lógica de un cliente MCP.
+// Cada sesión tiene su propio servidor (y por tanto su propio workspace Git).
+type session struct {
+	id       string
+	server   *types.MCPServer
+	ctx      context.Context
+	cancel   context.CancelFunc
+	events   chan []byte
+	lastSeen atomic.Int64
+}
+
+func (sess *session) touch() {
+	sess.lastSeen.Store(time.Now().UnixNano())
+}
+
+// HTTPTokenEnv es la variable de entorno con el bearer token del transporte HTTP
+const HTTPTokenEnv = "MCP_HTTP_TOKEN"
+
+// HTTPOptions configura el transporte HTTP
+type HTTPOptions struct {
+	Addr           string   // dirección de escucha; el Host de cada petición debe corresponder a ella
+	Token          string   // bearer token exigido en cada petición; sin él solo se escucha en loopback
+	AllowedOrigins []string // orígenes admitidos (p. ej. https://app.example.com); vacío = localhost, 127.0.0.1 y ::1
+	Workers        int      // peticiones procesadas en paralelo entre todas las sesiones
+}
+
+// HTTPHandler implementa el transporte Streamable HTTP:
+// POST para enviar mensajes, GET para abrir un stream SSE de mensajes del servidor
+// y DELETE para cerrar la sesión.
+type HTTPHandler struct {
+	base    *types.MCPServer
+	sem     chan struct{}
+	token   []byte
+	origins map[string]bool // orígenes admitidos; nil = solo loopback
+	hosts   map[string]bool // valores admitidos de Host; nil = cualquiera (escucha en todas las interfaces)
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+// NewHTTPHandler crea el handler HTTP. Las sesiones se crean a partir de base.
+// Sin token se rechaza una dirección que no sea loopback: cualquiera en la red podría usar las credenciales de GitHub.
+func NewHTTPHandler(base *types.MCPServer, opts HTTPOptions) (*HTTPHandler, error) {
+	if err := checkListenAddr(opts); err != nil {
+		return nil, err
+	}
+	host, port, _ := net.SplitHostPort(opts.Addr)
+
+	workers := opts.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	h := &HTTPHandler{
+		base:     base,
+		sem:      make(chan struct{}, workers),
+		token:    []byte(opts.Token),
+		sessions: make(map[string]*session),
+	}
+
+	if len(opts.AllowedOrigins) > 0 {
+		h.origins = make(map[string]bool)
+		for _, origin := range opts.AllowedOrigins {
+			h.origins[normalizeOrigin(origin)] = true
+		}
+	}
+
+	// En una interfaz concreta el Host debe nombrarla; en loopback se admiten sus tres nombres
+	if ip := net.ParseIP(host); host != "" && (ip == nil || !ip.IsUnspecified()) {
+		h.hosts = map[string]bool{strings.ToLower(net.JoinHostPort(host, port)): true}
+		if isLoopback(host) {
+			for _, name := range []string{"localhost", "127.0.0.1", "::1"} {
+				h.hosts[net.JoinHostPort(name, port)] = true
+			}
+		}
+	}
+	return h, nil
+}
+
+// checkListenAddr valida la dirección de escucha y exige token fuera de loopback
+func checkListenAddr(opts HTTPOptions) error {
+	host, _, err := net.SplitHostPort(opts.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid listen address %q: %w", opts.Addr, err)
+	}
+	if opts.Token == "" && !isLoopback(host) {
+		return fmt.Errorf("refusing to listen on %s without a token: set %s or use a loopback address", opts.Addr, HTTPTokenEnv)
+	}
+	return nil
+}
+
+// ListenAndServeHTTP sirve el transporte Streamable HTTP en opts.Addr
+func ListenAndServeHTTP(base *types.MCPServer, opts HTTPOptions) error {
+	if err := checkListenAddr(opts); err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", opts.Addr)
+	if err != nil {
+		return err
+	}
+
+	// Con puerto 0 el Host admitido lleva el puerto asignado
+	host, _, _ := net.SplitHostPort(opts.Addr)
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	opts.Addr = net.JoinHostPort(host, port)
+	handler, err := NewHTTPHandler(base, opts)
+	if err != nil {
+		listener.Close()
+		return err
+	}
+	go handler.reapIdleSessions()
+
+	mux := http.NewServeMux()
+	mux.Handle(HTTPEndpoint, handler)
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return srv.Serve(listener)
+}
+
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Protección contra DNS rebinding: el atacante controla Origin y el nombre del Host,
+	// pero no que ese nombre coincida con la dirección de escucha
+	if h.hosts != nil && !h.hosts[strings.ToLower(r.Host)] {
+		http.Error(w, "Forbidden host", http.StatusForbidden)
+		return
+	}
+	if !h.allowedOrigin(r.Header.Get("Origin")) {
+		http.Error(w, "Forbidden origin", http.StatusForbidden)
+		return
+	}
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="mcp"`)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		h.handlePost(w, r)
+	case http.MethodGet:
+		h.handleGet(w, r)
+	case http.MethodDelete:
+		h.handleDelete(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handlePost procesa un mensaje JSON-RPC o un batch y responde con JSON
+func (h *HTTPHandler) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMessageSize+1))
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, -32700, "Parse error: "+err.Error())
+		return
+	}
+	if len(body) > maxMessageSize {
+		writeHTTPError(w, http.StatusRequestEntityTooLarge, -32600, "Invalid Request: message too large")
+		return
+	}
+
+	messages, batch, err := decodeMessages(body)
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, -32700, "Parse error: "+err.Error())
+		return
+	}
+
+	sess, status, msg := h.resolveSession(r, messages)
+	if sess == nil {
+		writeHTTPError(w, status, -32600, msg)
+		return
+	}
+	sess.touch()
+
+	// El contexto de cada petición termina si el cliente corta la conexión o cierra la sesión
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	stop := context.AfterFunc(sess.ctx, cancel)
+	defer stop()
+
+	responses := make([]*types.JSONRPCResponse, len(messages))
+	var wg sync.WaitGroup
+	pending := 0
+	for i, req := range messages {
+		// Respuestas del cliente a peticiones del servidor: no las usamos
+		if req.Method == "" && req.ID != nil {
+			continue
+		}
+
+		if server.IsNotification(req) {
+			server.HandleRequest(ctx, sess.server, req)
+			continue
+		}
+
+		pending++
+		wg.Add(1)
+		go func(i int, req types.JSONRPCRequest) {
+			defer wg.Done()
+			h.sem <- struct{}{}
+			defer func() { <-h.sem }()
+			responses[i] = server.HandleRequest(ctx, sess.server, req)
+		}(i, req)
+	}
+	wg.Wait()
+
+	w.Header().Set(sessionHeader, sess.id)
+	if pending == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	var out interface{}
+	if batch {
+		list := make([]*types.JSONRPCResponse, 0, pending)
+		for _, resp := range responses {
+			if resp != nil {
+				list = append(list, resp)
+			}
+		}
+		out = list
+	} else {
+		out = responses[0]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
+
+// handleGet abre un stream SSE por el que el servidor envía notificaciones a la sesión
+func (h *HTTPHandler) handleGet(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		w.Header().Set("Allow", "POST, DELETE")
+		http.Error(w, "GET requires Accept: text/event-stream", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sess, status, msg := h.lookupSession(r)
+	if sess == nil {
+		http.Error(w, msg, status)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set(sessionHeader, sess.id)
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case data := <-sess.events:
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+			flusher.Flush()
+			sess.touch()
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+			sess.touch()
+		case <-r.Context().Done():
+			return
+		case <-sess.ctx.Done():
+			return
+		}
+	}
+}
+
+// handleDelete cierra la sesión y cancela sus peticiones en curso
+func (h *HTTPHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	sess, status, msg := h.lookupSession(r)
+	if sess == nil {
+		http.Error(w, msg, status)
+		return
+	}
+
+	h.closeSession(sess.id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// resolveSession crea una sesión nueva para initialize o localiza la existente
+func (h *HTTPHandler) resolveSession(r *http.Request, messages []types.JSONRPCRequest) (*session, int, string) {
+	for _, req := range messages {
+		if req.Method != "initialize" {
+			continue
+		}
+		if len(messages) > 1 {
+			return nil, http.StatusBadRequest, "Invalid Request: initialize must not be part of a batch"
+		}
+		return h.newSession(), 0, ""
+	}
+	return h.lookupSession(r)
+}
+
+func (h *HTTPHandler) lookupSession(r *http.Request) (*session, int, string) {
+	id := r.Header.Get(sessionHeader)
+	if id == "" {
+		return nil, http.StatusBadRequest, "Bad Request: missing " + sessionHeader + " header"
+	}
+
+	h.mu.Lock()
+	sess, ok := h.sessions[id]
+	h.mu.Unlock()
+	if !ok {
+		return nil, http.StatusNotFound, "Session not found"
+	}
+	return sess, 0, ""
+}
+
+func (h *HTTPHandler) newSession() *session {
+	ctx, cancel := context.WithCancel(context.Background())
+	sess := &session{
+		id:     newSessionID(),
+		server: h.base.Clone(),
+		ctx:    ctx,
+		cancel: cancel,
+		events: make(chan []byte, sessionEventsBuffer),
+	}
+	sess.touch()
+
+	// Cada mensaje del servidor se entrega por un único stream SSE
+	sess.server.Notifier = func(n types.JSONRPCNotification) {
+		data, err := json.Marshal(n)
+		if err != nil {
+			return
+		}
+		select {
+		case sess.events <- data:
+		default:
+			log.Printf("⚠️ Sesión %s: notificación %s descartada (stream lleno o sin cliente)", sess.id, n.Method)
+		}
+	}
+
+	h.mu.Lock()
+	h.sessions[sess.id] = sess
+	h.mu.Unlock()
+
+	log.Printf("🔌 Nueva sesión MCP: %s", sess.id)
+	return sess
+}
+
+func (h *HTTPHandler) closeSession(id string) {
+	h.mu.Lock()
+	sess, ok := h.sessions[id]
+	delete(h.sessions, id)
+	h.mu.Unlock()
+
+	if ok {
+		sess.cancel()
+		sess.server.Close()
+		log.Printf("🔌 Sesión MCP cerrada: %s", id)
+	}
+}
+
+// reapIdleSessions cierra periódicamente las sesiones sin actividad
+func (h *HTTPHandler) reapIdleSessions() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		cutoff := time.Now().Add(-sessionIdleTimeout).UnixNano()
+
+		var idle []string
+		h.mu.Lock()
+		for id, sess := range h.sessions {
+			if sess.lastSeen.Load() < cutoff {
+				idle = append(idle, id)
+			}
+		}
+		h.mu.Unlock()
+
+		for _, id := range idle {
+			h.closeSession(id)
+		}
+	}
+}
+
+// decodeMessages interpreta el cuerpo como un mensaje JSON-RPC o un batch
+func decodeMessages(body []byte) ([]types.JSONRPCRequest, bool, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var messages []types.JSONRPCRequest
+		if err := json.Unmarshal(body, &messages); err != nil {
+			return nil, true, err
+		}
+		if len(messages) == 0 {
+			return nil, true, fmt.Errorf("empty batch")
+		}
+		return messages, true, nil
+	}
+
+	var req types.JSONRPCRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, false, err
+	}
+	return []types.JSONRPCRequest{req}, false, nil
+}
+
+// allowedOrigin acepta peticiones sin Origin (clientes que no son navegadores) o desde un origen admitido
+func (h *HTTPHandler) allowedOrigin(origin string) bool {
+	if origin == "" {
+		return true
+	}
+	if h.origins != nil {
+		return h.origins[normalizeOrigin(origin)]
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	return isLoopback(u.Hostname())
+}
+
+// authorized comprueba el bearer token en tiempo constante
+func (h *HTTPHandler) authorized(r *http.Request) bool {
+	if len(h.token) == 0 {
+		return true
+	}
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), h.token) == 1
+}
+
+// normalizeOrigin deja un origen en la forma en que lo envía el navegador: scheme://host[:puerto] en minúsculas
+func normalizeOrigin(origin string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+}
+
+func isLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func newSessionID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func writeHTTPError(w http.ResponseWriter, status, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(types.JSONRPCResponse{
+		JSONRPC: "2.0",
+		Error:   &types.JSONRPCError{Code: code, Message: message},
+	})
+}
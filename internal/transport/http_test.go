@@ -0,0 +1,333 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+const testToken = "s3cret"
+
+// newTestHTTP arranca el handler en un servidor de prueba escuchando en loopback
+func newTestHTTP(t *testing.T, opts HTTPOptions) (*HTTPHandler, string) {
+	t.Helper()
+	ts := httptest.NewUnstartedServer(nil)
+	opts.Addr = ts.Listener.Addr().String()
+	handler, err := NewHTTPHandler(&types.MCPServer{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts.Config.Handler = handler
+	ts.Start()
+	t.Cleanup(ts.Close)
+	return handler, ts.URL + HTTPEndpoint
+}
+
+// post envía un mensaje JSON-RPC con el token y, si se indica, la sesión
+func post(t *testing.T, url, sessionID, body string, header map[string]string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	if sessionID != "" {
+		req.Header.Set(sessionHeader, sessionID)
+	}
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// initSession abre una sesión con initialize y devuelve su ID
+func initSession(t *testing.T, url string) string {
+	t.Helper()
+	resp := post(t, url, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("initialize: status %d", resp.StatusCode)
+	}
+	id := resp.Header.Get(sessionHeader)
+	if id == "" {
+		t.Fatal("initialize response without " + sessionHeader)
+	}
+	return id
+}
+
+func TestHTTPSession(t *testing.T) {
+	handler, url := newTestHTTP(t, HTTPOptions{Token: testToken})
+	id := initSession(t, url)
+
+	resp := post(t, url, id, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"test_sleep","arguments":{"millis":1}}}`, nil)
+	<-sleepStarted
+	if resp.StatusCode != http.StatusOK || resp.Header.Get(sessionHeader) != id {
+		t.Fatalf("call: status %d, session %q", resp.StatusCode, resp.Header.Get(sessionHeader))
+	}
+	var out types.JSONRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.ID != float64(2) || out.Error != nil || isToolError(out) {
+		t.Errorf("call response = %+v", out)
+	}
+
+	// Una notificación no tiene respuesta
+	if resp := post(t, url, id, `{"jsonrpc":"2.0","method":"notifications/initialized"}`, nil); resp.StatusCode != http.StatusAccepted {
+		t.Errorf("notification: status %d, want 202", resp.StatusCode)
+	}
+
+	// Cada initialize crea una sesión distinta
+	if other := initSession(t, url); other == id {
+		t.Error("two initialize calls share a session")
+	}
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	if len(handler.sessions) != 2 {
+		t.Errorf("sessions = %d, want 2", len(handler.sessions))
+	}
+}
+
+func TestHTTPUnknownSession(t *testing.T) {
+	_, url := newTestHTTP(t, HTTPOptions{Token: testToken})
+	call := `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`
+
+	if resp := post(t, url, "missing", call, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown session: status %d, want 404", resp.StatusCode)
+	}
+	if resp := post(t, url, "", call, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("no session: status %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestHTTPRejectedRequests(t *testing.T) {
+	handler, url := newTestHTTP(t, HTTPOptions{Token: testToken})
+	port := url[strings.LastIndex(url, ":")+1 : strings.Index(url, HTTPEndpoint)]
+	initialize := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   int
+	}{
+		{name: "no origin", want: http.StatusOK},
+		{name: "localhost origin", header: map[string]string{"Origin": "http://localhost:3000"}, want: http.StatusOK},
+		{name: "ipv6 loopback origin", header: map[string]string{"Origin": "http://[::1]"}, want: http.StatusOK},
+		{name: "localhost host", header: map[string]string{"Host": "localhost:" + port}, want: http.StatusOK},
+		{name: "foreign origin", header: map[string]string{"Origin": "https://evil.example"}, want: http.StatusForbidden},
+		{name: "origin matching a rebound host", header: map[string]string{"Origin": "http://evil.example:" + port, "Host": "evil.example:" + port}, want: http.StatusForbidden},
+		{name: "rebound host", header: map[string]string{"Host": "evil.example:" + port}, want: http.StatusForbidden},
+		{name: "other port", header: map[string]string{"Host": "127.0.0.1:1"}, want: http.StatusForbidden},
+		{name: "no token", header: map[string]string{"Authorization": ""}, want: http.StatusUnauthorized},
+		{name: "wrong token", header: map[string]string{"Authorization": "Bearer s3cre"}, want: http.StatusUnauthorized},
+		{name: "basic auth", header: map[string]string{"Authorization": "Basic " + testToken}, want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(initialize))
+			req.Header.Set("Authorization", "Bearer "+testToken)
+			for name, value := range tt.header {
+				if name == "Host" {
+					req.Host = value
+				} else {
+					req.Header.Set(name, value)
+				}
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+
+	// GET y DELETE también exigen el token
+	id := initSession(t, url)
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		req, _ := http.NewRequest(method, url, nil)
+		req.Header.Set(sessionHeader, id)
+		req.Header.Set("Accept", "text/event-stream")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s without token: status %d, want 401", method, resp.StatusCode)
+		}
+	}
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	if handler.sessions[id] == nil {
+		t.Error("unauthorized DELETE closed the session")
+	}
+}
+
+func TestHTTPAllowedOrigins(t *testing.T) {
+	_, url := newTestHTTP(t, HTTPOptions{AllowedOrigins: []string{"https://App.example.com/"}})
+	initialize := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`
+
+	for origin, want := range map[string]int{
+		"https://app.example.com":      http.StatusOK,
+		"http://app.example.com":       http.StatusForbidden,
+		"https://app.example.com:8443": http.StatusForbidden,
+		"http://localhost":             http.StatusForbidden,
+	} {
+		if resp := post(t, url, "", initialize, map[string]string{"Origin": origin}); resp.StatusCode != want {
+			t.Errorf("origin %s: status %d, want %d", origin, resp.StatusCode, want)
+		}
+	}
+}
+
+func TestNewHTTPHandlerBind(t *testing.T) {
+	tests := []struct {
+		addr    string
+		token   string
+		wantErr bool
+	}{
+		{addr: "127.0.0.1:8080"},
+		{addr: "localhost:8080"},
+		{addr: "[::1]:8080"},
+		{addr: "0.0.0.0:8080", wantErr: true},
+		{addr: ":8080", wantErr: true},
+		{addr: "192.168.1.10:8080", wantErr: true},
+		{addr: "0.0.0.0:8080", token: testToken},
+		{addr: "8080", token: testToken, wantErr: true},
+	}
+	for _, tt := range tests {
+		_, err := NewHTTPHandler(&types.MCPServer{}, HTTPOptions{Addr: tt.addr, Token: tt.token})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("addr %s token %q: err = %v, wantErr %v", tt.addr, tt.token, err, tt.wantErr)
+		}
+	}
+	if err := ListenAndServeHTTP(&types.MCPServer{}, HTTPOptions{Addr: "0.0.0.0:0"}); err == nil || !strings.Contains(err.Error(), HTTPTokenEnv) {
+		t.Errorf("ListenAndServeHTTP without token = %v, want refusal", err)
+	}
+}
+
+func TestHTTPNotificationStream(t *testing.T) {
+	handler, url := newTestHTTP(t, HTTPOptions{Token: testToken})
+	id := initSession(t, url)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(sessionHeader, id)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("GET: status %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	handler.mu.Lock()
+	sess := handler.sessions[id]
+	handler.mu.Unlock()
+	sess.server.Notify("notifications/resources/updated", map[string]string{"uri": "git://status"})
+
+	events := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+				events <- data
+				return
+			}
+		}
+	}()
+	select {
+	case data := <-events:
+		var n types.JSONRPCNotification
+		if err := json.Unmarshal([]byte(data), &n); err != nil {
+			t.Fatal(err)
+		}
+		if n.Method != "notifications/resources/updated" {
+			t.Errorf("notification = %+v", n)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("notification not delivered")
+	}
+}
+
+func TestHTTPDeleteSession(t *testing.T) {
+	handler, url := newTestHTTP(t, HTTPOptions{Token: testToken})
+	id := initSession(t, url)
+
+	handler.mu.Lock()
+	sess := handler.sessions[id]
+	handler.mu.Unlock()
+	watchStopped := make(chan struct{})
+	sess.server.Subscribe("git://status", func(ctx context.Context) {
+		<-ctx.Done()
+		close(watchStopped)
+	})
+
+	// Una llamada en curso se cancela al cerrar la sesión
+	callDone := make(chan *http.Response)
+	go func() {
+		req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"test_sleep","arguments":{"block":true}}}`))
+		req.Header.Set("Authorization", "Bearer "+testToken)
+		req.Header.Set(sessionHeader, id)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Error(err)
+		}
+		callDone <- resp
+	}()
+	<-sleepStarted
+
+	req, _ := http.NewRequest(http.MethodDelete, url, nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set(sessionHeader, id)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("DELETE: status %d, want 204", resp.StatusCode)
+	}
+
+	select {
+	case <-watchStopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("DELETE did not close the session's server")
+	}
+	if subs := sess.server.Subscriptions(); len(subs) != 0 {
+		t.Errorf("subscriptions after DELETE = %v", subs)
+	}
+
+	select {
+	case resp := <-callDone:
+		if resp != nil {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if !strings.Contains(string(body), "-32800") {
+				t.Errorf("in-flight call after DELETE = %s, want error -32800", body)
+			}
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("in-flight call not cancelled")
+	}
+
+	if resp := post(t, url, id, `{"jsonrpc":"2.0","id":3,"method":"tools/list"}`, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("request after DELETE: status %d, want 404", resp.StatusCode)
+	}
+}
@@ -0,0 +1,100 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"sync"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/server"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+// maxMessageSize limita el tamaño de una línea JSON-RPC (create_file puede enviar archivos grandes)
+const maxMessageSize = 16 * 1024 * 1024
+
+// lineWriter serializa los mensajes salientes: una línea JSON por mensaje, sin intercalar
+type lineWriter struct {
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+func (lw *lineWriter) write(msg interface{}) {
+	output, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("❌ Error serializando mensaje: %v", err)
+		return
+	}
+
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	lw.w.Write(output)
+	lw.w.WriteByte('\n')
+	lw.w.Flush()
+}
+
+// ServeStdio lee peticiones JSON-RPC línea a línea y las atiende con un pool de workers.
+// Las respuestas pueden salir en distinto orden que las peticiones (se correlacionan por ID),
+// pero todas pasan por un único escritor para no intercalar líneas.
+// Las notificaciones (p. ej. notifications/cancelled) se procesan al momento, sin esperar worker.
+func ServeStdio(s *types.MCPServer, in io.Reader, out io.Writer, workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	// El contexto se cancela al salir, cuando ya han terminado las peticiones leídas
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	writer := &lineWriter{w: bufio.NewWriter(out)}
+	s.Notifier = func(n types.JSONRPCNotification) {
+		writer.write(n)
+	}
+
+	// Pool de workers
+	requests := make(chan types.JSONRPCRequest)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for req := range requests {
+				// Usar el handler del paquete server
+				if resp := server.HandleRequest(ctx, s, req); resp != nil {
+					writer.write(resp)
+				}
+			}
+		}()
+	}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req types.JSONRPCRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			continue
+		}
+
+		if server.IsNotification(req) {
+			server.HandleRequest(ctx, s, req)
+			continue
+		}
+
+		requests <- req
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("❌ Error leyendo stdin: %v", err)
+	}
+
+	// Con stdin cerrado aún se responden las peticiones leídas (p. ej. `echo ... | server`);
+	// los timeouts de las herramientas acotan la espera
+	close(requests)
+	wg.Wait()
+}
@@ -0,0 +1,167 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jotajotape/github-go-server-mcp/internal/server"
+	"github.com/jotajotape/github-go-server-mcp/internal/types"
+)
+
+type sleepArgs struct {
+	Millis int  `json:"millis"`
+	Block  bool `json:"block"` // esperar hasta que se cancele la petición
+}
+
+type sleepResult struct {
+	Slept int `json:"slept"`
+}
+
+// sleepStarted recibe un aviso cada vez que empieza una llamada a test_sleep
+var sleepStarted = make(chan struct{}, 64)
+
+func init() {
+	server.RegisterTool(server.ToolDef[sleepArgs, sleepResult]{
+		Name:        "test_sleep",
+		Description: "Espera el tiempo indicado",
+		Handler: func(ctx context.Context, s *types.MCPServer, args sleepArgs) (sleepResult, error) {
+			sleepStarted <- struct{}{}
+			if args.Block {
+				<-ctx.Done()
+				return sleepResult{}, ctx.Err()
+			}
+			select {
+			case <-time.After(time.Duration(args.Millis) * time.Millisecond):
+				return sleepResult{Slept: args.Millis}, nil
+			case <-ctx.Done():
+				return sleepResult{}, ctx.Err()
+			}
+		},
+	})
+}
+
+// callLine es la línea JSON-RPC de una llamada a test_sleep
+func callLine(id int, args string) string {
+	return fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"test_sleep","arguments":%s}}`, id, args)
+}
+
+// readResponses decodifica las líneas de salida indexadas por ID
+func readResponses(t *testing.T, output []byte) map[int]types.JSONRPCResponse {
+	t.Helper()
+	responses := map[int]types.JSONRPCResponse{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(nil, maxMessageSize)
+	for scanner.Scan() {
+		var resp types.JSONRPCResponse
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid output line %q: %v", scanner.Text(), err)
+		}
+		id, ok := resp.ID.(float64)
+		if !ok {
+			t.Fatalf("response without numeric id: %s", scanner.Text())
+		}
+		if _, dup := responses[int(id)]; dup {
+			t.Errorf("duplicate response for id %d", int(id))
+		}
+		responses[int(id)] = resp
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return responses
+}
+
+// isToolError indica si la respuesta es un resultado de herramienta con isError
+func isToolError(resp types.JSONRPCResponse) bool {
+	data, _ := json.Marshal(resp.Result)
+	var result struct {
+		IsError bool `json:"isError"`
+	}
+	json.Unmarshal(data, &result)
+	return result.IsError
+}
+
+func TestServeStdioAnswersAfterEOF(t *testing.T) {
+	lines := []string{`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`}
+	for id := 2; id <= 7; id++ {
+		lines = append(lines, callLine(id, fmt.Sprintf(`{"millis":%d}`, 20*id)))
+	}
+	lines = append(lines, `{"jsonrpc":"2.0","id":8,"method":"tools/list"}`, `not json`, ``)
+
+	var out bytes.Buffer
+	ServeStdio(&types.MCPServer{}, strings.NewReader(strings.Join(lines, "\n")), &out, 2)
+
+	responses := readResponses(t, out.Bytes())
+	for id := 1; id <= 8; id++ {
+		resp, ok := responses[id]
+		if !ok {
+			t.Errorf("no response for id %d", id)
+			continue
+		}
+		if resp.Error != nil || isToolError(resp) {
+			t.Errorf("id %d failed: %+v", id, resp)
+		}
+	}
+	if len(responses) != 8 {
+		t.Errorf("got %d responses, want 8", len(responses))
+	}
+}
+
+func TestServeStdioConcurrent(t *testing.T) {
+	const workers, delay = 4, 300
+	var lines []string
+	for id := 1; id <= workers; id++ {
+		lines = append(lines, callLine(id, fmt.Sprintf(`{"millis":%d}`, delay)))
+	}
+
+	var out bytes.Buffer
+	start := time.Now()
+	ServeStdio(&types.MCPServer{}, strings.NewReader(strings.Join(lines, "\n")), &out, workers)
+
+	// En serie tardaría workers*delay
+	if elapsed := time.Since(start); elapsed >= time.Duration(workers-1)*delay*time.Millisecond {
+		t.Errorf("%d calls of %dms took %v; requests are not served in parallel", workers, delay, elapsed)
+	}
+	if got := len(readResponses(t, out.Bytes())); got != workers {
+		t.Errorf("got %d responses, want %d", got, workers)
+	}
+}
+
+func TestServeStdioCancelled(t *testing.T) {
+	in, input := io.Pipe()
+	var out bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		ServeStdio(&types.MCPServer{}, in, &out, 2)
+		close(done)
+	}()
+
+	fmt.Fprintln(input, callLine(1, `{"block":true}`))
+	fmt.Fprintln(input, callLine(2, `{"millis":10}`))
+	<-sleepStarted
+	<-sleepStarted
+	// La notificación no espera a un worker libre
+	fmt.Fprintln(input, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":1,"reason":"test"}}`)
+	input.Close()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ServeStdio did not return after the cancellation")
+	}
+
+	responses := readResponses(t, out.Bytes())
+	if resp := responses[1]; resp.Error == nil || resp.Error.Code != -32800 {
+		t.Errorf("cancelled request: %+v, want error -32800", resp)
+	}
+	if resp, ok := responses[2]; !ok || resp.Error != nil || isToolError(resp) {
+		t.Errorf("other request: %+v", resp)
+	}
+}
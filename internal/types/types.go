@@ -1,11 +1,188 @@
 package types
 
-import "github.com/google/go-github/v66/github"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
 
 // MCPServer representa el servidor MCP principal
 type MCPServer struct {
 	GithubClient *github.Client
 	GitConfig    GitConfig
+
+	// DefaultTimeout es el tiempo máximo de una herramienta (0 = sin límite)
+	DefaultTimeout time.Duration
+	// ToolTimeouts sobreescribe DefaultTimeout para herramientas concretas
+	ToolTimeouts map[string]time.Duration
+
+	// Defaults son los valores por defecto del perfil para las herramientas de GitHub
+	Defaults RepoDefaults
+	// ToolFilter indica qué herramientas habilita el perfil (nil = todas)
+	ToolFilter func(name string) bool
+
+	// Notifier envía mensajes iniciados por el servidor al cliente (lo configura el transporte)
+	Notifier func(JSONRPCNotification)
+
+	// gitMu protege GitConfig, ya que las peticiones se atienden en paralelo
+	gitMu sync.RWMutex
+
+	// inFlight guarda la función de cancelación de cada petición en curso, por ID
+	inFlightMu sync.Mutex
+	inFlight   map[string]context.CancelFunc
+
+	// subscriptions son las URIs suscritas con resources/subscribe; stopWatch detiene su vigilancia
+	subsMu        sync.Mutex
+	subscriptions map[string]bool
+	stopWatch     context.CancelFunc
+}
+
+// Subscribe añade una suscripción a un recurso. Con la primera suscripción se
+// lanza watch en segundo plano; su contexto se cancela al quedar sin suscripciones.
+func (s *MCPServer) Subscribe(uri string, watch func(ctx context.Context)) {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+
+	if s.subscriptions == nil {
+		s.subscriptions = make(map[string]bool)
+	}
+	s.subscriptions[uri] = true
+
+	if s.stopWatch == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.stopWatch = cancel
+		go watch(ctx)
+	}
+}
+
+// Unsubscribe elimina una suscripción y detiene la vigilancia si era la última
+func (s *MCPServer) Unsubscribe(uri string) {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+
+	delete(s.subscriptions, uri)
+	if len(s.subscriptions) == 0 && s.stopWatch != nil {
+		s.stopWatch()
+		s.stopWatch = nil
+	}
+}
+
+// Subscriptions devuelve las URIs suscritas actualmente
+func (s *MCPServer) Subscriptions() []string {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+
+	uris := make([]string, 0, len(s.subscriptions))
+	for uri := range s.subscriptions {
+		uris = append(uris, uri)
+	}
+	return uris
+}
+
+// Close libera los recursos en segundo plano del servidor (p. ej. al cerrar una sesión HTTP)
+func (s *MCPServer) Close() {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+
+	s.subscriptions = nil
+	if s.stopWatch != nil {
+		s.stopWatch()
+		s.stopWatch = nil
+	}
+}
+
+// ToolEnabled indica si la herramienta está habilitada en el perfil activo
+func (s *MCPServer) ToolEnabled(name string) bool {
+	return s.ToolFilter == nil || s.ToolFilter(name)
+}
+
+// ToolTimeout devuelve el tiempo máximo configurado para una herramienta
+func (s *MCPServer) ToolTimeout(name string) time.Duration {
+	if timeout, ok := s.ToolTimeouts[name]; ok {
+		return timeout
+	}
+	return s.DefaultTimeout
+}
+
+// TrackRequest registra una petición en curso y devuelve la función para desregistrarla
+func (s *MCPServer) TrackRequest(id interface{}, cancel context.CancelFunc) func() {
+	key := fmt.Sprint(id)
+
+	s.inFlightMu.Lock()
+	if s.inFlight == nil {
+		s.inFlight = make(map[string]context.CancelFunc)
+	}
+	s.inFlight[key] = cancel
+	s.inFlightMu.Unlock()
+
+	return func() {
+		s.inFlightMu.Lock()
+		delete(s.inFlight, key)
+		s.inFlightMu.Unlock()
+	}
+}
+
+// CancelRequest cancela una petición en curso. Devuelve false si ya no existe.
+func (s *MCPServer) CancelRequest(id interface{}) bool {
+	s.inFlightMu.Lock()
+	cancel, ok := s.inFlight[fmt.Sprint(id)]
+	s.inFlightMu.Unlock()
+
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// Clone crea un servidor independiente (p. ej. una sesión HTTP) que comparte el cliente
+// de GitHub pero tiene su propia configuración Git y sus propias peticiones en curso
+func (s *MCPServer) Clone() *MCPServer {
+	return &MCPServer{
+		GithubClient:   s.GithubClient,
+		GitConfig:      s.GitSnapshot(),
+		DefaultTimeout: s.DefaultTimeout,
+		ToolTimeouts:   s.ToolTimeouts,
+		Defaults:       s.Defaults,
+		ToolFilter:     s.ToolFilter,
+	}
+}
+
+// Notify envía una notificación al cliente si el transporte lo permite
+func (s *MCPServer) Notify(method string, params interface{}) {
+	if s.Notifier == nil {
+		return
+	}
+	s.Notifier(JSONRPCNotification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+}
+
+// GitSnapshot devuelve una copia de la configuración Git actual
+func (s *MCPServer) GitSnapshot() GitConfig {
+	s.gitMu.RLock()
+	defer s.gitMu.RUnlock()
+	return s.GitConfig
+}
+
+// UpdateGitConfig modifica la configuración Git bajo bloqueo exclusivo.
+// Las operaciones que cambian el workspace o la rama se serializan entre sí.
+func (s *MCPServer) UpdateGitConfig(fn func(config *GitConfig) error) error {
+	s.gitMu.Lock()
+	defer s.gitMu.Unlock()
+	return fn(&s.GitConfig)
+}
+
+// RepoDefaults son el owner, repositorio y rama base usados cuando una herramienta no los recibe
+type RepoDefaults struct {
+	Owner      string
+	Repo       string
+	BaseBranch string
 }
 
 // GitConfig contiene la configuración del entorno Git local
@@ -15,7 +192,12 @@ type GitConfig struct {
 	RemoteURL     string `json:"remoteURL"`
 	CurrentBranch string `json:"currentBranch"`
 	IsGitRepo     bool   `json:"isGitRepo"`
-	WorkspacePath string `json:"workspacePath"` // Directorio de trabajo configurado manualmente
+	WorkspacePath string `json:"workspacePath"`        // Directorio de trabajo configurado manualmente
+	GitHubHost    string `json:"githubHost"`           // Host de GitHub del perfil (github.com o GitHub Enterprise)
+	RemoteName    string `json:"remoteName"`           // Remoto del que se deducen owner/repo (origin, upstream...)
+	RemoteHost    string `json:"remoteHost,omitempty"` // Host, owner y repo extraídos de RemoteURL
+	Owner         string `json:"owner,omitempty"`
+	Repo          string `json:"repo,omitempty"`
 }
 
 // Estructuras del protocolo JSON-RPC 2.0
@@ -33,6 +215,13 @@ type JSONRPCResponse struct {
 	Error   *JSONRPCError `json:"error,omitempty"`
 }
 
+// JSONRPCNotification es un mensaje sin ID enviado por el servidor
+type JSONRPCNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
 type JSONRPCError struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
@@ -41,9 +230,21 @@ type JSONRPCError struct {
 
 // Estructuras MCP para herramientas
 type Tool struct {
-	Name        string          `json:"name"`
-	Description string          `json:"description"`
-	InputSchema ToolInputSchema `json:"inputSchema"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description"`
+	InputSchema  ToolInputSchema  `json:"inputSchema"`
+	OutputSchema *ToolInputSchema `json:"outputSchema,omitempty"`
+	Annotations  *ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations describe el comportamiento de una herramienta para que el cliente
+// decida cuándo pedir confirmación. Son indicaciones, no garantías.
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    bool   `json:"readOnlyHint"`    // no modifica nada
+	DestructiveHint bool   `json:"destructiveHint"` // puede borrar o sobrescribir datos
+	IdempotentHint  bool   `json:"idempotentHint"`  // repetir la llamada no tiene efecto adicional
+	OpenWorldHint   bool   `json:"openWorldHint"`   // interactúa con sistemas externos (remotos, GitHub)
 }
 
 type ToolInputSchema struct {
@@ -52,9 +253,31 @@ type ToolInputSchema struct {
 	Required   []string            `json:"required,omitempty"`
 }
 
+// Property es el JSON Schema de un argumento. Admite enumeraciones, valores por
+// defecto, arrays (Items), límites numéricos, patrones y objetos anidados.
 type Property struct {
-	Type        string `json:"type"`
-	Description string `json:"description"`
+	Type        string              `json:"type"`
+	Description string              `json:"description,omitempty"`
+	Enum        []interface{}       `json:"enum,omitempty"`
+	Default     interface{}         `json:"default,omitempty"`
+	Items       *Property           `json:"items,omitempty"`
+	Minimum     *float64            `json:"minimum,omitempty"`
+	Maximum     *float64            `json:"maximum,omitempty"`
+	Pattern     string              `json:"pattern,omitempty"`
+	Properties  map[string]Property `json:"properties,omitempty"`
+	Required    []string            `json:"required,omitempty"`
+	Nullable    bool                `json:"-"` // el valor puede ser null: type se emite como [Type, "null"]
+}
+
+func (p Property) MarshalJSON() ([]byte, error) {
+	type plain Property
+	if !p.Nullable {
+		return json.Marshal(plain(p))
+	}
+	return json.Marshal(struct {
+		Type []string `json:"type"`
+		plain
+	}{[]string{p.Type, "null"}, plain(p)})
 }
 
 type ToolsListResult struct {
@@ -62,10 +285,74 @@ type ToolsListResult struct {
 }
 
 type ToolCallResult struct {
-	Content []Content `json:"content"`
+	Content           []Content   `json:"content"`
+	StructuredContent interface{} `json:"structuredContent,omitempty"`
+	IsError           bool        `json:"isError,omitempty"`
 }
 
 type Content struct {
 	Type string `json:"type"`
 	Text string `json:"text"`
 }
+
+// Estructuras MCP para recursos
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+type ResourcesListResult struct {
+	Resources  []Resource `json:"resources"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
+
+type ResourceTemplatesListResult struct {
+	ResourceTemplates []ResourceTemplate `json:"resourceTemplates"`
+}
+
+type ResourceReadResult struct {
+	Contents []ResourceContents `json:"contents"`
+}
+
+// Estructuras MCP para prompts
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+type PromptMessage struct {
+	Role    string  `json:"role"`
+	Content Content `json:"content"`
+}
+
+type PromptsListResult struct {
+	Prompts []Prompt `json:"prompts"`
+}
+
+type PromptGetResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
@@ -1,84 +1,240 @@
 package main
 
 import (
-	"bufio"
 	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"time"
 
-	"github.com/google/go-github/v66/github"
-	"golang.org/x/oauth2"
-
+	"github.com/jotajotape/github-go-server-mcp/internal/config"
 	"github.com/jotajotape/github-go-server-mcp/internal/git"
+	githubapi "github.com/jotajotape/github-go-server-mcp/internal/github"
 	"github.com/jotajotape/github-go-server-mcp/internal/server"
+	"github.com/jotajotape/github-go-server-mcp/internal/transport"
 	"github.com/jotajotape/github-go-server-mcp/internal/types"
 )
 
 func main() {
 	// Configuración de perfiles
-	profile := flag.String("profile", "default", "Profile name for this MCP instance")
+	profile := flag.String("profile", "", "Profile name for this MCP instance (default: default_profile from the config file)")
+	configPath := flag.String("config", "", "Config file with named profiles (default: "+config.DefaultPath()+" if it exists)")
+	doctor := flag.Bool("doctor", false, "Validate the config file and exit")
+	workers := flag.Int("workers", 8, "Maximum number of requests processed concurrently")
+	timeout := flag.Duration("timeout", 2*time.Minute, "Default timeout per tool call (0 disables it)")
+	toolTimeouts := flag.String("tool-timeouts", "git_push=10m,git_pull=10m", "Per-tool timeouts, e.g. git_push=10m,github_list_repos=30s")
+	transportName := flag.String("transport", "stdio", "Transport: stdio or http (Streamable HTTP)")
+	listen := flag.String("listen", "127.0.0.1:8080", "Listen address for the http transport")
+	allowedOrigins := flag.String("allowed-origins", "", "Comma-separated browser origins allowed by the http transport (default: localhost only)")
+	promptsDir := flag.String("prompts-dir", "", "Directory with custom prompt templates (*.json)")
 	flag.Parse()
 
-	log.Printf("🚀 Starting GitHub MCP Server with profile: %s", *profile)
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *doctor {
+		os.Exit(runDoctor(cfg))
+	}
 
-	mcpServer, err := NewMCPServer(*profile)
+	profileName, profileConfig, err := selectProfile(cfg, *profile)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
-			continue
+	log.Printf("🚀 Starting GitHub MCP Server with profile: %s", profileName)
+
+	if cfg != nil {
+		for _, problem := range cfg.Check(server.ToolNames()) {
+			log.Printf("⚠️ Config: %s", problem)
+		}
+	}
+
+	mcpServer, err := NewMCPServer(profileName, profileConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mcpServer.DefaultTimeout = *timeout
+	mcpServer.ToolTimeouts, err = parseToolTimeouts(*toolTimeouts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *promptsDir != "" {
+		count, err := server.LoadPromptDir(*promptsDir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("💬 %d custom prompts loaded from %s", count, *promptsDir)
+	}
+
+	switch *transportName {
+	case "stdio":
+		transport.ServeStdio(mcpServer, os.Stdin, os.Stdout, *workers)
+	case "http":
+		opts := transport.HTTPOptions{
+			Addr:    *listen,
+			Token:   os.Getenv(transport.HTTPTokenEnv),
+			Workers: *workers,
+		}
+		if *allowedOrigins != "" {
+			opts.AllowedOrigins = strings.Split(*allowedOrigins, ",")
 		}
+		log.Printf("🌐 Streamable HTTP transport listening on %s%s", *listen, transport.HTTPEndpoint)
+		if err := transport.ListenAndServeHTTP(mcpServer, opts); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		log.Fatalf("unknown transport: %s (use stdio or http)", *transportName)
+	}
+}
 
-		var req types.JSONRPCRequest
-		if err := json.Unmarshal([]byte(line), &req); err != nil {
+// parseToolTimeouts interpreta la lista "herramienta=duración,..." del flag --tool-timeouts
+func parseToolTimeouts(spec string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
 			continue
 		}
 
-		// Usar el handler del paquete server
-		resp := server.HandleRequest(mcpServer, req)
-		output, err := json.Marshal(resp)
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid tool timeout %q: expected name=duration", entry)
+		}
+
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
 		if err != nil {
-			continue
+			return nil, fmt.Errorf("invalid tool timeout %q: %v", entry, err)
+		}
+		timeouts[strings.TrimSpace(name)] = timeout
+	}
+	return timeouts, nil
+}
+
+// loadConfig carga el archivo de configuración. Sin --config se usa la ruta por
+// defecto si existe; sin archivo se devuelve nil y se usa GITHUB_TOKEN como antes.
+func loadConfig(path string) (*config.Config, error) {
+	if path != "" {
+		return config.Load(path)
+	}
+
+	path = config.DefaultPath()
+	if path == "" {
+		return nil, nil
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	log.Printf("⚙️ Using config file: %s", path)
+	return config.Load(path)
+}
+
+// selectProfile elige el perfil de --profile, el default_profile del archivo o "default"
+func selectProfile(cfg *config.Config, name string) (string, config.Profile, error) {
+	if cfg == nil {
+		if name == "" {
+			name = "default"
 		}
-		
-		fmt.Println(string(output))
+		return name, config.Profile{}, nil
+	}
+
+	if name == "" {
+		name = cfg.DefaultProfile
+	}
+	if name == "" {
+		name = "default"
+	}
+
+	profile, err := cfg.Profile(name)
+	return name, profile, err
+}
+
+// runDoctor valida todos los perfiles del archivo de configuración y devuelve el código de salida
+func runDoctor(cfg *config.Config) int {
+	if cfg == nil {
+		log.Printf("❌ No config file found (use --config or create %s)", config.DefaultPath())
+		return 1
+	}
+
+	problems := cfg.Check(server.ToolNames())
+	for _, problem := range problems {
+		log.Printf("❌ %s", problem)
+	}
+
+	for _, name := range cfg.ProfileNames() {
+		log.Printf("📋 Profile: %s | Auth: %s", name, cfg.Profiles[name].DescribeAuth())
+	}
+
+	if len(problems) > 0 {
+		log.Printf("❌ %d problem(s) found", len(problems))
+		return 1
 	}
+	log.Printf("✅ Config OK: %d profile(s)", len(cfg.Profiles))
+	return 0
 }
 
-func NewMCPServer(profile string) (*types.MCPServer, error) {
-	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" {
-		return nil, fmt.Errorf("GITHUB_TOKEN required for profile: %s", profile)
+func NewMCPServer(profile string, profileConfig config.Profile) (*types.MCPServer, error) {
+	tokenSource, err := profileConfig.TokenSource()
+	if err != nil {
+		return nil, fmt.Errorf("authentication required for profile %s (%s): %v", profile, profileConfig.DescribeAuth(), err)
 	}
 
 	// Log del perfil y token (solo primeros 7 caracteres por seguridad)
-	tokenPreview := "***"
-	if len(token) >= 7 {
-		tokenPreview = token[:7] + "***"
+	if token, ok := tokenSource.(githubapi.StaticToken); ok {
+		tokenPreview := "***"
+		if len(token) >= 7 {
+			tokenPreview = string(token[:7]) + "***"
+		}
+		log.Printf("📋 Profile: %s | Token: %s (%s)", profile, tokenPreview, profileConfig.DescribeAuth())
+	} else {
+		log.Printf("📋 Profile: %s | Auth: %s", profile, profileConfig.DescribeAuth())
 	}
-	log.Printf("📋 Profile: %s | Token: %s", profile, tokenPreview)
 
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(context.Background(), ts)
-	githubClient := github.NewClient(tc)
+	clientOptions := profileConfig.ClientOptions()
+	githubClient, err := githubapi.NewClient(tokenSource, clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub connection settings for profile %s: %v", profile, err)
+	}
+	if clientOptions.BaseURL != "" {
+		log.Printf("🏢 GitHub Enterprise API: %s (uploads: %s)", githubClient.BaseURL, githubClient.UploadURL)
+	}
 
 	// Detectar entorno Git
-	gitConfig := git.DetectGitEnvironment()
+	gitConfig := git.DetectGitEnvironment(clientOptions.Host(), profileConfig.Remote)
+	if workspace := profileConfig.WorkspacePath(); workspace != "" {
+		if _, err := git.SetWorkspace(context.Background(), &gitConfig, workspace, ""); err != nil {
+			return nil, fmt.Errorf("invalid workspace for profile %s: %v", profile, err)
+		}
+	}
 	
 	// Agregar perfil al gitConfig para logging
 	if gitConfig.HasGit {
 		log.Printf("🔧 Git environment detected for profile: %s", profile)
 	}
+	if gitConfig.Owner != "" {
+		log.Printf("📦 Repository: %s/%s (remote %s on %s)", gitConfig.Owner, gitConfig.Repo, gitConfig.RemoteName, gitConfig.RemoteHost)
+		if _, _, ok := git.RemoteRepository(gitConfig); !ok {
+			log.Printf("⚠️ Remote %s is not on %s: owner/repo will not be inferred from it", gitConfig.RemoteName, gitConfig.GitHubHost)
+		}
+	}
 
-	return &types.MCPServer{
+	mcpServer := &types.MCPServer{
 		GithubClient: githubClient,
 		GitConfig:    gitConfig,
-	}, nil
+		Defaults: types.RepoDefaults{
+			Owner:      profileConfig.Owner,
+			Repo:       profileConfig.Repo,
+			BaseBranch: profileConfig.BaseBranch,
+		},
+	}
+	if len(profileConfig.Tools) > 0 {
+		mcpServer.ToolFilter = profileConfig.ToolEnabled
+		log.Printf("🧰 Tools enabled for profile %s: %s", profile, strings.Join(profileConfig.Tools, ", "))
+	}
+	return mcpServer, nil
 }